@@ -80,7 +80,7 @@ func (b *BlankArchiveBuilder) Build(ctx context.Context, zone string) (*iaas.Arc
 	ftpsClient := ftps.NewClient(ftpServer.User, ftpServer.Password, ftpServer.HostName)
 
 	if err := ftpsClient.UploadReader("data.raw", b.SourceReader); err != nil {
-		return archive, fmt.Errorf("uploading file via FTPS is failed: %s", err)
+		return archive, fmt.Errorf("uploading file via FTPS is failed: %w", err)
 	}
 
 	// close FTP
@@ -21,6 +21,7 @@ import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/query"
 	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/progress"
 )
 
 // FromSharedArchiveBuilder 共有アーカイブからアーカイブの作成を行う
@@ -75,13 +76,18 @@ func (b *FromSharedArchiveBuilder) Build(ctx context.Context, zone string) (*iaa
 		return nil, err
 	}
 
+	resource := progress.Resource{Kind: "Archive", Zone: zone, ID: archive.ID}
+	progress.Emit(ctx, &progress.ResourceCreated{Resource: resource})
+
 	if b.NoWait {
 		return archive, nil
 	}
 
-	lastState, err := iaas.WaiterForReady(func() (interface{}, error) {
+	done := progress.StartPhase(ctx, resource, progress.PhaseWaitForCopy)
+	lastState, err := iaas.WaiterForReady(progress.ObserveState(ctx, resource, func() (interface{}, error) {
 		return b.Client.Archive.Read(ctx, zone, archive.ID)
-	}).WaitForState(ctx)
+	})).WaitForState(ctx)
+	done(err)
 
 	var ret *iaas.Archive
 	if lastState != nil {
@@ -20,6 +20,7 @@ import (
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/progress"
 )
 
 // StandardArchiveBuilder 同一アカウント/同一ゾーンのディスク/アーカイブからアーカイブの作成を行う
@@ -68,13 +69,18 @@ func (b *StandardArchiveBuilder) Build(ctx context.Context, zone string) (*iaas.
 		return nil, err
 	}
 
+	resource := progress.Resource{Kind: "Archive", Zone: zone, ID: archive.ID}
+	progress.Emit(ctx, &progress.ResourceCreated{Resource: resource})
+
 	if b.NoWait {
 		return archive, nil
 	}
 
-	lastState, err := iaas.WaiterForReady(func() (interface{}, error) {
+	done := progress.StartPhase(ctx, resource, progress.PhaseWaitForCopy)
+	lastState, err := iaas.WaiterForReady(progress.ObserveState(ctx, resource, func() (interface{}, error) {
 		return b.Client.Archive.Read(ctx, zone, archive.ID)
-	}).WaitForState(ctx)
+	})).WaitForState(ctx)
+	done(err)
 
 	var ret *iaas.Archive
 	if lastState != nil {
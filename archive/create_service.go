@@ -16,11 +16,11 @@ package archive
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"os"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	builder2 "github.com/sacloud/iaas-service-go/archive/builder"
 )
 
@@ -28,9 +28,12 @@ func (s *Service) Create(req *CreateRequest) (*iaas.Archive, error) {
 	return s.CreateWithContext(context.Background(), req)
 }
 
-func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (*iaas.Archive, error) {
+func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (_ *iaas.Archive, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Create", Resource: "Archive", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Create", Resource: "Archive", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	var reader io.Reader
@@ -40,7 +43,7 @@ func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (*i
 	default:
 		file, err := os.Open(req.SourcePath)
 		if err != nil {
-			return nil, fmt.Errorf("reading source file[%s] failed: %s", req.SourcePath, err)
+			return nil, &service.Error{Op: "Create", Resource: "Archive", Zone: req.Zone, Err: err}
 		}
 		defer file.Close()
 		reader = file
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) Delete(req *DeleteRequest) error {
 	return s.DeleteWithContext(context.Background(), req)
 }
 
-func (s *Service) DeleteWithContext(ctx context.Context, req *DeleteRequest) error {
+func (s *Service) DeleteWithContext(ctx context.Context, req *DeleteRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Delete", Resource: "Archive", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "Delete", Resource: "Archive", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewArchiveOp(s.caller)
@@ -16,6 +16,7 @@ package archive
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -23,30 +24,34 @@ import (
 	"github.com/sacloud/ftps"
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Download(req *DownloadRequest) error {
 	return s.DownloadWithContext(context.Background(), req)
 }
 
-func (s *Service) DownloadWithContext(ctx context.Context, req *DownloadRequest) error {
+func (s *Service) DownloadWithContext(ctx context.Context, req *DownloadRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Download", Resource: "Archive", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "Download", Resource: "Archive", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewArchiveOp(s.caller)
 	resource, err := client.Read(ctx, req.Zone, req.ID)
 	if err != nil {
-		return fmt.Errorf("reading Archive[%s] failed: %s", req.ID, err)
+		return &service.Error{Op: "Download", Resource: "Archive", Zone: req.Zone, ID: req.ID, Err: err}
 	}
 
 	if resource.Scope != types.Scopes.User {
-		return fmt.Errorf("archive[%s] is not allowed to download", req.ID)
+		return &service.Error{Op: "Download", Resource: "Archive", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindConflict, Err: errors.New("archive is not allowed to download")}
 	}
 
 	ftpServer, err := client.OpenFTP(ctx, req.Zone, req.ID, &iaas.OpenFTPRequest{ChangePassword: true})
 	if err != nil {
-		return fmt.Errorf("requesting FTP server information failed: %s", err)
+		return &service.Error{Op: "Download", Resource: "Archive", Zone: req.Zone, ID: req.ID, Err: fmt.Errorf("requesting FTP server information failed: %w", err)}
 	}
 
 	ftpsClient := ftps.NewClient(ftpServer.User, ftpServer.Password, ftpServer.HostName)
@@ -57,17 +62,17 @@ func (s *Service) DownloadWithContext(ctx context.Context, req *DownloadRequest)
 			out = req.Writer
 		}
 		if err := ftpsClient.DownloadWriter(out); err != nil {
-			return fmt.Errorf("downloading via FTP failed: %s", err)
+			return &service.Error{Op: "Download", Resource: "Archive", Zone: req.Zone, ID: req.ID, Err: fmt.Errorf("downloading via FTP failed: %w", err)}
 		}
 	default:
 		if err := ftpsClient.Download(req.Path); err != nil {
-			return fmt.Errorf("downloading via FTP failed: %s", err)
+			return &service.Error{Op: "Download", Resource: "Archive", Zone: req.Zone, ID: req.ID, Err: fmt.Errorf("downloading via FTP failed: %w", err)}
 		}
 	}
 
 	// close
 	if err := client.CloseFTP(ctx, req.Zone, req.ID); err != nil {
-		return fmt.Errorf("closing FTP server failed: %s", err)
+		return &service.Error{Op: "Download", Resource: "Archive", Zone: req.Zone, ID: req.ID, Err: fmt.Errorf("closing FTP server failed: %w", err)}
 	}
 	return nil
 }
@@ -0,0 +1,38 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import "github.com/sacloud/packages-go/validate"
+
+// FindAllZonesRequest 複数ゾーンを対象とした検索リクエスト
+type FindAllZonesRequest struct {
+	// Zones 検索対象のゾーン、空の場合は全ゾーンが対象
+	Zones []string `validate:"omitempty,dive,required"`
+	// Parallelism 同時に検索するゾーン数、0の場合はmultizone.DefaultParallelism
+	Parallelism int `validate:"min=0"`
+
+	// FindRequest 各ゾーンでの検索条件、Zoneは無視される
+	FindRequest `validate:"-"`
+}
+
+func (req *FindAllZonesRequest) Validate() error {
+	return validate.New().Struct(req)
+}
+
+func (req *FindAllZonesRequest) findRequest(zone string) *FindRequest {
+	findReq := req.FindRequest
+	findReq.Zone = zone
+	return &findReq
+}
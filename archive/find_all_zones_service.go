@@ -0,0 +1,43 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package archive
+
+import (
+	"context"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/multizone"
+)
+
+// FindAllZones 複数ゾーンを並列に検索する
+//
+// 一部のゾーンで検索に失敗した場合、成功したゾーンの結果と*multizone.Errorを返す
+func (s *Service) FindAllZones(req *FindAllZonesRequest) ([]*multizone.Result[*iaas.Archive], error) {
+	return s.FindAllZonesWithContext(context.Background(), req)
+}
+
+func (s *Service) FindAllZonesWithContext(ctx context.Context, req *FindAllZonesRequest) (_ []*multizone.Result[*iaas.Archive], err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "FindAllZones", Resource: "Archive", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "FindAllZones", Resource: "Archive", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	return multizone.Find(ctx, s.caller, req.Zones, req.Parallelism, func(ctx context.Context, zone string) ([]*iaas.Archive, error) {
+		return s.FindWithContext(ctx, req.findRequest(zone))
+	})
+}
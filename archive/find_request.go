@@ -19,6 +19,7 @@ import (
 	"github.com/sacloud/iaas-api-go/ostype"
 	"github.com/sacloud/iaas-api-go/search"
 	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -34,6 +35,8 @@ type FindRequest struct {
 	Tags  []string     `service:"-"`
 	Scope types.EScope `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -51,9 +54,9 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 		return nil, err
 	}
 
-	filter, ok := ostype.ArchiveCriteria[req.OSType]
+	criteria, ok := ostype.ArchiveCriteria[req.OSType]
 	if ok {
-		for k, v := range filter {
+		for k, v := range criteria {
 			condition.Filter[k] = v
 		}
 	}
@@ -66,5 +69,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.Scope) {
 		condition.Filter[search.Key("Scope")] = search.OrEqual(req.Scope)
 	}
+	filter.SetCondition[*iaas.Archive](&req.Filter, condition)
 	return condition, nil
 }
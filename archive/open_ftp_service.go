@@ -18,16 +18,21 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) OpenFTP(req *OpenFTPRequest) (*iaas.FTPServer, error) {
 	return s.OpenFTPWithContext(context.Background(), req)
 }
 
-func (s *Service) OpenFTPWithContext(ctx context.Context, req *OpenFTPRequest) (*iaas.FTPServer, error) {
+func (s *Service) OpenFTPWithContext(ctx context.Context, req *OpenFTPRequest) (_ *iaas.FTPServer, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "OpenFTP", Resource: "Archive", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "OpenFTP", Resource: "Archive", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
+
 	client := iaas.NewArchiveOp(s.caller)
 	return client.OpenFTP(ctx, req.Zone, req.ID, &iaas.OpenFTPRequest{ChangePassword: req.ChangePassword})
 }
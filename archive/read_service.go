@@ -18,16 +18,21 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Read(req *ReadRequest) (*iaas.Archive, error) {
 	return s.ReadWithContext(context.Background(), req)
 }
 
-func (s *Service) ReadWithContext(ctx context.Context, req *ReadRequest) (*iaas.Archive, error) {
+func (s *Service) ReadWithContext(ctx context.Context, req *ReadRequest) (_ *iaas.Archive, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Read", Resource: "Archive", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Read", Resource: "Archive", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
+
 	client := iaas.NewArchiveOp(s.caller)
 	return client.Read(ctx, req.Zone, req.ID)
 }
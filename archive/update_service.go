@@ -16,29 +16,32 @@ package archive
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Update(req *UpdateRequest) (*iaas.Archive, error) {
 	return s.UpdateWithContext(context.Background(), req)
 }
 
-func (s *Service) UpdateWithContext(ctx context.Context, req *UpdateRequest) (*iaas.Archive, error) {
+func (s *Service) UpdateWithContext(ctx context.Context, req *UpdateRequest) (_ *iaas.Archive, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Update", Resource: "Archive", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Update", Resource: "Archive", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewArchiveOp(s.caller)
 	current, err := client.Read(ctx, req.Zone, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("reading Archive[%s] failed: %s", req.ID, err)
+		return nil, &service.Error{Op: "Update", Resource: "Archive", Zone: req.Zone, ID: req.ID, Err: err}
 	}
 
 	params, err := req.ToRequestParameter(current)
 	if err != nil {
-		return nil, fmt.Errorf("processing request parameter failed: %s", err)
+		return nil, &service.Error{Op: "Update", Resource: "Archive", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	return client.Update(ctx, req.Zone, req.ID, params)
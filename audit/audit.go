@@ -0,0 +1,205 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"reflect"
+	"time"
+
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// RecordType 監査ログのレコード種別
+type RecordType string
+
+const (
+	// RecordTypeOperation サービスの操作(Apply/Createなど)のレコード
+	RecordTypeOperation RecordType = "operation"
+	// RecordTypeAPICall 操作の中で行われたAPI呼び出しのレコード
+	RecordTypeAPICall RecordType = "api"
+)
+
+// Record 監査ログのレコード
+type Record struct {
+	Time          time.Time
+	Type          RecordType
+	CorrelationID string `json:",omitempty"` // 同じ操作から発生したレコードに共通のID
+	Operation     string `json:",omitempty"` // 操作名(例: server.Apply)
+
+	Request interface{} `json:",omitempty"` // 操作のリクエスト、パスワードなどの値はマスクされる
+	Method  string      `json:",omitempty"` // API呼び出しのHTTPメソッド
+	URL     string      `json:",omitempty"` // API呼び出しのURL
+
+	Zone       string   `json:",omitempty"`
+	ResourceID types.ID `json:",omitempty"`
+
+	Duration time.Duration
+	Error    string `json:",omitempty"` // 失敗した場合のエラーメッセージ
+}
+
+// Succeeded 操作/API呼び出しが成功したか
+func (r *Record) Succeeded() bool {
+	return r.Error == ""
+}
+
+// Auditor 監査ログの出力を行う
+type Auditor struct {
+	Sink Sink
+	// NewCorrelationID 相関IDの生成に用いる、省略時はランダムな文字列を生成する
+	NewCorrelationID func() string
+	// ErrorHandler Sinkへの出力に失敗した場合に呼ばれる、省略時は無視される
+	ErrorHandler func(err error)
+}
+
+func (a *Auditor) write(ctx context.Context, r *Record) {
+	if a == nil || a.Sink == nil {
+		return
+	}
+	if err := a.Sink.Write(ctx, r); err != nil && a.ErrorHandler != nil {
+		a.ErrorHandler(err)
+	}
+}
+
+func (a *Auditor) newCorrelationID() string {
+	if a.NewCorrelationID != nil {
+		return a.NewCorrelationID()
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+type operationContextKey struct{}
+
+type operation struct {
+	name          string
+	correlationID string
+}
+
+// WithCorrelationID ctxへ相関IDを設定する
+//
+// Doで開始される操作は、ctxに相関IDが設定されている場合はそれを引き継ぐ。
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	op := operationFromContext(ctx)
+	return context.WithValue(ctx, operationContextKey{}, &operation{name: op.name, correlationID: correlationID})
+}
+
+// CorrelationID ctxに設定された相関IDを返す
+func CorrelationID(ctx context.Context) string {
+	return operationFromContext(ctx).correlationID
+}
+
+func operationFromContext(ctx context.Context) *operation {
+	if op, ok := ctx.Value(operationContextKey{}).(*operation); ok {
+		return op
+	}
+	return &operation{}
+}
+
+// Do 操作を実行し、その結果を監査ログへ出力する
+//
+// fnに渡されるctxには操作名と相関IDが設定されており、
+// Auditor.Callerでラップしたcallerを用いたAPI呼び出しやサービスの操作のレコードにも引き継がれる。
+// サービスの操作はAuditor.Callerを渡すだけで記録されるため、Doは複数の操作を1つの相関IDでまとめる場合や
+// サービスを経由しない処理を記録する場合に利用する。
+//
+// 例:
+//
+//	caller := auditor.Caller(rawCaller)
+//	server, err := audit.Do(ctx, auditor, "server.Apply", req, func(ctx context.Context) (*iaas.Server, error) {
+//		return server.New(caller).ApplyWithContext(ctx, req)
+//	})
+func Do[T any](ctx context.Context, auditor *Auditor, name string, req interface{}, fn func(ctx context.Context) (T, error)) (T, error) {
+	correlationID := CorrelationID(ctx)
+	if correlationID == "" && auditor != nil {
+		correlationID = auditor.newCorrelationID()
+	}
+	ctx = context.WithValue(ctx, operationContextKey{}, &operation{name: name, correlationID: correlationID})
+
+	started := time.Now()
+	result, err := fn(ctx)
+
+	record := &Record{
+		Time:          started,
+		Type:          RecordTypeOperation,
+		CorrelationID: correlationID,
+		Operation:     name,
+		Request:       redact(req),
+		Zone:          stringField(req, "Zone"),
+		ResourceID:    idField(req, "ID"),
+		Duration:      time.Since(started),
+	}
+	if record.ResourceID.IsEmpty() {
+		record.ResourceID = resultID(result)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	auditor.write(ctx, record)
+
+	return result, err
+}
+
+func structValue(v interface{}) (reflect.Value, bool) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}, false
+		}
+		rv = rv.Elem()
+	}
+	return rv, rv.Kind() == reflect.Struct
+}
+
+func stringField(v interface{}, name string) string {
+	rv, ok := structValue(v)
+	if !ok {
+		return ""
+	}
+	f := rv.FieldByName(name)
+	if f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return ""
+}
+
+func idField(v interface{}, name string) types.ID {
+	rv, ok := structValue(v)
+	if !ok {
+		return types.ID(0)
+	}
+	f := rv.FieldByName(name)
+	if f.IsValid() && f.CanInterface() {
+		if id, ok := f.Interface().(types.ID); ok {
+			return id
+		}
+	}
+	return types.ID(0)
+}
+
+func resultID(v interface{}) types.ID {
+	if r, ok := v.(interface{ GetID() types.ID }); ok {
+		if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+			return types.ID(0)
+		}
+		return r.GetID()
+	}
+	return types.ID(0)
+}
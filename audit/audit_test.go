@@ -0,0 +1,209 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/sacloud/iaas-service-go/sim"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyCaller struct {
+	err error
+}
+
+func (c *dummyCaller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	return []byte(`{}`), c.err
+}
+
+type dummyRequest struct {
+	Zone     string
+	ID       types.ID
+	Name     string
+	Password string
+	Disk     struct {
+		SSHKey   string
+		Password string
+	}
+}
+
+func TestDo(t *testing.T) {
+	var records []*Record
+	auditor := &Auditor{
+		Sink: SinkFunc(func(_ context.Context, r *Record) error {
+			records = append(records, r)
+			return nil
+		}),
+		NewCorrelationID: func() string { return "correlation-id" },
+	}
+	caller := auditor.Caller(&dummyCaller{})
+
+	req := &dummyRequest{Zone: "is1a", ID: 101, Name: "example", Password: "p@ssw0rd"}
+	req.Disk.SSHKey = "ssh-ed25519 AAAA"
+	req.Disk.Password = "p@ssw0rd"
+
+	_, err := Do(context.Background(), auditor, "server.Update", req, func(ctx context.Context) (*iaas.Server, error) {
+		require.Equal(t, "correlation-id", CorrelationID(ctx))
+		_, err := caller.Do(ctx, "GET", "https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/server/101", nil)
+		require.NoError(t, err)
+		_, err = caller.Do(ctx, "PUT", "https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/server/101/power", nil)
+		require.NoError(t, err)
+		return &iaas.Server{ID: 101}, nil
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	for _, r := range records[:2] {
+		require.Equal(t, RecordTypeAPICall, r.Type)
+		require.Equal(t, "server.Update", r.Operation)
+		require.Equal(t, "correlation-id", r.CorrelationID)
+		require.Equal(t, "is1a", r.Zone)
+		require.Equal(t, types.ID(101), r.ResourceID)
+		require.True(t, r.Succeeded())
+	}
+	require.Equal(t, "PUT", records[1].Method)
+
+	op := records[2]
+	require.Equal(t, RecordTypeOperation, op.Type)
+	require.Equal(t, "server.Update", op.Operation)
+	require.Equal(t, "is1a", op.Zone)
+	require.Equal(t, types.ID(101), op.ResourceID)
+	require.Equal(t, map[string]interface{}{
+		"Zone":     "is1a",
+		"ID":       float64(101),
+		"Name":     "example",
+		"Password": RedactedValue,
+		"Disk": map[string]interface{}{
+			"SSHKey":   "ssh-ed25519 AAAA",
+			"Password": RedactedValue,
+		},
+	}, op.Request)
+	// 元のリクエストは変更されない
+	require.Equal(t, "p@ssw0rd", req.Password)
+}
+
+func TestRedact(t *testing.T) {
+	req := &sim.CreateRequest{Name: "example", ICCID: "1234567890", PassCode: "p@ssc0de", IMEI: "123456789012345"}
+	redacted := redact(req).(map[string]interface{})
+	require.Equal(t, RedactedValue, redacted["PassCode"])
+	require.Equal(t, "1234567890", redacted["ICCID"])
+	require.Equal(t, "123456789012345", redacted["IMEI"])
+	require.Equal(t, "p@ssc0de", req.PassCode)
+}
+
+func TestDo_Error(t *testing.T) {
+	var records []*Record
+	auditor := &Auditor{
+		Sink: SinkFunc(func(_ context.Context, r *Record) error {
+			records = append(records, r)
+			return nil
+		}),
+	}
+	caller := auditor.Caller(&dummyCaller{err: errors.New("api error")})
+
+	ctx := WithCorrelationID(context.Background(), "given-id")
+	_, err := Do(ctx, auditor, "switch.Create", nil, func(ctx context.Context) (*iaas.Switch, error) {
+		_, err := caller.Do(ctx, "POST", "https://secure.sakura.ad.jp/cloud/zone/tk1a/api/cloud/1.1/switch", nil)
+		return nil, err
+	})
+	require.EqualError(t, err, "api error")
+	require.Len(t, records, 2)
+	for _, r := range records {
+		require.Equal(t, "given-id", r.CorrelationID)
+		require.Equal(t, "api error", r.Error)
+		require.False(t, r.Succeeded())
+		require.True(t, r.ResourceID.IsEmpty())
+	}
+	require.Equal(t, "tk1a", records[0].Zone)
+}
+
+func TestCaller_Operation(t *testing.T) {
+	var records []*Record
+	auditor := &Auditor{
+		Sink: SinkFunc(func(_ context.Context, r *Record) error {
+			records = append(records, r)
+			return nil
+		}),
+		NewCorrelationID: func() string { return "correlation-id" },
+	}
+	caller := auditor.Caller(&dummyCaller{})
+
+	_, err := Do(context.Background(), auditor, "batch", nil, func(ctx context.Context) (*iaas.Server, error) {
+		return server.New(caller).ReadWithContext(ctx, &server.ReadRequest{Zone: "is1a", ID: 101})
+	})
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+
+	api, op := records[0], records[1]
+	require.Equal(t, RecordTypeAPICall, api.Type)
+	require.Equal(t, "Server.Read", api.Operation)
+
+	require.Equal(t, RecordTypeOperation, op.Type)
+	require.Equal(t, "Server.Read", op.Operation)
+	require.Equal(t, "is1a", op.Zone)
+	require.Equal(t, types.ID(101), op.ResourceID)
+	require.Equal(t, map[string]interface{}{"Zone": "is1a", "ID": float64(101)}, op.Request)
+
+	require.Equal(t, "batch", records[2].Operation)
+	for _, r := range records {
+		require.Equal(t, "correlation-id", r.CorrelationID)
+	}
+
+	// Doを使わない場合も操作ごとに記録される
+	records = nil
+	_, err = server.New(caller).Read(&server.ReadRequest{Zone: "is1a", ID: 101})
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	require.Equal(t, RecordTypeOperation, records[1].Type)
+	require.NotEmpty(t, records[1].CorrelationID)
+	require.Equal(t, records[0].CorrelationID, records[1].CorrelationID)
+
+	// 検証エラーで拒否された操作も記録される
+	records = nil
+	_, err = server.New(caller).Read(&server.ReadRequest{Zone: "is1a"})
+	require.Error(t, err)
+	require.Len(t, records, 1)
+	require.Equal(t, RecordTypeOperation, records[0].Type)
+	require.Equal(t, "Server.Read", records[0].Operation)
+	require.False(t, records[0].Succeeded())
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	auditor := &Auditor{Sink: NewJSONLinesSink(buf)}
+	caller := auditor.Caller(&dummyCaller{})
+
+	_, err := caller.Do(context.Background(), "GET", "https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/disk/102", nil)
+	require.NoError(t, err)
+	_, err = caller.Do(context.Background(), "GET", "https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/disk/103", nil)
+	require.NoError(t, err)
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+
+	var record Record
+	require.NoError(t, json.Unmarshal(lines[1], &record))
+	require.Equal(t, RecordTypeAPICall, record.Type)
+	require.Equal(t, types.ID(103), record.ResourceID)
+	require.Empty(t, record.Operation)
+}
@@ -0,0 +1,107 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/serviceutil"
+)
+
+// Caller API呼び出しごとに監査ログを出力するiaas.APICallerを返す
+//
+// 戻り値はservice.OperationHookを実装しており、各サービスのコンストラクタに渡すと
+// サービスの操作(例: Server.Apply)ごとのレコードも出力される。
+func (a *Auditor) Caller(caller iaas.APICaller) iaas.APICaller {
+	return &auditCaller{auditor: a, caller: caller}
+}
+
+var (
+	_ service.OperationHook = (*auditCaller)(nil)
+	_ service.CallerWrapper = (*auditCaller)(nil)
+)
+
+type auditCaller struct {
+	auditor *Auditor
+	caller  iaas.APICaller
+}
+
+// Unwrap ラップしているiaas.APICallerを返す
+func (c *auditCaller) Unwrap() iaas.APICaller {
+	return c.caller
+}
+
+func (c *auditCaller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	started := time.Now()
+	data, err := c.caller.Do(ctx, method, uri, body)
+
+	op := operationFromContext(ctx)
+	var zone string
+	var id types.ID
+	if u, ok := serviceutil.ParseAPIURL(uri); ok {
+		zone, id = u.Zone, u.ID()
+	}
+	record := &Record{
+		Time:          started,
+		Type:          RecordTypeAPICall,
+		CorrelationID: op.correlationID,
+		Operation:     op.name,
+		Method:        method,
+		URL:           uri,
+		Zone:          zone,
+		ResourceID:    id,
+		Duration:      time.Since(started),
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	c.auditor.write(ctx, record)
+
+	return data, err
+}
+
+// StartOperation service.OperationHookの実装
+//
+// 操作名と相関IDを設定したcontextを返し、操作の終了時に操作のレコードを出力する
+func (c *auditCaller) StartOperation(ctx context.Context, op *service.Operation) (context.Context, func(err error)) {
+	name := op.Resource + "." + op.Name
+	correlationID := CorrelationID(ctx)
+	if correlationID == "" && c.auditor != nil {
+		correlationID = c.auditor.newCorrelationID()
+	}
+	ctx = context.WithValue(ctx, operationContextKey{}, &operation{name: name, correlationID: correlationID})
+
+	started := time.Now()
+	return ctx, func(err error) {
+		record := &Record{
+			Time:          started,
+			Type:          RecordTypeOperation,
+			CorrelationID: correlationID,
+			Operation:     name,
+			Request:       redact(op.Request),
+			Zone:          op.Zone,
+			ResourceID:    op.ID,
+			Duration:      time.Since(started),
+		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+		c.auditor.write(ctx, record)
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"encoding/json"
+
+	"github.com/sacloud/iaas-service-go/serviceutil"
+)
+
+// RedactedValue マスクされた値
+const RedactedValue = "(redacted)"
+
+// redact リクエストをJSON互換の値へ変換し、パスワードなどの値(serviceutil.DefaultSensitiveKeys)をマスクする
+func redact(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil
+	}
+	return serviceutil.MaskSensitiveValues(decoded, serviceutil.DefaultSensitiveKeys, RedactedValue)
+}
@@ -0,0 +1,53 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+// Sink 監査ログの出力先
+type Sink interface {
+	Write(ctx context.Context, r *Record) error
+}
+
+// SinkFunc 関数をSinkとして扱うためのアダプタ
+type SinkFunc func(ctx context.Context, r *Record) error
+
+// Write Sinkインターフェースの実装
+func (f SinkFunc) Write(ctx context.Context, r *Record) error {
+	return f(ctx, r)
+}
+
+// JSONLinesSink レコードを1行1件のJSONとして出力するSink
+type JSONLinesSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLinesSink wへ出力するJSONLinesSinkを返す
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{enc: json.NewEncoder(w)}
+}
+
+// Write Sinkインターフェースの実装
+func (s *JSONLinesSink) Write(_ context.Context, r *Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.enc.Encode(r)
+}
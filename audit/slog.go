@@ -0,0 +1,70 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package audit
+
+import (
+	"context"
+	"log/slog"
+)
+
+// SlogSink レコードをslog.Handlerへ出力するSink
+type SlogSink struct {
+	handler slog.Handler
+	level   slog.Level
+}
+
+// NewSlogSink handlerへ出力するSlogSinkを返す
+//
+// 成功したレコードはlevel、失敗したレコードはslog.LevelErrorで出力される。
+func NewSlogSink(handler slog.Handler, level slog.Level) *SlogSink {
+	return &SlogSink{handler: handler, level: level}
+}
+
+// Write Sinkインターフェースの実装
+func (s *SlogSink) Write(ctx context.Context, r *Record) error {
+	level := s.level
+	if !r.Succeeded() {
+		level = slog.LevelError
+	}
+	if !s.handler.Enabled(ctx, level) {
+		return nil
+	}
+
+	record := slog.NewRecord(r.Time, level, "audit", 0)
+	record.AddAttrs(
+		slog.String("type", string(r.Type)),
+		slog.String("correlation_id", r.CorrelationID),
+		slog.String("operation", r.Operation),
+		slog.Duration("duration", r.Duration),
+	)
+	if r.Request != nil {
+		record.AddAttrs(slog.Any("request", r.Request))
+	}
+	if r.Method != "" {
+		record.AddAttrs(slog.String("method", r.Method), slog.String("url", r.URL))
+	}
+	if r.Zone != "" {
+		record.AddAttrs(slog.String("zone", r.Zone))
+	}
+	if !r.ResourceID.IsEmpty() {
+		record.AddAttrs(slog.String("id", r.ResourceID.String()))
+	}
+	if r.Error != "" {
+		record.AddAttrs(slog.String("error", r.Error))
+	}
+	return s.handler.Handle(ctx, record)
+}
@@ -0,0 +1,44 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build go1.21
+
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogSink(t *testing.T) {
+	buf := &bytes.Buffer{}
+	auditor := &Auditor{Sink: NewSlogSink(slog.NewJSONHandler(buf, nil), slog.LevelInfo)}
+	caller := auditor.Caller(&dummyCaller{})
+
+	_, err := caller.Do(context.Background(), "DELETE", "https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/switch/104", nil)
+	require.NoError(t, err)
+
+	var v map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &v))
+	require.Equal(t, "INFO", v["level"])
+	require.Equal(t, "api", v["type"])
+	require.Equal(t, "DELETE", v["method"])
+	require.Equal(t, "is1a", v["zone"])
+	require.Equal(t, "104", v["id"])
+}
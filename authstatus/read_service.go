@@ -18,13 +18,17 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Read() (*iaas.AuthStatus, error) {
 	return s.ReadWithContext(context.Background())
 }
 
-func (s *Service) ReadWithContext(ctx context.Context) (*iaas.AuthStatus, error) {
+func (s *Service) ReadWithContext(ctx context.Context) (_ *iaas.AuthStatus, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Read", Resource: "AuthStatus"})
+	defer func() { err = finish(err) }()
+
 	client := iaas.NewAuthStatusOp(s.caller)
 	return client.Read(ctx)
 }
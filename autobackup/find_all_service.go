@@ -0,0 +1,61 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package autobackup
+
+import (
+	"context"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/pagination"
+)
+
+// FindAll 検索結果の全ページを順に取得し、要素ごとにfnを呼び出す
+//
+// req.Countを1ページあたりの件数(0の場合はpagination.DefaultPageSize)、req.Fromを開始位置として扱う。
+// req.Filterはクライアント側で適用される。fnからpagination.ErrStopを返すとエラーとせずに中断する。
+func (s *Service) FindAll(req *FindRequest, fn func(v *iaas.AutoBackup) error) error {
+	return s.FindAllWithContext(context.Background(), req, fn)
+}
+
+func (s *Service) FindAllWithContext(ctx context.Context, req *FindRequest, fn func(v *iaas.AutoBackup) error) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "FindAll", Resource: "AutoBackup", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return &service.Error{Op: "FindAll", Resource: "AutoBackup", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	params, err := req.ToRequestParameter()
+	if err != nil {
+		return err
+	}
+
+	client := iaas.NewAutoBackupOp(s.caller)
+	return pagination.Each(ctx, req.From, req.Count, func(ctx context.Context, from, count int) ([]*iaas.AutoBackup, int, error) {
+		params.From = from
+		params.Count = count
+		found, err := client.Find(ctx, req.Zone, params)
+		if err != nil {
+			return nil, 0, err
+		}
+		return found.AutoBackups, found.Total, nil
+	}, func(v *iaas.AutoBackup) error {
+		if !req.Filter.Match(v) {
+			return nil
+		}
+		return fn(v)
+	})
+}
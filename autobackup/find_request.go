@@ -17,6 +17,7 @@ package autobackup
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -28,6 +29,8 @@ type FindRequest struct {
 	Names []string `service:"-"`
 	Tags  []string `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -51,5 +54,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.Tags) {
 		condition.Filter[search.Key("Tags.Name")] = search.TagsAndEqual(req.Tags...)
 	}
+	filter.SetCondition[*iaas.AutoBackup](&req.Filter, condition)
 	return condition, nil
 }
@@ -18,15 +18,20 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/filter"
 )
 
 func (s *Service) Find(req *FindRequest) ([]*iaas.AutoBackup, error) {
 	return s.FindWithContext(context.Background(), req)
 }
 
-func (s *Service) FindWithContext(ctx context.Context, req *FindRequest) ([]*iaas.AutoBackup, error) {
+func (s *Service) FindWithContext(ctx context.Context, req *FindRequest) (_ []*iaas.AutoBackup, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Find", Resource: "AutoBackup", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Find", Resource: "AutoBackup", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	params, err := req.ToRequestParameter()
@@ -39,5 +44,5 @@ func (s *Service) FindWithContext(ctx context.Context, req *FindRequest) ([]*iaa
 	if err != nil {
 		return nil, err
 	}
-	return found.AutoBackups, nil
+	return filter.Apply(&req.Filter, found.AutoBackups), nil
 }
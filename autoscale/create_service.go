@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Create(req *CreateRequest) (*iaas.AutoScale, error) {
 	return s.CreateWithContext(context.Background(), req)
 }
 
-func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (*iaas.AutoScale, error) {
+func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (_ *iaas.AutoScale, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Create", Resource: "AutoScale", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Create", Resource: "AutoScale", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	params, err := req.ToRequestParameter()
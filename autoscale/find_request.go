@@ -17,6 +17,7 @@ package autoscale
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -26,6 +27,8 @@ type FindRequest struct {
 	Names []string `service:"-"`
 	Tags  []string `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -49,5 +52,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.Tags) {
 		condition.Filter[search.Key("Tags.Name")] = search.TagsAndEqual(req.Tags...)
 	}
+	filter.SetCondition[*iaas.AutoScale](&req.Filter, condition)
 	return condition, nil
 }
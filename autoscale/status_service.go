@@ -18,16 +18,21 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Status(req *StatusRequest) (*iaas.AutoScaleStatus, error) {
 	return s.StatusWithContext(context.Background(), req)
 }
 
-func (s *Service) StatusWithContext(ctx context.Context, req *StatusRequest) (*iaas.AutoScaleStatus, error) {
+func (s *Service) StatusWithContext(ctx context.Context, req *StatusRequest) (_ *iaas.AutoScaleStatus, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Status", Resource: "AutoScale", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Status", Resource: "AutoScale", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
+
 	client := iaas.NewAutoScaleOp(s.caller)
 	return client.Status(ctx, req.ID)
 }
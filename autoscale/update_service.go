@@ -16,29 +16,32 @@ package autoscale
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Update(req *UpdateRequest) (*iaas.AutoScale, error) {
 	return s.UpdateWithContext(context.Background(), req)
 }
 
-func (s *Service) UpdateWithContext(ctx context.Context, req *UpdateRequest) (*iaas.AutoScale, error) {
+func (s *Service) UpdateWithContext(ctx context.Context, req *UpdateRequest) (_ *iaas.AutoScale, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Update", Resource: "AutoScale", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Update", Resource: "AutoScale", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewAutoScaleOp(s.caller)
 	current, err := client.Read(ctx, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("reading AutoScale[%s] failed: %s", req.ID, err)
+		return nil, &service.Error{Op: "Update", Resource: "AutoScale", ID: req.ID, Err: err}
 	}
 
 	params, err := req.ToRequestParameter(current)
 	if err != nil {
-		return nil, fmt.Errorf("processing request parameter failed: %s", err)
+		return nil, &service.Error{Op: "Update", Resource: "AutoScale", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	return client.Update(ctx, req.ID, params)
@@ -19,15 +19,19 @@ import (
 	"errors"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Csv(req *CsvRequest) (*iaas.BillDetailCSV, error) {
 	return s.CsvWithContext(context.Background(), req)
 }
 
-func (s *Service) CsvWithContext(ctx context.Context, req *CsvRequest) (*iaas.BillDetailCSV, error) {
+func (s *Service) CsvWithContext(ctx context.Context, req *CsvRequest) (_ *iaas.BillDetailCSV, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Csv", Resource: "Bill", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Csv", Resource: "Bill", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	billOp := iaas.NewBillOp(s.caller)
@@ -39,10 +43,10 @@ func (s *Service) CsvWithContext(ctx context.Context, req *CsvRequest) (*iaas.Bi
 		return nil, err
 	}
 	if auth.AccountID.IsEmpty() {
-		return nil, errors.New("invalid account id")
+		return nil, &service.Error{Op: "Csv", Resource: "Bill", Err: errors.New("invalid account id")}
 	}
 	if !auth.ExternalPermission.PermittedBill() {
-		return nil, errors.New("you don't have a permission")
+		return nil, &service.Error{Op: "Csv", Resource: "Bill", Err: errors.New("you don't have a permission")}
 	}
 
 	// get latest bill ID if empty
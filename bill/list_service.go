@@ -19,15 +19,19 @@ import (
 	"errors"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) List(req *ListRequest) ([]*iaas.Bill, error) {
 	return s.ListWithContext(context.Background(), req)
 }
 
-func (s *Service) ListWithContext(ctx context.Context, req *ListRequest) ([]*iaas.Bill, error) {
+func (s *Service) ListWithContext(ctx context.Context, req *ListRequest) (_ []*iaas.Bill, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "List", Resource: "Bill", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "List", Resource: "Bill", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	billOp := iaas.NewBillOp(s.caller)
@@ -39,10 +43,10 @@ func (s *Service) ListWithContext(ctx context.Context, req *ListRequest) ([]*iaa
 		return nil, err
 	}
 	if auth.AccountID.IsEmpty() {
-		return nil, errors.New("invalid account id")
+		return nil, &service.Error{Op: "List", Resource: "Bill", Err: errors.New("invalid account id")}
 	}
 	if !auth.ExternalPermission.PermittedBill() {
-		return nil, errors.New("you don't have a permission")
+		return nil, &service.Error{Op: "List", Resource: "Bill", Err: errors.New("you don't have a permission")}
 	}
 
 	// get bills
@@ -19,15 +19,19 @@ import (
 	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ConnectSwitch(req *ConnectSwitchRequest) error {
 	return s.ConnectSwitchWithContext(context.Background(), req)
 }
 
-func (s *Service) ConnectSwitchWithContext(ctx context.Context, req *ConnectSwitchRequest) error {
+func (s *Service) ConnectSwitchWithContext(ctx context.Context, req *ConnectSwitchRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ConnectSwitch", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "ConnectSwitch", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	bridgeOp := iaas.NewBridgeOp(s.caller)
@@ -38,7 +42,7 @@ func (s *Service) ConnectSwitchWithContext(ctx context.Context, req *ConnectSwit
 		return err
 	}
 	if bridge.SwitchInZone != nil {
-		return fmt.Errorf("target bridge[%s] is already connected to the switch[%s]", req.ID, bridge.SwitchInZone.ID)
+		return &service.Error{Op: "ConnectSwitch", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindConflict, Err: fmt.Errorf("target bridge is already connected to the switch[%s]", bridge.SwitchInZone.ID)}
 	}
 
 	return switchOp.ConnectToBridge(ctx, req.Zone, req.SwitchID, req.ID)
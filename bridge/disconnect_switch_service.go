@@ -16,22 +16,26 @@ package bridge
 
 import (
 	"context"
-	"fmt"
+	"errors"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DisconnectSwitch(req *DisconnectSwitchRequest) error {
 	return s.DisconnectSwitchWithContext(context.Background(), req)
 }
 
-func (s *Service) DisconnectSwitchWithContext(ctx context.Context, req *DisconnectSwitchRequest) error {
+func (s *Service) DisconnectSwitchWithContext(ctx context.Context, req *DisconnectSwitchRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DisconnectSwitch", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DisconnectSwitch", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DisconnectSwitch", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	bridgeOp := iaas.NewBridgeOp(s.caller)
@@ -42,7 +46,7 @@ func (s *Service) DisconnectSwitchWithContext(ctx context.Context, req *Disconne
 		return err
 	}
 	if bridge.SwitchInZone == nil {
-		return fmt.Errorf("target bridge[%s] is not connected any switches", req.ID)
+		return &service.Error{Op: "DisconnectSwitch", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindConflict, Err: errors.New("target bridge is not connected any switches")}
 	}
 
 	return switchOp.DisconnectFromBridge(ctx, req.Zone, bridge.SwitchInZone.ID)
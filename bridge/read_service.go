@@ -18,16 +18,21 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Read(req *ReadRequest) (*iaas.Bridge, error) {
 	return s.ReadWithContext(context.Background(), req)
 }
 
-func (s *Service) ReadWithContext(ctx context.Context, req *ReadRequest) (*iaas.Bridge, error) {
+func (s *Service) ReadWithContext(ctx context.Context, req *ReadRequest) (_ *iaas.Bridge, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Read", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Read", Resource: "Bridge", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
+
 	client := iaas.NewBridgeOp(s.caller)
 	return client.Read(ctx, req.Zone, req.ID)
 }
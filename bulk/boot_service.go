@@ -0,0 +1,41 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Boot Selectorに該当するサーバ/アプライアンスを起動する
+//
+// 電源操作をサポートしない種別のリソースは対象外となる。
+func (s *Service) Boot(req *BootRequest) ([]*Result, error) {
+	return s.BootWithContext(context.Background(), req)
+}
+
+func (s *Service) BootWithContext(ctx context.Context, req *BootRequest) (_ []*Result, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Boot", Resource: "Bulk", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Boot", Resource: "Bulk", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	return s.selectAndRun(ctx, &req.Selector, req.Parallelism, true, func(k *kind) bool { return k.boot != nil }, func(ctx context.Context, k *kind, t *Target) error {
+		return k.boot(ctx, s.caller, t)
+	})
+}
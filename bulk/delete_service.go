@@ -0,0 +1,43 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Delete Selectorに該当するリソースを削除する
+//
+// サーバ/アプライアンス、ディスク/アーカイブ、スイッチの順に削除する。
+// 削除に失敗したゾーンでは、以降の順序のリソースは削除せずErrSkippedとする。
+func (s *Service) Delete(req *DeleteRequest) ([]*Result, error) {
+	return s.DeleteWithContext(context.Background(), req)
+}
+
+func (s *Service) DeleteWithContext(ctx context.Context, req *DeleteRequest) (_ []*Result, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Delete", Resource: "Bulk", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Delete", Resource: "Bulk", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	p := &deleteParameter{force: req.Force, withDisks: req.WithDisks}
+	return s.selectAndRun(ctx, &req.Selector, req.Parallelism, false, func(*kind) bool { return true }, func(ctx context.Context, k *kind, t *Target) error {
+		return k.delete(ctx, s.caller, t, p)
+	})
+}
@@ -0,0 +1,37 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Find Selectorに該当するリソースを返す
+func (s *Service) Find(req *FindRequest) ([]*Target, error) {
+	return s.FindWithContext(context.Background(), req)
+}
+
+func (s *Service) FindWithContext(ctx context.Context, req *FindRequest) (_ []*Target, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Find", Resource: "Bulk", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Find", Resource: "Bulk", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	return s.selectTargets(ctx, &req.Selector, req.Parallelism, func(*kind) bool { return true })
+}
@@ -0,0 +1,272 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/archive"
+	"github.com/sacloud/iaas-service-go/database"
+	"github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/loadbalancer"
+	"github.com/sacloud/iaas-service-go/mobilegateway"
+	"github.com/sacloud/iaas-service-go/multizone"
+	"github.com/sacloud/iaas-service-go/nfs"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/sacloud/iaas-service-go/swytch"
+	"github.com/sacloud/iaas-service-go/vpcrouter"
+)
+
+// 操作対象にできるリソース種別
+const (
+	KindServer        = "Server"
+	KindVPCRouter     = "VPCRouter"
+	KindLoadBalancer  = "LoadBalancer"
+	KindDatabase      = "Database"
+	KindNFS           = "NFS"
+	KindMobileGateway = "MobileGateway"
+	KindDisk          = "Disk"
+	KindArchive       = "Archive"
+	KindSwitch        = "Switch"
+)
+
+// Target 操作対象のリソース
+type Target struct {
+	Kind      string
+	Zone      string
+	ID        types.ID
+	Name      string
+	Tags      types.Tags
+	CreatedAt time.Time
+}
+
+type findParameter struct {
+	zones       []string
+	parallelism int
+	tags        []string
+}
+
+type deleteParameter struct {
+	force     bool
+	withDisks bool
+}
+
+// kind リソース種別ごとの操作
+//
+// shutdown/bootがnilの種別は電源操作の対象外となる
+type kind struct {
+	name string
+	// stage 削除時の順序、小さいものから順に処理される
+	stage    int
+	find     func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error)
+	shutdown func(ctx context.Context, caller iaas.APICaller, t *Target, force bool) error
+	boot     func(ctx context.Context, caller iaas.APICaller, t *Target) error
+	delete   func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error
+}
+
+// kinds 操作対象にできるリソース種別の一覧
+//
+// サーバやアプライアンスを削除してからディスク/アーカイブ、スイッチの順で削除する
+var kinds = []*kind{
+	{
+		name:  KindServer,
+		stage: 0,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.Server](KindServer)(server.New(caller).FindAllZonesWithContext(ctx, &server.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: server.FindRequest{Tags: p.tags},
+			}))
+		},
+		shutdown: func(ctx context.Context, caller iaas.APICaller, t *Target, force bool) error {
+			return server.New(caller).ShutdownWithContext(ctx, &server.ShutdownRequest{Zone: t.Zone, ID: t.ID, ForceShutdown: force})
+		},
+		boot: func(ctx context.Context, caller iaas.APICaller, t *Target) error {
+			return server.New(caller).BootWithContext(ctx, &server.BootRequest{Zone: t.Zone, ID: t.ID})
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			return server.New(caller).DeleteWithContext(ctx, &server.DeleteRequest{Zone: t.Zone, ID: t.ID, WithDisks: p.withDisks, Force: p.force})
+		},
+	},
+	{
+		name:  KindVPCRouter,
+		stage: 0,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.VPCRouter](KindVPCRouter)(vpcrouter.New(caller).FindAllZonesWithContext(ctx, &vpcrouter.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: vpcrouter.FindRequest{Tags: p.tags},
+			}))
+		},
+		shutdown: func(ctx context.Context, caller iaas.APICaller, t *Target, force bool) error {
+			return vpcrouter.New(caller).ShutdownWithContext(ctx, &vpcrouter.ShutdownRequest{Zone: t.Zone, ID: t.ID, ForceShutdown: force})
+		},
+		boot: func(ctx context.Context, caller iaas.APICaller, t *Target) error {
+			return vpcrouter.New(caller).BootWithContext(ctx, &vpcrouter.BootRequest{Zone: t.Zone, ID: t.ID})
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			return vpcrouter.New(caller).DeleteWithContext(ctx, &vpcrouter.DeleteRequest{Zone: t.Zone, ID: t.ID, Force: p.force})
+		},
+	},
+	{
+		name:  KindLoadBalancer,
+		stage: 0,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.LoadBalancer](KindLoadBalancer)(loadbalancer.New(caller).FindAllZonesWithContext(ctx, &loadbalancer.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: loadbalancer.FindRequest{Tags: p.tags},
+			}))
+		},
+		shutdown: func(ctx context.Context, caller iaas.APICaller, t *Target, force bool) error {
+			return loadbalancer.New(caller).ShutdownWithContext(ctx, &loadbalancer.ShutdownRequest{Zone: t.Zone, ID: t.ID, ForceShutdown: force})
+		},
+		boot: func(ctx context.Context, caller iaas.APICaller, t *Target) error {
+			return loadbalancer.New(caller).BootWithContext(ctx, &loadbalancer.BootRequest{Zone: t.Zone, ID: t.ID})
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			return loadbalancer.New(caller).DeleteWithContext(ctx, &loadbalancer.DeleteRequest{Zone: t.Zone, ID: t.ID, Force: p.force})
+		},
+	},
+	{
+		name:  KindDatabase,
+		stage: 0,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.Database](KindDatabase)(database.New(caller).FindAllZonesWithContext(ctx, &database.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: database.FindRequest{Tags: p.tags},
+			}))
+		},
+		shutdown: func(ctx context.Context, caller iaas.APICaller, t *Target, force bool) error {
+			return database.New(caller).ShutdownWithContext(ctx, &database.ShutdownRequest{Zone: t.Zone, ID: t.ID, ForceShutdown: force})
+		},
+		boot: func(ctx context.Context, caller iaas.APICaller, t *Target) error {
+			return database.New(caller).BootWithContext(ctx, &database.BootRequest{Zone: t.Zone, ID: t.ID})
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			return database.New(caller).DeleteWithContext(ctx, &database.DeleteRequest{Zone: t.Zone, ID: t.ID, Force: p.force})
+		},
+	},
+	{
+		name:  KindNFS,
+		stage: 0,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.NFS](KindNFS)(nfs.New(caller).FindAllZonesWithContext(ctx, &nfs.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: nfs.FindRequest{Tags: p.tags},
+			}))
+		},
+		shutdown: func(ctx context.Context, caller iaas.APICaller, t *Target, force bool) error {
+			return nfs.New(caller).ShutdownWithContext(ctx, &nfs.ShutdownRequest{Zone: t.Zone, ID: t.ID, ForceShutdown: force})
+		},
+		boot: func(ctx context.Context, caller iaas.APICaller, t *Target) error {
+			return nfs.New(caller).BootWithContext(ctx, &nfs.BootRequest{Zone: t.Zone, ID: t.ID})
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			return nfs.New(caller).DeleteWithContext(ctx, &nfs.DeleteRequest{Zone: t.Zone, ID: t.ID, Force: p.force})
+		},
+	},
+	{
+		name:  KindMobileGateway,
+		stage: 0,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.MobileGateway](KindMobileGateway)(mobilegateway.New(caller).FindAllZonesWithContext(ctx, &mobilegateway.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: mobilegateway.FindRequest{Tags: p.tags},
+			}))
+		},
+		shutdown: func(ctx context.Context, caller iaas.APICaller, t *Target, force bool) error {
+			return mobilegateway.New(caller).ShutdownWithContext(ctx, &mobilegateway.ShutdownRequest{Zone: t.Zone, ID: t.ID, ForceShutdown: force})
+		},
+		boot: func(ctx context.Context, caller iaas.APICaller, t *Target) error {
+			return mobilegateway.New(caller).BootWithContext(ctx, &mobilegateway.BootRequest{Zone: t.Zone, ID: t.ID})
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			return mobilegateway.New(caller).DeleteWithContext(ctx, &mobilegateway.DeleteRequest{Zone: t.Zone, ID: t.ID, Force: p.force})
+		},
+	},
+	{
+		name:  KindDisk,
+		stage: 1,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.Disk](KindDisk)(disk.New(caller).FindAllZonesWithContext(ctx, &disk.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: disk.FindRequest{Tags: p.tags},
+			}))
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			err := disk.New(caller).DeleteWithContext(ctx, &disk.DeleteRequest{Zone: t.Zone, ID: t.ID})
+			if p.withDisks && service.IsNotFoundError(err) {
+				// サーバと一緒に削除済みのディスク
+				return nil
+			}
+			return err
+		},
+	},
+	{
+		name:  KindArchive,
+		stage: 1,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.Archive](KindArchive)(archive.New(caller).FindAllZonesWithContext(ctx, &archive.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: archive.FindRequest{Tags: p.tags, Scope: types.Scopes.User},
+			}))
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			return archive.New(caller).DeleteWithContext(ctx, &archive.DeleteRequest{Zone: t.Zone, ID: t.ID})
+		},
+	},
+	{
+		name:  KindSwitch,
+		stage: 2,
+		find: func(ctx context.Context, caller iaas.APICaller, p *findParameter) ([]*Target, error) {
+			return targets[*iaas.Switch](KindSwitch)(swytch.New(caller).FindAllZonesWithContext(ctx, &swytch.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: swytch.FindRequest{Tags: p.tags},
+			}))
+		},
+		delete: func(ctx context.Context, caller iaas.APICaller, t *Target, p *deleteParameter) error {
+			return swytch.New(caller).DeleteWithContext(ctx, &swytch.DeleteRequest{Zone: t.Zone, ID: t.ID})
+		},
+	},
+}
+
+func kindByName(name string) *kind {
+	for _, k := range kinds {
+		if k.name == name {
+			return k
+		}
+	}
+	return nil
+}
+
+type resource interface {
+	GetID() types.ID
+	GetName() string
+	GetTags() types.Tags
+	GetCreatedAt() time.Time
+}
+
+// targets FindAllZonesの結果をTargetへ変換する関数を返す
+//
+// 一部のゾーンで検索に失敗した場合も、成功したゾーンの結果はエラーと共に返す
+func targets[T resource](kind string) func(results []*multizone.Result[T], err error) ([]*Target, error) {
+	return func(results []*multizone.Result[T], err error) ([]*Target, error) {
+		var targets []*Target
+		for _, r := range results {
+			targets = append(targets, &Target{
+				Kind:      kind,
+				Zone:      r.Zone,
+				ID:        r.Value.GetID(),
+				Name:      r.Value.GetName(),
+				Tags:      r.Value.GetTags(),
+				CreatedAt: r.Value.GetCreatedAt(),
+			})
+		}
+		return targets, err
+	}
+}
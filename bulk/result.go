@@ -0,0 +1,91 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// String fmt.Stringerの実装
+func (t *Target) String() string {
+	if t.ID.IsEmpty() {
+		return t.Kind
+	}
+	return fmt.Sprintf("%s[%s:%s]", t.Kind, t.Zone, t.ID)
+}
+
+// Result リソースごとの処理結果
+type Result struct {
+	Target *Target
+	Err    error
+}
+
+// TargetError 特定のリソースの処理中に発生したエラー
+type TargetError struct {
+	Target *Target
+	Err    error
+}
+
+// Error errorインターフェースの実装
+func (e *TargetError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Target, e.Err)
+}
+
+// Unwrap 元のエラーを返す
+func (e *TargetError) Unwrap() error {
+	return e.Err
+}
+
+// Error 1つ以上のリソースで処理が失敗したことを示すエラー
+//
+// 全てのリソースの処理結果はこのエラーと共に返される。
+type Error struct {
+	Errors []*TargetError
+}
+
+// Error errorインターフェースの実装
+func (e *Error) Error() string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("failed in %d resource(s): %s", len(e.Errors), strings.Join(messages, ", "))
+}
+
+// Unwrap リソースごとのエラーを返す
+func (e *Error) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func errorFromResults(findErr *Error, results []*Result) error {
+	var errs []*TargetError
+	if findErr != nil {
+		errs = append(errs, findErr.Errors...)
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, &TargetError{Target: r.Target, Err: r.Err})
+		}
+	}
+	if len(errs) > 0 {
+		return &Error{Errors: errs}
+	}
+	return nil
+}
@@ -0,0 +1,230 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sacloud/iaas-service-go/multizone"
+)
+
+// DefaultParallelism 同時に処理するリソース数のデフォルト値
+const DefaultParallelism = 4
+
+// ErrSkipped 先行するstageで同じゾーンの処理が失敗したため、処理しなかったことを示すエラー
+var ErrSkipped = errors.New("skipped because a preceding stage failed")
+
+// Selector 操作対象の選択条件
+type Selector struct {
+	// Tags 指定したタグを全て持つリソースが対象となる
+	//
+	// Find以外の操作ではTagsかTargetsのいずれかの指定が必須
+	Tags []string
+	// Kinds 対象のリソース種別、省略時は操作をサポートする全ての種別が対象
+	Kinds []string `validate:"omitempty,dive,required"`
+	// Zones 対象のゾーン、省略時は全ゾーンが対象
+	Zones []string `validate:"omitempty,dive,required"`
+
+	// Targets 選択済みの操作対象、指定した場合はTags/Kinds/Zonesは無視される
+	Targets []*Target
+}
+
+// validate 選択条件を検証する
+//
+// requireConditionがtrueの場合、意図せず全てのリソースが対象とならないようTagsかTargetsの指定を必須とする
+func (s *Selector) validate(requireCondition bool) error {
+	if requireCondition && len(s.Tags) == 0 && len(s.Targets) == 0 {
+		return errors.New("Tags or Targets is required")
+	}
+	for _, name := range s.Kinds {
+		if kindByName(name) == nil {
+			return fmt.Errorf("unsupported kind: %q", name)
+		}
+	}
+	for _, t := range s.Targets {
+		if kindByName(t.Kind) == nil {
+			return fmt.Errorf("unsupported kind: %q", t.Kind)
+		}
+		if t.Zone == "" || t.ID.IsEmpty() {
+			return fmt.Errorf("Zone and ID are required: %s", t)
+		}
+	}
+	return nil
+}
+
+func (s *Selector) includes(k *kind) bool {
+	if len(s.Kinds) == 0 {
+		return true
+	}
+	for _, name := range s.Kinds {
+		if name == k.name {
+			return true
+		}
+	}
+	return false
+}
+
+// selectTargets Selectorに該当するリソースのうち、supportsがtrueを返す種別のものを返す
+//
+// 一部の種別やゾーンでの検索に失敗した場合も、検索できたリソースは*Errorと共に返す
+func (s *Service) selectTargets(ctx context.Context, selector *Selector, parallelism int, supports func(k *kind) bool) ([]*Target, error) {
+	if len(selector.Targets) > 0 {
+		var targets []*Target
+		for _, t := range selector.Targets {
+			if supports(kindByName(t.Kind)) {
+				targets = append(targets, t)
+			}
+		}
+		return targets, nil
+	}
+
+	zones := selector.Zones
+	if len(zones) == 0 {
+		found, err := multizone.Zones(ctx, s.caller)
+		if err != nil {
+			return nil, err
+		}
+		zones = found
+	}
+
+	p := &findParameter{zones: zones, parallelism: parallelism, tags: selector.Tags}
+	var targets []*Target
+	var errs []*TargetError
+	for _, k := range kinds {
+		if !selector.includes(k) || !supports(k) {
+			continue
+		}
+		found, err := k.find(ctx, s.caller, p)
+		targets = append(targets, found...)
+		if err != nil {
+			errs = append(errs, &TargetError{Target: &Target{Kind: k.name}, Err: err})
+		}
+	}
+	if len(errs) > 0 {
+		return targets, &Error{Errors: errs}
+	}
+	return targets, nil
+}
+
+// selectAndRun Selectorに該当するリソースのうち、supportsがtrueを返す種別のものに対しfnを実行する
+//
+// 一部の種別やゾーンでの検索に失敗した場合も検索できたリソースは処理し、検索時のエラーは処理結果のエラーと共に返す。
+func (s *Service) selectAndRun(ctx context.Context, selector *Selector, parallelism int, reverse bool, supports func(k *kind) bool, fn func(ctx context.Context, k *kind, t *Target) error) ([]*Result, error) {
+	targets, err := s.selectTargets(ctx, selector, parallelism, supports)
+	var findErr *Error
+	if err != nil && !errors.As(err, &findErr) {
+		return nil, err
+	}
+
+	results := run(ctx, targets, parallelism, reverse, findFailures(findErr), fn)
+	return results, errorFromResults(findErr, results)
+}
+
+// findFailures 検索に失敗したゾーンをstageごとに返す
+//
+// 失敗したゾーンを特定できない場合は全てのゾーンを表す空文字となる
+func findFailures(findErr *Error) map[int][]string {
+	failures := map[int][]string{}
+	if findErr == nil {
+		return failures
+	}
+	for _, e := range findErr.Errors {
+		stage := kindByName(e.Target.Kind).stage
+		var zoneErr *multizone.Error
+		if !errors.As(e.Err, &zoneErr) {
+			failures[stage] = append(failures[stage], "")
+			continue
+		}
+		for _, ze := range zoneErr.Errors {
+			failures[stage] = append(failures[stage], ze.Zone)
+		}
+	}
+	return failures
+}
+
+// run 対象のリソースに対しfnを実行する
+//
+// kindのstageごとに、reverseがfalseの場合は昇順、trueの場合は降順に処理する。
+// 同じstageのリソースはparallelismを上限に並列で処理される。
+// あるstageで処理や検索(failures)に失敗したゾーンでは、以降のstageのリソースは処理せずErrSkippedとする。
+func run(ctx context.Context, targets []*Target, parallelism int, reverse bool, failures map[int][]string, fn func(ctx context.Context, k *kind, t *Target) error) []*Result {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	results := make([]*Result, len(targets))
+	stages := map[int][]int{}
+	for i, t := range targets {
+		results[i] = &Result{Target: t}
+		stage := kindByName(t.Kind).stage
+		stages[stage] = append(stages[stage], i)
+	}
+	for stage := range failures {
+		if _, ok := stages[stage]; !ok {
+			stages[stage] = nil
+		}
+	}
+	var order []int
+	for stage := range stages {
+		order = append(order, stage)
+	}
+	sort.Ints(order)
+	if reverse {
+		sort.Sort(sort.Reverse(sort.IntSlice(order)))
+	}
+
+	failed := map[string]bool{}
+	for _, stage := range order {
+		sem := make(chan struct{}, parallelism)
+		var wg sync.WaitGroup
+		for _, i := range stages[stage] {
+			if failed[""] || failed[results[i].Target.Zone] {
+				results[i].Err = ErrSkipped
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				results[i].Err = err
+				continue
+			}
+			wg.Add(1)
+			go func(result *Result) {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					result.Err = ctx.Err()
+					return
+				}
+				defer func() { <-sem }()
+				result.Err = fn(ctx, kindByName(result.Target.Kind), result.Target)
+			}(results[i])
+		}
+		wg.Wait()
+
+		for _, i := range stages[stage] {
+			if results[i].Err != nil {
+				failed[results[i].Target.Zone] = true
+			}
+		}
+		for _, zone := range failures[stage] {
+			failed[zone] = true
+		}
+	}
+	return results
+}
@@ -0,0 +1,221 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/multizone"
+	"github.com/sacloud/packages-go/size"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("bulk")
+	zone := testutil.TestZone()
+	tag := name + "-env=staging"
+
+	serverOp := iaas.NewServerOp(caller)
+	diskOp := iaas.NewDiskOp(caller)
+	switchOp := iaas.NewSwitchOp(caller)
+
+	sw, err := switchOp.Create(ctx, zone, &iaas.SwitchCreateRequest{Name: name, Tags: types.Tags{tag}})
+	require.NoError(t, err)
+
+	sv, err := serverOp.Create(ctx, zone, &iaas.ServerCreateRequest{
+		CPU:                  1,
+		MemoryMB:             1 * size.GiB,
+		ServerPlanCommitment: types.Commitments.Standard,
+		ConnectedSwitches:    []*iaas.ConnectedSwitch{{ID: sw.ID}},
+		InterfaceDriver:      types.InterfaceDrivers.VirtIO,
+		Name:                 name,
+		Tags:                 types.Tags{tag},
+	})
+	require.NoError(t, err)
+	require.NoError(t, serverOp.Boot(ctx, zone, sv.ID))
+
+	untagged, err := serverOp.Create(ctx, zone, &iaas.ServerCreateRequest{
+		CPU:                  1,
+		MemoryMB:             1 * size.GiB,
+		ServerPlanCommitment: types.Commitments.Standard,
+		Name:                 name,
+	})
+	require.NoError(t, err)
+	defer serverOp.Delete(ctx, zone, untagged.ID) //nolint:errcheck
+
+	disk, err := diskOp.Create(ctx, zone, &iaas.DiskCreateRequest{
+		DiskPlanID: types.DiskPlans.SSD,
+		SizeMB:     20 * size.GiB,
+		Name:       name,
+		Tags:       types.Tags{tag},
+	}, nil)
+	require.NoError(t, err)
+
+	svc := New(caller)
+	selector := Selector{Tags: []string{tag}, Zones: []string{zone}}
+
+	targets, err := svc.Find(&FindRequest{Selector: selector})
+	require.NoError(t, err)
+	require.Len(t, targets, 3)
+	require.Equal(t, KindServer, targets[0].Kind)
+	require.Equal(t, sv.ID, targets[0].ID)
+	require.Equal(t, KindDisk, targets[1].Kind)
+	require.Equal(t, KindSwitch, targets[2].Kind)
+
+	// 電源操作はサーバのみが対象
+	results, err := svc.Shutdown(&ShutdownRequest{Selector: selector, ForceShutdown: true})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	read, err := serverOp.Read(ctx, zone, sv.ID)
+	require.NoError(t, err)
+	require.True(t, read.InstanceStatus.IsDown())
+
+	results, err = svc.Boot(&BootRequest{Selector: selector})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	read, err = serverOp.Read(ctx, zone, sv.ID)
+	require.NoError(t, err)
+	require.True(t, read.InstanceStatus.IsUp())
+
+	results, err = svc.Delete(&DeleteRequest{Selector: selector, Force: true})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+	for _, r := range results {
+		require.NoError(t, r.Err)
+	}
+
+	_, err = serverOp.Read(ctx, zone, sv.ID)
+	require.True(t, iaas.IsNotFoundError(err))
+	_, err = diskOp.Read(ctx, zone, disk.ID)
+	require.True(t, iaas.IsNotFoundError(err))
+	_, err = switchOp.Read(ctx, zone, sw.ID)
+	require.True(t, iaas.IsNotFoundError(err))
+	_, err = serverOp.Read(ctx, zone, untagged.ID)
+	require.NoError(t, err)
+}
+
+func TestRequest_Validate(t *testing.T) {
+	_, err := New(testutil.SingletonAPICaller()).Delete(&DeleteRequest{})
+	require.True(t, service.IsValidationError(err))
+
+	_, err = New(testutil.SingletonAPICaller()).Delete(&DeleteRequest{Selector: Selector{Tags: []string{"a"}, Kinds: []string{"Unknown"}}})
+	require.True(t, service.IsValidationError(err))
+}
+
+func TestService_Delete_withDisks(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("bulk-with-disks")
+	zone := testutil.TestZone()
+	tag := name + "-env=staging"
+
+	sv, err := iaas.NewServerOp(caller).Create(ctx, zone, &iaas.ServerCreateRequest{
+		CPU:                  1,
+		MemoryMB:             1 * size.GiB,
+		ServerPlanCommitment: types.Commitments.Standard,
+		Name:                 name,
+		Tags:                 types.Tags{tag},
+	})
+	require.NoError(t, err)
+
+	diskOp := iaas.NewDiskOp(caller)
+	disk, err := diskOp.Create(ctx, zone, &iaas.DiskCreateRequest{
+		DiskPlanID: types.DiskPlans.SSD,
+		SizeMB:     20 * size.GiB,
+		Name:       name,
+		Tags:       types.Tags{tag},
+		ServerID:   sv.ID,
+	}, nil)
+	require.NoError(t, err)
+
+	// サーバと一緒に削除されたディスクの削除は成功として扱われる
+	results, err := New(caller).Delete(&DeleteRequest{Selector: Selector{Tags: []string{tag}, Zones: []string{zone}}, WithDisks: true})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	for _, r := range results {
+		require.NoError(t, r.Err)
+	}
+
+	_, err = diskOp.Read(ctx, zone, disk.ID)
+	require.True(t, iaas.IsNotFoundError(err))
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	targets := []*Target{
+		{Kind: KindServer, Zone: "is1a", ID: 1},
+		{Kind: KindDisk, Zone: "is1a", ID: 2},
+		{Kind: KindSwitch, Zone: "is1a", ID: 3},
+		{Kind: KindDisk, Zone: "is1b", ID: 4},
+		{Kind: KindSwitch, Zone: "is1b", ID: 5},
+	}
+
+	t.Run("skip after failed stage", func(t *testing.T) {
+		var processed []types.ID
+		var mu sync.Mutex
+		results := run(ctx, targets, 1, false, nil, func(ctx context.Context, k *kind, t *Target) error {
+			mu.Lock()
+			defer mu.Unlock()
+			processed = append(processed, t.ID)
+			if t.ID == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+		require.ElementsMatch(t, []types.ID{1, 4, 5}, processed)
+		require.Error(t, results[0].Err)
+		require.ErrorIs(t, results[1].Err, ErrSkipped)
+		require.ErrorIs(t, results[2].Err, ErrSkipped)
+		require.NoError(t, results[3].Err)
+		require.NoError(t, results[4].Err)
+	})
+
+	t.Run("skip after failed find", func(t *testing.T) {
+		results := run(ctx, targets, 1, false, map[int][]string{1: {"is1b"}}, func(ctx context.Context, k *kind, t *Target) error {
+			return nil
+		})
+		for _, r := range results[:4] {
+			require.NoError(t, r.Err)
+		}
+		require.ErrorIs(t, results[4].Err, ErrSkipped)
+
+		// ゾーンを特定できない場合は全てのゾーンが対象
+		results = run(ctx, targets, 1, false, map[int][]string{0: {""}}, func(ctx context.Context, k *kind, t *Target) error {
+			return nil
+		})
+		require.NoError(t, results[0].Err)
+		for _, r := range results[1:] {
+			require.ErrorIs(t, r.Err, ErrSkipped)
+		}
+	})
+}
+
+func TestFindFailures(t *testing.T) {
+	findErr := &Error{Errors: []*TargetError{
+		{Target: &Target{Kind: KindServer}, Err: &multizone.Error{Errors: []*multizone.ZoneError{{Zone: "is1b", Err: errors.New("failed")}}}},
+		{Target: &Target{Kind: KindSwitch}, Err: errors.New("failed")},
+	}}
+	require.Equal(t, map[int][]string{0: {"is1b"}, 2: {""}}, findFailures(findErr))
+	require.Empty(t, findFailures(nil))
+}
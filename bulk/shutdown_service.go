@@ -0,0 +1,41 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bulk
+
+import (
+	"context"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Shutdown Selectorに該当するサーバ/アプライアンスをシャットダウンする
+//
+// 電源操作をサポートしない種別のリソースは対象外となる。
+func (s *Service) Shutdown(req *ShutdownRequest) ([]*Result, error) {
+	return s.ShutdownWithContext(context.Background(), req)
+}
+
+func (s *Service) ShutdownWithContext(ctx context.Context, req *ShutdownRequest) (_ []*Result, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Shutdown", Resource: "Bulk", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Shutdown", Resource: "Bulk", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	return s.selectAndRun(ctx, &req.Selector, req.Parallelism, false, func(k *kind) bool { return k.shutdown != nil }, func(ctx context.Context, k *kind, t *Target) error {
+		return k.shutdown(ctx, s.caller, t, req.ForceShutdown)
+	})
+}
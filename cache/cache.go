@@ -0,0 +1,221 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+)
+
+// DefaultTTL TTL省略時のキャッシュの有効期間
+const DefaultTTL = time.Hour
+
+// Cache プランやゾーンなど変更頻度の低いリソースの参照結果を保持するTTL付きのキャッシュ
+//
+// Callerでラップしたiaas.APICallerを各サービスやビルダーに渡すことで、
+// 同じCacheを用いる全ての呼び出しでキャッシュが共有される。
+type Cache struct {
+	ttl       time.Duration
+	resources map[Resource]bool
+	now       func() time.Time
+
+	mu       sync.Mutex
+	entries  map[string]*entry
+	inflight map[string]*call
+	// generation キャッシュを破棄するたびに加算される、破棄前に開始した取得処理の結果は保存しない
+	generation uint64
+}
+
+type entry struct {
+	resource  Resource
+	data      []byte
+	expiresAt time.Time
+}
+
+type call struct {
+	resource   Resource
+	generation uint64
+	done       chan struct{}
+	data       []byte
+	err        error
+	// canceled 取得処理を開始した呼び出し元のcontextが終了していたか
+	canceled bool
+}
+
+// New Cacheを返す
+//
+// ttlが0の場合はDefaultTTL、resourcesを省略した場合はAllResourcesが対象となる。
+func New(ttl time.Duration, resources ...Resource) *Cache {
+	if ttl <= 0 {
+		ttl = DefaultTTL
+	}
+	if len(resources) == 0 {
+		resources = AllResources
+	}
+	c := &Cache{
+		ttl:       ttl,
+		resources: map[Resource]bool{},
+		now:       time.Now,
+		entries:   map[string]*entry{},
+		inflight:  map[string]*call{},
+	}
+	for _, r := range resources {
+		c.resources[r] = true
+	}
+	return c
+}
+
+// Invalidate 全てのキャッシュを破棄する
+//
+// 実行中の取得処理の結果も保存されない
+func (c *Cache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = map[string]*entry{}
+	c.inflight = map[string]*call{}
+	c.generation++
+}
+
+// InvalidateResource 指定したリソースのキャッシュを破棄する
+//
+// 実行中の取得処理の結果も保存されない
+func (c *Cache) InvalidateResource(resource Resource) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, e := range c.entries {
+		if e.resource == resource {
+			delete(c.entries, key)
+		}
+	}
+	for key, cl := range c.inflight {
+		if cl.resource == resource {
+			delete(c.inflight, key)
+		}
+	}
+	c.generation++
+}
+
+// Caller キャッシュ対象のリソースの参照(GET)をキャッシュするiaas.APICallerを返す
+func (c *Cache) Caller(caller iaas.APICaller) iaas.APICaller {
+	return &cachedCaller{cache: c, caller: caller}
+}
+
+type cachedCaller struct {
+	cache  *Cache
+	caller iaas.APICaller
+}
+
+// Unwrap ラップしているiaas.APICallerを返す
+func (c *cachedCaller) Unwrap() iaas.APICaller {
+	return c.caller
+}
+
+func (c *cachedCaller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	if method != http.MethodGet {
+		return c.caller.Do(ctx, method, uri, body)
+	}
+	resource, ok := resourceFromURL(uri)
+	if !ok || !c.cache.resources[resource] {
+		return c.caller.Do(ctx, method, uri, body)
+	}
+
+	var rawBody []byte
+	if body != nil {
+		v, err := json.Marshal(body)
+		if err != nil {
+			return c.caller.Do(ctx, method, uri, body)
+		}
+		rawBody = v
+	}
+	// アーカイブは検索条件がパブリックアーカイブに限定されている場合、またはIDでの参照結果がパブリックアーカイブの場合のみキャッシュする
+	var cacheable func(data []byte) bool
+	if resource == ResourcePublicArchive && !isPublicArchiveCondition(rawBody) {
+		if !isReadURL(uri, resource) {
+			return c.caller.Do(ctx, method, uri, body)
+		}
+		cacheable = isPublicArchive
+	}
+
+	key := uri + "\n" + string(rawBody)
+	return c.cache.get(ctx, resource, key, cacheable, func(ctx context.Context) ([]byte, error) {
+		return c.caller.Do(ctx, method, uri, body)
+	})
+}
+
+// get キャッシュから値を返す、存在しない場合はfetchの結果を保存して返す
+//
+// 同じキーに対する取得処理が実行中の場合はその結果を待ち合わせる。
+// 実行中の取得処理が呼び出し元のcontextの終了により失敗した場合は、改めて取得する。
+// cacheableが指定されている場合、trueを返した結果のみ保存する。
+func (c *Cache) get(ctx context.Context, resource Resource, key string, cacheable func([]byte) bool, fetch func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	for {
+		c.mu.Lock()
+		if e, ok := c.entries[key]; ok {
+			if c.now().Before(e.expiresAt) {
+				c.mu.Unlock()
+				return copyBytes(e.data), nil
+			}
+			delete(c.entries, key)
+		}
+		cl, ok := c.inflight[key]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+
+		select {
+		case <-cl.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if cl.err != nil && cl.canceled && ctx.Err() == nil {
+			continue
+		}
+		return copyBytes(cl.data), cl.err
+	}
+
+	cl := &call{resource: resource, generation: c.generation, done: make(chan struct{})}
+	c.inflight[key] = cl
+	c.mu.Unlock()
+
+	cl.data, cl.err = fetch(ctx)
+	cl.canceled = ctx.Err() != nil
+
+	c.mu.Lock()
+	if c.inflight[key] == cl {
+		delete(c.inflight, key)
+	}
+	if cl.err == nil && cl.generation == c.generation && (cacheable == nil || cacheable(cl.data)) {
+		c.entries[key] = &entry{resource: resource, data: copyBytes(cl.data), expiresAt: c.now().Add(c.ttl)}
+	}
+	c.mu.Unlock()
+	close(cl.done)
+
+	return copyBytes(cl.data), cl.err
+}
+
+func copyBytes(data []byte) []byte {
+	if data == nil {
+		return nil
+	}
+	copied := make([]byte, len(data))
+	copy(copied, data)
+	return copied
+}
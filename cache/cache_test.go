@@ -0,0 +1,238 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/query"
+	"github.com/sacloud/iaas-api-go/ostype"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyCaller struct {
+	mu        sync.Mutex
+	calls     map[string]int
+	responses map[string]string
+}
+
+func newDummyCaller(responses map[string]string) *dummyCaller {
+	return &dummyCaller{calls: map[string]int{}, responses: responses}
+}
+
+func (c *dummyCaller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	resource, _ := resourceFromURL(uri)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[method+" "+string(resource)]++
+	return []byte(c.responses[string(resource)]), nil
+}
+
+func (c *dummyCaller) count(key string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls[key]
+}
+
+func TestCache_Plans(t *testing.T) {
+	ctx := context.Background()
+	dummy := newDummyCaller(map[string]string{
+		"product/server": `{"Total":1,"From":0,"Count":1,"ServerPlans":[{"ID":"100001001","Name":"plan","CPU":1,"MemoryMB":1024,"Commitment":"standard","Generation":100,"Availability":"available"}]}`,
+		"zone":           `{"Total":1,"From":0,"Count":1,"Zones":[{"ID":"31001","Name":"is1a"}]}`,
+	})
+	c := New(time.Minute)
+	caller := c.Caller(dummy)
+
+	for i := 0; i < 3; i++ {
+		plan, err := query.FindServerPlan(ctx, iaas.NewServerPlanOp(caller), "is1a", &query.FindServerPlanRequest{CPU: 1, MemoryGB: 1})
+		require.NoError(t, err)
+		require.Equal(t, "plan", plan.Name)
+
+		id, err := query.ZoneIDFromName(ctx, iaas.NewZoneOp(caller), "is1a")
+		require.NoError(t, err)
+		require.Equal(t, "31001", id.String())
+	}
+	require.Equal(t, 1, dummy.count("GET product/server"))
+	require.Equal(t, 1, dummy.count("GET zone"))
+
+	// 検索条件が異なる場合は別のキャッシュとなる
+	_, err := iaas.NewServerPlanOp(caller).Find(ctx, "tk1a", nil)
+	require.NoError(t, err)
+	require.Equal(t, 2, dummy.count("GET product/server"))
+
+	// 破棄
+	c.InvalidateResource(ResourceZone)
+	_, err = query.ZoneIDFromName(ctx, iaas.NewZoneOp(caller), "is1a")
+	require.NoError(t, err)
+	require.Equal(t, 2, dummy.count("GET zone"))
+	_, err = query.FindServerPlan(ctx, iaas.NewServerPlanOp(caller), "is1a", &query.FindServerPlanRequest{CPU: 1, MemoryGB: 1})
+	require.NoError(t, err)
+	require.Equal(t, 2, dummy.count("GET product/server"))
+
+	// 有効期限切れ
+	c.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	_, err = query.ZoneIDFromName(ctx, iaas.NewZoneOp(caller), "is1a")
+	require.NoError(t, err)
+	require.Equal(t, 3, dummy.count("GET zone"))
+}
+
+func TestCache_Archives(t *testing.T) {
+	ctx := context.Background()
+	dummy := newDummyCaller(map[string]string{
+		"archive": `{"Total":1,"From":0,"Count":1,"Archives":[{"ID":"112233445566","Name":"ubuntu","Scope":"shared"}],"Archive":{"ID":"112233445566","Name":"ubuntu","Scope":"shared"}}`,
+	})
+	caller := New(time.Minute).Caller(dummy)
+	archiveOp := iaas.NewArchiveOp(caller)
+
+	for i := 0; i < 2; i++ {
+		archive, err := query.FindArchiveByOSType(ctx, archiveOp, "is1a", ostype.Ubuntu)
+		require.NoError(t, err)
+		require.Equal(t, "ubuntu", archive.Name)
+	}
+	require.Equal(t, 1, dummy.count("GET archive"))
+
+	// パブリックアーカイブに限定されない検索はキャッシュしない
+	for i := 0; i < 2; i++ {
+		_, err := archiveOp.Find(ctx, "is1a", &iaas.FindCondition{})
+		require.NoError(t, err)
+	}
+	require.Equal(t, 3, dummy.count("GET archive"))
+
+	// 参照結果がパブリックアーカイブの場合はキャッシュする
+	for i := 0; i < 2; i++ {
+		_, err := archiveOp.Read(ctx, "is1a", 112233445566)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 4, dummy.count("GET archive"))
+
+	// 更新系の操作はキャッシュしない
+	for i := 0; i < 2; i++ {
+		require.NoError(t, archiveOp.Delete(ctx, "is1a", 112233445566))
+	}
+	require.Equal(t, 2, dummy.count("DELETE archive"))
+}
+
+func TestCache_UserArchive(t *testing.T) {
+	ctx := context.Background()
+	dummy := newDummyCaller(map[string]string{
+		"archive": `{"Archive":{"ID":"112233445566","Name":"user","Scope":"user"}}`,
+	})
+	archiveOp := iaas.NewArchiveOp(New(time.Minute).Caller(dummy))
+
+	for i := 0; i < 2; i++ {
+		_, err := archiveOp.Read(ctx, "is1a", 112233445566)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, dummy.count("GET archive"))
+}
+
+func TestCache_Concurrent(t *testing.T) {
+	dummy := newDummyCaller(map[string]string{
+		"region": `{"Total":0,"From":0,"Count":0,"Regions":[]}`,
+	})
+	caller := New(time.Minute).Caller(dummy)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := iaas.NewRegionOp(caller).Find(context.Background(), nil)
+			require.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	require.Equal(t, 1, dummy.count("GET region"))
+}
+
+func TestCache_InvalidateInflight(t *testing.T) {
+	ctx := context.Background()
+	c := New(time.Minute)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan []byte)
+	go func() {
+		data, err := c.get(ctx, ResourceZone, "key", nil, func(context.Context) ([]byte, error) {
+			close(started)
+			<-release
+			return []byte("old"), nil
+		})
+		require.NoError(t, err)
+		done <- data
+	}()
+	<-started
+
+	// 破棄後の参照は実行中の取得処理を待ち合わせない
+	c.InvalidateResource(ResourceZone)
+	data, err := c.get(ctx, ResourceZone, "key", nil, func(context.Context) ([]byte, error) {
+		return []byte("new"), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "new", string(data))
+
+	close(release)
+	require.Equal(t, "old", string(<-done))
+
+	// 破棄前に開始した取得処理の結果は保存されない
+	data, err = c.get(ctx, ResourceZone, "key", nil, func(context.Context) ([]byte, error) {
+		return []byte("fetched"), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "new", string(data))
+
+	c.Invalidate()
+	data, err = c.get(ctx, ResourceZone, "key", nil, func(context.Context) ([]byte, error) {
+		return []byte("fetched"), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, "fetched", string(data))
+}
+
+func TestCache_CanceledCaller(t *testing.T) {
+	c := New(time.Minute)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		_, err := c.get(ctx, ResourceZone, "key", nil, func(ctx context.Context) ([]byte, error) {
+			close(started)
+			<-ctx.Done()
+			return nil, ctx.Err()
+		})
+		done <- err
+	}()
+	<-started
+
+	waited := make(chan []byte)
+	go func() {
+		data, err := c.get(context.Background(), ResourceZone, "key", nil, func(context.Context) ([]byte, error) {
+			return []byte("fetched"), nil
+		})
+		require.NoError(t, err)
+		waited <- data
+	}()
+	time.Sleep(10 * time.Millisecond)
+
+	// 取得処理を開始した呼び出し元のキャンセルは待ち合わせていた呼び出し元には返さない
+	cancel()
+	require.ErrorIs(t, <-done, context.Canceled)
+	require.Equal(t, "fetched", string(<-waited))
+}
@@ -0,0 +1,115 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/sacloud/iaas-service-go/serviceutil"
+)
+
+// Resource キャッシュ対象のリソース
+type Resource string
+
+// キャッシュ対象にできるリソース、値はAPIのパス
+const (
+	ResourceServerPlan      Resource = "product/server"
+	ResourceDiskPlan        Resource = "product/disk"
+	ResourceInternetPlan    Resource = "product/internet"
+	ResourcePrivateHostPlan Resource = "product/privatehost"
+	ResourceLicenseInfo     Resource = "product/license"
+	ResourceZone            Resource = "zone"
+	ResourceRegion          Resource = "region"
+	// ResourcePublicArchive パブリックアーカイブ(スコープがshared)のアーカイブのみが対象
+	ResourcePublicArchive Resource = "archive"
+)
+
+// AllResources キャッシュ対象にできる全てのリソース
+var AllResources = []Resource{
+	ResourceServerPlan,
+	ResourceDiskPlan,
+	ResourceInternetPlan,
+	ResourcePrivateHostPlan,
+	ResourceLicenseInfo,
+	ResourceZone,
+	ResourceRegion,
+	ResourcePublicArchive,
+}
+
+// resourceFromURL APIのURLから対象のリソースを判定する
+//
+// 例: https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/product/server/1001001
+func resourceFromURL(uri string) (Resource, bool) {
+	path, ok := resourcePath(uri)
+	if !ok {
+		return "", false
+	}
+	for _, r := range AllResources {
+		if path == string(r) || strings.HasPrefix(path, string(r)+"/") {
+			return r, true
+		}
+	}
+	return "", false
+}
+
+// isReadURL URLがIDを指定したリソースの参照か
+func isReadURL(uri string, resource Resource) bool {
+	path, ok := resourcePath(uri)
+	if !ok {
+		return false
+	}
+	id := strings.TrimPrefix(path, string(resource)+"/")
+	return id != path && id != "" && !strings.Contains(id, "/")
+}
+
+// resourcePath APIのURLからapi/cloud/1.1/以降のパスを返す
+func resourcePath(uri string) (string, bool) {
+	u, ok := serviceutil.ParseAPIURL(uri)
+	if !ok {
+		return "", false
+	}
+	return u.Path(), true
+}
+
+// isPublicArchiveCondition 検索条件がパブリックアーカイブに限定されているか
+func isPublicArchiveCondition(body []byte) bool {
+	var v struct {
+		Filter map[string]interface{}
+	}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return false
+	}
+	switch scope := v.Filter["Scope"].(type) {
+	case string:
+		return scope == "shared"
+	case []interface{}:
+		return len(scope) == 1 && scope[0] == "shared"
+	}
+	return false
+}
+
+// isPublicArchive レスポンスがパブリックアーカイブのものか
+func isPublicArchive(data []byte) bool {
+	var v struct {
+		Archive *struct {
+			Scope string
+		}
+	}
+	if err := json.Unmarshal(data, &v); err != nil || v.Archive == nil {
+		return false
+	}
+	return v.Archive.Scope == "shared"
+}
@@ -0,0 +1,120 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/sacloud/iaas-api-go"
+	"gopkg.in/yaml.v3"
+)
+
+// Interaction 1回のAPI呼び出しの記録
+type Interaction struct {
+	Method string `yaml:"method"`
+	URL    string `yaml:"url"`
+	// Request リクエストボディ(JSON)、ボディがない場合は空
+	Request string `yaml:"request,omitempty"`
+	// Response レスポンスボディ(JSON)、エラーの場合は空
+	Response string `yaml:"response,omitempty"`
+	// Error API呼び出しがエラーとなった場合のエラー
+	Error *Error `yaml:"error,omitempty"`
+}
+
+// Error 記録されたエラー
+type Error struct {
+	// StatusCode APIがエラーを返した場合のレスポンスコード
+	StatusCode int `yaml:"statusCode,omitempty"`
+	// APIError APIが返したエラーの内容
+	APIError *iaas.APIErrorResponse `yaml:"apiError,omitempty"`
+	// Message API以外のエラー(通信エラーなど)の場合のメッセージ
+	Message string `yaml:"message,omitempty"`
+}
+
+func newError(err error) *Error {
+	var apiErr iaas.APIError
+	if errors.As(err, &apiErr) {
+		return &Error{StatusCode: apiErr.ResponseCode(), APIError: apiErr.OrigErr()}
+	}
+	return &Error{Message: err.Error()}
+}
+
+// Err 記録されたエラーを元にerrorを返す
+//
+// APIが返したエラーの場合はiaas.APIErrorを返すため、iaas.IsNotFoundErrorなどで判定できる
+func (e *Error) Err(method, uri string) error {
+	if e.StatusCode == 0 && e.APIError == nil {
+		return errors.New(e.Message)
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return err
+	}
+	return iaas.NewAPIError(method, u, e.StatusCode, e.APIError)
+}
+
+// Cassette 記録されたAPI呼び出しの一覧
+type Cassette struct {
+	Interactions []*Interaction `yaml:"interactions"`
+}
+
+// Load pathからCassetteを読み込む
+func Load(path string) (*Cassette, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, err
+	}
+	cassette := &Cassette{}
+	if err := yaml.Unmarshal(data, cassette); err != nil {
+		return nil, err
+	}
+	return cassette, nil
+}
+
+// Save pathへCassetteを書き込む
+func (c *Cassette) Save(path string) error {
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(c); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o600)
+}
+
+// formatJSON 比較や差分の確認がしやすいようJSONを整形する
+//
+// JSONとして解釈できない場合はそのまま返す
+func formatJSON(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return buf.String()
+}
@@ -0,0 +1,46 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// Matcher 実際のリクエストrと記録されたInteraction iが一致するかを判定する
+type Matcher func(r, i *Interaction) bool
+
+// MatchMethodAndURL メソッドとURLが一致する場合に一致とするMatcher
+//
+// モニタリングの取得期間など、実行ごとに変わる値をリクエストボディに含む場合に利用する
+func MatchMethodAndURL(r, i *Interaction) bool {
+	return r.Method == i.Method && r.URL == i.URL
+}
+
+// DefaultMatcher メソッド、URL、リクエストボディ(JSONとして比較)が一致する場合に一致とするMatcher
+func DefaultMatcher(r, i *Interaction) bool {
+	return MatchMethodAndURL(r, i) && equalJSON(r.Request, i.Request)
+}
+
+func equalJSON(a, b string) bool {
+	if a == b {
+		return true
+	}
+	var decodedA, decodedB interface{}
+	if json.Unmarshal([]byte(a), &decodedA) != nil || json.Unmarshal([]byte(b), &decodedB) != nil {
+		return false
+	}
+	return reflect.DeepEqual(decodedA, decodedB)
+}
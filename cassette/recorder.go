@@ -0,0 +1,221 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/packages-go/validate"
+)
+
+// ErrInteractionNotFound 再生時に一致する記録が見つからなかった
+var ErrInteractionNotFound = errors.New("interaction not found")
+
+// Mode Recorderの動作モード
+type Mode string
+
+const (
+	// ModeRecordOnce Pathが存在する場合は再生、存在しない場合は記録する
+	ModeRecordOnce = Mode("")
+	// ModeRecord 常に実際のAPIを呼び出して記録する、既存のファイルは上書きされる
+	ModeRecord = Mode("record")
+	// ModeReplay 常に記録を再生する、実際のAPIは呼び出さない
+	ModeReplay = Mode("replay")
+)
+
+// Options Recorderの設定
+type Options struct {
+	// Path 記録ファイルのパス
+	Path string `validate:"required"`
+	// Mode 動作モード
+	Mode Mode `validate:"omitempty,oneof=record replay"`
+	// Matcher 再生時にリクエストと記録を照合する関数、省略時はDefaultMatcher
+	Matcher Matcher
+	// SensitiveKeys 記録前に値を置き換えるフィールド名、省略時はDefaultSensitiveKeys
+	SensitiveKeys []string
+	// Scrubbers SensitiveKeysによる置き換えの後に適用されるScrubber
+	Scrubbers []Scrubber
+}
+
+func (o *Options) Validate() error {
+	return validate.New().Struct(o)
+}
+
+// Recorder API呼び出しを記録/再生するiaas.APICaller
+//
+// 記録時はラップしたAPICallerを呼び出して結果を記録し、Stopでファイルへ書き込む。
+// 再生時は記録された順に、リクエストと一致するまだ再生していない記録を返すため、
+// 同じリクエストを繰り返すポーリングでも記録時と同じ順でレスポンスが返る。
+//
+// testutil.SingletonAPICallerを利用するとAPIの呼び出しがフェイクに置き換えられるため、同じテストバイナリ内では併用しないこと
+type Recorder struct {
+	caller    iaas.APICaller
+	options   Options
+	scrubbers []Scrubber
+	recording bool
+
+	mu       sync.Mutex
+	cassette *Cassette
+	used     []bool
+}
+
+var _ iaas.APICaller = (*Recorder)(nil)
+
+// New Recorderを返す
+//
+// 再生時はcallerは利用されないためnilでもよい
+func New(caller iaas.APICaller, options *Options) (*Recorder, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+
+	r := &Recorder{caller: caller, options: *options}
+	if r.options.Matcher == nil {
+		r.options.Matcher = DefaultMatcher
+	}
+	keys := r.options.SensitiveKeys
+	if keys == nil {
+		keys = DefaultSensitiveKeys
+	}
+	r.scrubbers = append([]Scrubber{ScrubKeys(keys...)}, r.options.Scrubbers...)
+
+	switch r.options.Mode {
+	case ModeRecord:
+		r.recording = true
+	case ModeReplay:
+	default:
+		if _, err := os.Stat(r.options.Path); errors.Is(err, os.ErrNotExist) {
+			r.recording = true
+		}
+	}
+
+	if r.recording {
+		if caller == nil {
+			return nil, errors.New("caller is required for recording")
+		}
+		r.cassette = &Cassette{}
+		return r, nil
+	}
+
+	cassette, err := Load(r.options.Path)
+	if err != nil {
+		return nil, err
+	}
+	r.cassette = cassette
+	r.used = make([]bool, len(cassette.Interactions))
+	return r, nil
+}
+
+// Recording 記録中の場合true
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Unwrap ラップしているiaas.APICallerを返す
+func (r *Recorder) Unwrap() iaas.APICaller {
+	return r.caller
+}
+
+// Do iaas.APICallerの実装
+func (r *Recorder) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	interaction := &Interaction{Method: method, URL: uri}
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		interaction.Request = formatJSON(data)
+	}
+
+	if r.recording {
+		return r.record(ctx, interaction, body)
+	}
+	return r.replay(interaction)
+}
+
+func (r *Recorder) record(ctx context.Context, interaction *Interaction, body interface{}) ([]byte, error) {
+	data, err := r.caller.Do(ctx, interaction.Method, interaction.URL, body)
+	if err != nil {
+		interaction.Error = newError(err)
+	} else {
+		interaction.Response = formatJSON(data)
+	}
+	r.scrub(interaction)
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.mu.Unlock()
+
+	return data, err
+}
+
+func (r *Recorder) replay(interaction *Interaction) ([]byte, error) {
+	r.scrub(interaction)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, recorded := range r.cassette.Interactions {
+		if r.used[i] || !r.options.Matcher(interaction, recorded) {
+			continue
+		}
+		r.used[i] = true
+		if recorded.Error != nil {
+			return nil, recorded.Error.Err(recorded.Method, recorded.URL)
+		}
+		return []byte(recorded.Response), nil
+	}
+	return nil, fmt.Errorf("%w: %s %s", ErrInteractionNotFound, interaction.Method, interaction.URL)
+}
+
+func (r *Recorder) scrub(interaction *Interaction) {
+	for _, scrubber := range r.scrubbers {
+		scrubber(interaction)
+	}
+}
+
+// Unused 再生時に一度も再生されなかった記録を返す
+//
+// テストの終了時に呼び出すことで、記録時と異なるAPI呼び出しとなっていないかを確認できる
+func (r *Recorder) Unused() []*Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []*Interaction
+	for i, used := range r.used {
+		if !used {
+			unused = append(unused, r.cassette.Interactions[i])
+		}
+	}
+	return unused
+}
+
+// Stop 記録中の場合は記録をファイルへ書き込む
+func (r *Recorder) Stop() error {
+	if !r.recording {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cassette.Save(r.options.Path)
+}
@@ -0,0 +1,55 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cassette
+
+import (
+	"encoding/json"
+
+	"github.com/sacloud/iaas-service-go/serviceutil"
+)
+
+// ScrubbedValue 秘匿情報を置き換える値
+const ScrubbedValue = "(scrubbed)"
+
+// DefaultSensitiveKeys 値を置き換えるフィールド名のデフォルト値(小文字、部分一致)
+var DefaultSensitiveKeys = serviceutil.DefaultSensitiveKeys
+
+// Scrubber 記録前のInteractionから秘匿情報を取り除く
+//
+// 再生時にはリクエストの照合前に、実際のリクエストから作成したInteractionにも適用される
+type Scrubber func(i *Interaction)
+
+// ScrubKeys Request/Responseのうち、名前にkeysのいずれかを含む(大文字小文字は区別しない)フィールドの値を置き換えるScrubberを返す
+func ScrubKeys(keys ...string) Scrubber {
+	return func(i *Interaction) {
+		i.Request = scrubJSON(i.Request, keys)
+		i.Response = scrubJSON(i.Response, keys)
+	}
+}
+
+func scrubJSON(data string, keys []string) string {
+	if data == "" {
+		return data
+	}
+	var decoded interface{}
+	if err := json.Unmarshal([]byte(data), &decoded); err != nil {
+		return data
+	}
+	scrubbed, err := json.Marshal(serviceutil.MaskSensitiveValues(decoded, keys, ScrubbedValue))
+	if err != nil {
+		return data
+	}
+	return formatJSON(scrubbed)
+}
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) CloseFTP(req *CloseFTPRequest) error {
 	return s.CloseFTPWithContext(context.Background(), req)
 }
 
-func (s *Service) CloseFTPWithContext(ctx context.Context, req *CloseFTPRequest) error {
+func (s *Service) CloseFTPWithContext(ctx context.Context, req *CloseFTPRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "CloseFTP", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "CloseFTP", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewCDROMOp(s.caller)
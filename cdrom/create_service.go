@@ -16,12 +16,12 @@ package cdrom
 
 import (
 	"context"
-	"fmt"
 	"io"
 	"os"
 
 	"github.com/sacloud/ftps"
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/packages-go/size"
 )
 
@@ -29,9 +29,12 @@ func (s *Service) Create(req *CreateRequest) (*iaas.CDROM, error) {
 	return s.CreateWithContext(context.Background(), req)
 }
 
-func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (*iaas.CDROM, error) {
+func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (_ *iaas.CDROM, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Create", Resource: "CDROM", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Create", Resource: "CDROM", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	var reader io.Reader
@@ -41,7 +44,7 @@ func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (*i
 	default:
 		file, err := os.Open(req.SourcePath)
 		if err != nil {
-			return nil, fmt.Errorf("reading source file[%s] failed: %s", req.SourcePath, err)
+			return nil, &service.Error{Op: "Create", Resource: "CDROM", Zone: req.Zone, Err: err}
 		}
 		defer file.Close()
 		reader = file
@@ -16,6 +16,7 @@ package cdrom
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -23,30 +24,34 @@ import (
 	"github.com/sacloud/ftps"
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Upload(req *UploadRequest) error {
 	return s.UploadWithContext(context.Background(), req)
 }
 
-func (s *Service) UploadWithContext(ctx context.Context, req *UploadRequest) error {
+func (s *Service) UploadWithContext(ctx context.Context, req *UploadRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Upload", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "Upload", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewCDROMOp(s.caller)
 	resource, err := client.Read(ctx, req.Zone, req.ID)
 	if err != nil {
-		return fmt.Errorf("reading CDROM[%s] failed: %s", req.ID, err)
+		return &service.Error{Op: "Upload", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Err: err}
 	}
 
 	if resource.Scope != types.Scopes.User {
-		return fmt.Errorf("CDROM[%s] is not allowed to download", req.ID)
+		return &service.Error{Op: "Upload", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindConflict, Err: errors.New("CDROM is not allowed to download")}
 	}
 
 	ftpServer, err := client.OpenFTP(ctx, req.Zone, req.ID, &iaas.OpenFTPRequest{ChangePassword: true})
 	if err != nil {
-		return fmt.Errorf("requesting FTP server information failed: %s", err)
+		return &service.Error{Op: "Upload", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Err: fmt.Errorf("requesting FTP server information failed: %w", err)}
 	}
 
 	ftpsClient := ftps.NewClient(ftpServer.User, ftpServer.Password, ftpServer.HostName)
@@ -60,19 +65,19 @@ func (s *Service) UploadWithContext(ctx context.Context, req *UploadRequest) err
 	default:
 		f, err := os.Open(req.Path)
 		if err != nil {
-			return fmt.Errorf("opening upload file failed: %s", err)
+			return &service.Error{Op: "Upload", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Err: fmt.Errorf("opening upload file failed: %w", err)}
 		}
 		defer f.Close()
 		reader = f
 	}
 
 	if err := ftpsClient.UploadReader("upload.raw", reader); err != nil {
-		return fmt.Errorf("uploading file failed: %s", err)
+		return &service.Error{Op: "Upload", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Err: fmt.Errorf("uploading file failed: %w", err)}
 	}
 
 	// close FTP
 	if err := client.CloseFTP(ctx, req.Zone, resource.ID); err != nil {
-		return fmt.Errorf("closing FTP server failed: %s", err)
+		return &service.Error{Op: "Upload", Resource: "CDROM", Zone: req.Zone, ID: req.ID, Err: fmt.Errorf("closing FTP server failed: %w", err)}
 	}
 	return nil
 }
@@ -0,0 +1,89 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSet Buildを行った場合の変更内容を返す
+//
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context) (*service.ChangeSet, error) {
+	current := &iaas.CertificateAuthority{}
+	var currentClients []*iaas.CertificateAuthorityClient
+	var currentServers []*iaas.CertificateAuthorityServer
+	var cs *service.ChangeSet
+
+	if b.ID.IsEmpty() {
+		cs = service.NewCreateChangeSet()
+		cs.Create("Country", b.Country)
+		cs.Create("Organization", b.Organization)
+		cs.Create("OrganizationUnit", b.OrganizationUnit)
+		cs.Create("CommonName", b.CommonName)
+		cs.Create("NotAfter", b.NotAfter)
+	} else {
+		ca, err := b.Client.Read(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		clients, err := b.Client.ListClients(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		if clients != nil {
+			currentClients = clients.CertificateAuthority
+		}
+		servers, err := b.Client.ListServers(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		if servers != nil {
+			currentServers = servers.CertificateAuthority
+		}
+		cs = service.NewUpdateChangeSet(ca.ID)
+		current = ca
+	}
+
+	cs.Set("Name", current.Name, b.Name)
+	cs.Set("Description", current.Description, b.Description)
+	cs.Set("Tags", current.Tags, b.Tags)
+	cs.Set("IconID", current.IconID, b.IconID)
+
+	for _, client := range b.deletedClients(currentClients) {
+		cs.Delete(fmt.Sprintf("Clients[%s]", client.ID), client.Subject)
+	}
+	for _, client := range b.updatedClients(currentClients) {
+		cs.Update(fmt.Sprintf("Clients[%s].Hold", client.ID), !client.Hold, client.Hold)
+	}
+	for i, client := range b.createdClients() {
+		cs.Create(fmt.Sprintf("Clients[new%d]", i), client.CommonName)
+	}
+
+	for _, server := range b.deletedServers(currentServers) {
+		cs.Delete(fmt.Sprintf("Servers[%s]", server.ID), server.Subject)
+	}
+	for _, server := range b.updatedServers(currentServers) {
+		cs.Update(fmt.Sprintf("Servers[%s].Hold", server.ID), !server.Hold, server.Hold)
+	}
+	for i, server := range b.createdServers() {
+		cs.Create(fmt.Sprintf("Servers[new%d]", i), server.CommonName)
+	}
+	return cs, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	builder2 "github.com/sacloud/iaas-service-go/certificateauthority/builder"
 )
 
@@ -25,9 +26,13 @@ func (s *Service) Read(req *ReadRequest) (*builder2.CertificateAuthority, error)
 	return s.ReadWithContext(context.Background(), req)
 }
 
-func (s *Service) ReadWithContext(ctx context.Context, req *ReadRequest) (*builder2.CertificateAuthority, error) {
+func (s *Service) ReadWithContext(ctx context.Context, req *ReadRequest) (_ *builder2.CertificateAuthority, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Read", Resource: "CertificateAuthority", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Read", Resource: "CertificateAuthority", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
+
 	return builder2.Read(ctx, iaas.NewCertificateAuthorityOp(s.caller), req.ID)
 }
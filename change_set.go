@@ -0,0 +1,194 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iaas
+
+import (
+	"reflect"
+
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/packages-go/objutil"
+)
+
+// ChangeAction Applyで行われる操作の種別
+type ChangeAction string
+
+const (
+	// ChangeActionNone 変更なし
+	ChangeActionNone ChangeAction = "none"
+	// ChangeActionCreate 新規作成
+	ChangeActionCreate ChangeAction = "create"
+	// ChangeActionUpdate 更新
+	ChangeActionUpdate ChangeAction = "update"
+	// ChangeActionReplace 再作成(IDが変わる)
+	ChangeActionReplace ChangeAction = "replace"
+	// ChangeActionDelete 削除
+	ChangeActionDelete ChangeAction = "delete"
+)
+
+const (
+	// SensitiveValue パスワードなど値を表示できない項目に設定される値
+	SensitiveValue = "(sensitive)"
+	// UnknownValue 新たに作成されるリソースのIDなど、Apply後に確定する項目に設定される値
+	UnknownValue = "(known after apply)"
+)
+
+// FieldChange フィールドごとの変更内容
+type FieldChange struct {
+	Field   string
+	Action  ChangeAction
+	Current interface{}
+	Desired interface{}
+}
+
+// ChangeSet Applyを行った場合の変更内容
+//
+// Planなどで変更内容の確認に利用する。ChangeSetの作成時には更新系APIの呼び出しは行われない。
+type ChangeSet struct {
+	// Action リソース全体として行われる操作
+	Action ChangeAction
+	// ID 更新対象のリソースのID、新規作成時は空
+	ID types.ID
+	// UpdateLevel 更新時に必要な変更のレベル、新規作成時はUpdateLevelNone
+	UpdateLevel UpdateLevel
+	// Changes フィールドごとの変更内容
+	Changes []*FieldChange
+}
+
+// NewCreateChangeSet 新規作成を表すChangeSetを返す
+func NewCreateChangeSet() *ChangeSet {
+	return &ChangeSet{Action: ChangeActionCreate}
+}
+
+// NewUpdateChangeSet 既存リソースの更新を表すChangeSetを返す
+//
+// 変更が追加されるまではActionはChangeActionNoneとなる
+func NewUpdateChangeSet(id types.ID) *ChangeSet {
+	return &ChangeSet{Action: ChangeActionNone, ID: id}
+}
+
+// Create 新たに設定される値を追加する。値が空の場合は何もしない
+func (c *ChangeSet) Create(field string, desired interface{}) {
+	if objutil.IsEmpty(desired) {
+		return
+	}
+	c.add(&FieldChange{Field: field, Action: ChangeActionCreate, Desired: desired})
+}
+
+// Update currentとdesiredが異なる場合に更新を追加する。追加した場合はtrueを返す
+func (c *ChangeSet) Update(field string, current, desired interface{}) bool {
+	if IsSameValue(current, desired) {
+		return false
+	}
+	c.add(&FieldChange{Field: field, Action: ChangeActionUpdate, Current: current, Desired: desired})
+	return true
+}
+
+// Set 新規作成時はCreate、更新時はUpdateとして値を追加する
+func (c *ChangeSet) Set(field string, current, desired interface{}) {
+	if c.Action == ChangeActionCreate {
+		c.Create(field, desired)
+		return
+	}
+	c.Update(field, current, desired)
+}
+
+// SetSensitive パスワードなどの値をSensitiveValueに置き換えてSetと同様に追加する
+func (c *ChangeSet) SetSensitive(field string, current, desired interface{}) {
+	if objutil.IsEmpty(desired) || IsSameValue(current, desired) {
+		return
+	}
+	if c.Action == ChangeActionCreate {
+		c.Create(field, SensitiveValue)
+		return
+	}
+	c.add(&FieldChange{Field: field, Action: ChangeActionUpdate, Current: SensitiveValue, Desired: SensitiveValue})
+}
+
+// Replace currentとdesiredが異なる場合に再作成を追加する。追加した場合はtrueを返す
+//
+// 再作成を伴う(リソースのIDが変わる)変更に利用する
+func (c *ChangeSet) Replace(field string, current, desired interface{}) bool {
+	if IsSameValue(current, desired) {
+		return false
+	}
+	c.add(&FieldChange{Field: field, Action: ChangeActionReplace, Current: current, Desired: desired})
+	return true
+}
+
+// Delete 削除される値を追加する
+func (c *ChangeSet) Delete(field string, current interface{}) {
+	c.add(&FieldChange{Field: field, Action: ChangeActionDelete, Current: current})
+}
+
+// Merge 子リソースのChangeSetの変更内容をprefixを付与して取り込む
+func (c *ChangeSet) Merge(prefix string, child *ChangeSet) {
+	if child == nil {
+		return
+	}
+	for _, change := range child.Changes {
+		c.add(&FieldChange{
+			Field:   prefix + "." + change.Field,
+			Action:  change.Action,
+			Current: change.Current,
+			Desired: change.Desired,
+		})
+	}
+	if child.UpdateLevel == UpdateLevelNeedShutdown {
+		c.NeedShutdown()
+	}
+}
+
+// NeedShutdown シャットダウンが必要な変更であることを設定する
+func (c *ChangeSet) NeedShutdown() {
+	if c.Action == ChangeActionCreate {
+		return
+	}
+	if c.Action == ChangeActionNone {
+		c.Action = ChangeActionUpdate
+	}
+	c.UpdateLevel = UpdateLevelNeedShutdown
+}
+
+// HasChanges 変更があるか
+func (c *ChangeSet) HasChanges() bool {
+	return c.Action != ChangeActionNone
+}
+
+func (c *ChangeSet) add(change *FieldChange) {
+	c.Changes = append(c.Changes, change)
+
+	if c.Action == ChangeActionCreate {
+		return
+	}
+	switch {
+	case change.Action == ChangeActionReplace:
+		c.Action = ChangeActionReplace
+	case c.Action == ChangeActionNone:
+		c.Action = ChangeActionUpdate
+	}
+	if c.UpdateLevel == UpdateLevelNone {
+		c.UpdateLevel = UpdateLevelSimple
+	}
+}
+
+// IsSameValue 2つの値が同じであるか
+//
+// 両方が空(nilや長さ0のスライスなど)の場合も同じとみなす
+func IsSameValue(v1, v2 interface{}) bool {
+	if objutil.IsEmpty(v1) && objutil.IsEmpty(v2) {
+		return true
+	}
+	return reflect.DeepEqual(v1, v2)
+}
@@ -0,0 +1,65 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iaas
+
+import (
+	"testing"
+
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChangeSet(t *testing.T) {
+	t.Run("create", func(t *testing.T) {
+		cs := NewCreateChangeSet()
+		cs.Create("Name", "foo")
+		cs.Create("Description", "")
+		cs.SetSensitive("Password", nil, "secret")
+
+		require.Equal(t, ChangeActionCreate, cs.Action)
+		require.Len(t, cs.Changes, 2)
+		require.Equal(t, SensitiveValue, cs.Changes[1].Desired)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		cs := NewUpdateChangeSet(types.ID(1))
+		require.False(t, cs.Update("Name", "foo", "foo"))
+		require.False(t, cs.Update("Tags", types.Tags{}, nil))
+		require.Equal(t, ChangeActionNone, cs.Action)
+		require.Equal(t, UpdateLevelNone, cs.UpdateLevel)
+
+		require.True(t, cs.Update("Name", "foo", "bar"))
+		require.Equal(t, ChangeActionUpdate, cs.Action)
+		require.Equal(t, UpdateLevelSimple, cs.UpdateLevel)
+
+		cs.NeedShutdown()
+		require.Equal(t, UpdateLevelNeedShutdown, cs.UpdateLevel)
+
+		require.True(t, cs.Replace("CPU", 1, 2))
+		require.Equal(t, ChangeActionReplace, cs.Action)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		child := NewUpdateChangeSet(types.ID(2))
+		child.Update("Name", "foo", "bar")
+		child.NeedShutdown()
+
+		cs := NewUpdateChangeSet(types.ID(1))
+		cs.Merge("Disks[0]", child)
+
+		require.Equal(t, "Disks[0].Name", cs.Changes[0].Field)
+		require.Equal(t, UpdateLevelNeedShutdown, cs.UpdateLevel)
+	})
+}
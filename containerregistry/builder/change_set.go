@@ -0,0 +1,81 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSet Buildを行った場合の変更内容を返す
+//
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context) (*service.ChangeSet, error) {
+	current := &iaas.ContainerRegistry{}
+	var currentUsers []*iaas.ContainerRegistryUser
+	var cs *service.ChangeSet
+
+	if b.ID.IsEmpty() {
+		cs = service.NewCreateChangeSet()
+		cs.Create("SubDomainLabel", b.SubDomainLabel)
+	} else {
+		reg, err := b.Client.Read(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		if reg.SubDomainLabel != b.SubDomainLabel {
+			return nil, errors.New("SubDomainLabel cannot be changed")
+		}
+		users, err := b.Client.ListUsers(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		if users != nil {
+			currentUsers = users.Users
+		}
+		cs = service.NewUpdateChangeSet(reg.ID)
+		current = reg
+	}
+
+	cs.Set("Name", current.Name, b.Name)
+	cs.Set("Description", current.Description, b.Description)
+	cs.Set("Tags", current.Tags, b.Tags)
+	cs.Set("IconID", current.IconID, b.IconID)
+	cs.Set("AccessLevel", current.AccessLevel, b.AccessLevel)
+	cs.Set("VirtualDomain", current.VirtualDomain, b.VirtualDomain)
+
+	for _, username := range b.deletedUsers(currentUsers) {
+		cs.Delete(fmt.Sprintf("Users[%s]", username), username)
+	}
+	for _, user := range b.updatedUsers(currentUsers) {
+		field := fmt.Sprintf("Users[%s]", user.UserName)
+		for _, c := range currentUsers {
+			if c.UserName == user.UserName {
+				cs.Update(field+".Permission", c.Permission, user.Permission)
+			}
+		}
+		cs.SetSensitive(field+".Password", nil, user.Password)
+	}
+	for _, user := range b.createdUsers(currentUsers) {
+		field := fmt.Sprintf("Users[%s]", user.UserName)
+		cs.Create(field+".Permission", user.Permission)
+		cs.Create(field+".Password", service.SensitiveValue)
+	}
+	return cs, nil
+}
@@ -0,0 +1,47 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package containerregistry
+
+import (
+	"context"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Export 既存リソースの現在の状態をApplyRequestとして返す
+//
+// 戻り値はexport.MarshalでYAML/JSONとして保存でき、読み込んだ後Applyに渡すことで再適用できる。
+// APIから参照できない項目(SIMのPassCodeなど)は空となる。
+func (s *Service) Export(req *ExportRequest) (*ApplyRequest, error) {
+	return s.ExportWithContext(context.Background(), req)
+}
+
+func (s *Service) ExportWithContext(ctx context.Context, req *ExportRequest) (_ *ApplyRequest, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Export", Resource: "ContainerRegistry", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Export", Resource: "ContainerRegistry", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	updateRequest := &UpdateRequest{ID: req.ID}
+	applyRequest, err := updateRequest.ApplyRequest(ctx, s.caller)
+	if err != nil {
+		return nil, &service.Error{Op: "Export", Resource: "ContainerRegistry", ID: req.ID, Err: err}
+	}
+	// 後から再適用できるよう、エクスポート時点のハッシュは含めない
+	applyRequest.SettingsHash = ""
+	return applyRequest, nil
+}
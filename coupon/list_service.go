@@ -18,13 +18,17 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) List() ([]*iaas.Coupon, error) {
 	return s.ListWithContext(context.Background())
 }
 
-func (s *Service) ListWithContext(ctx context.Context) ([]*iaas.Coupon, error) {
+func (s *Service) ListWithContext(ctx context.Context) (_ []*iaas.Coupon, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "List", Resource: "Coupon"})
+	defer func() { err = finish(err) }()
+
 	authOp := iaas.NewAuthStatusOp(s.caller)
 	couponOp := iaas.NewCouponOp(s.caller)
 
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Apply(req *ApplyRequest) (*iaas.Database, error) {
 	return s.ApplyWithContext(context.Background(), req)
 }
 
-func (s *Service) ApplyWithContext(ctx context.Context, req *ApplyRequest) (*iaas.Database, error) {
+func (s *Service) ApplyWithContext(ctx context.Context, req *ApplyRequest) (_ *iaas.Database, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Apply", Resource: "Database", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Apply", Resource: "Database", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	builder, err := req.Builder(s.caller)
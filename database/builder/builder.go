@@ -62,10 +62,7 @@ type Builder struct {
 }
 
 func (b *Builder) init() {
-	if b.SetupOptions == nil {
-		b.SetupOptions = &setup2.Options{}
-	}
-	b.SetupOptions.Init()
+	b.SetupOptions = b.SetupOptions.WithDefaults()
 }
 
 // Validate 設定値の検証
@@ -150,6 +147,7 @@ func (b *Builder) create(ctx context.Context, zone string) (*iaas.Database, erro
 		},
 		IsWaitForCopy: !b.NoWait,
 		IsWaitForUp:   !b.NoWait,
+		Kind:          "Database",
 		Options:       b.SetupOptions,
 	}
 
@@ -245,6 +243,19 @@ func (b *Builder) reconcileDatabaseParameters(ctx context.Context, zone string,
 		return err
 	}
 
+	newParameters := b.desiredParameters(parameters)
+	if len(newParameters) > 0 {
+		// DatabaseAPI.Configはあとで呼ぶ
+		return b.Client.Database.SetParameter(ctx, zone, id, newParameters)
+	}
+
+	return nil
+}
+
+// desiredParameters 現在のパラメータを元に設定すべきパラメータを返す
+//
+// 既存のパラメータはnullとし、b.Parametersのキーがラベルの場合は名前に変換する
+func (b *Builder) desiredParameters(parameters *iaas.DatabaseParameter) map[string]interface{} {
 	newParameters := make(map[string]interface{})
 	// 既存のパラメータは一旦nullに
 	for k := range parameters.Settings {
@@ -265,10 +276,5 @@ func (b *Builder) reconcileDatabaseParameters(ctx context.Context, zone string,
 			newParameters[k] = v
 		}
 	}
-	if len(newParameters) > 0 {
-		// DatabaseAPI.Configはあとで呼ぶ
-		return b.Client.Database.SetParameter(ctx, zone, id, newParameters)
-	}
-
-	return nil
+	return newParameters
 }
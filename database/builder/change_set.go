@@ -0,0 +1,114 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSet Buildを行った場合の変更内容を返す
+//
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context) (*service.ChangeSet, error) {
+	b.init()
+
+	if err := b.Validate(ctx, b.Zone); err != nil {
+		return nil, err
+	}
+
+	if b.ID.IsEmpty() {
+		cs := service.NewCreateChangeSet()
+		cs.Create("PlanID", b.PlanID)
+		cs.Create("SwitchID", b.SwitchID)
+		cs.Create("IPAddresses", b.IPAddresses)
+		cs.Create("NetworkMaskLen", b.NetworkMaskLen)
+		cs.Create("DefaultRoute", b.DefaultRoute)
+		cs.Create("Conf", b.Conf)
+		cs.Create("SourceID", b.SourceID)
+		b.planSettings(cs, &iaas.Database{CommonSetting: &iaas.DatabaseSettingCommon{}})
+		for _, name := range sortedKeys(b.Parameters) {
+			cs.Create("Parameters."+name, b.Parameters[name])
+		}
+		return cs, nil
+	}
+
+	db, err := b.Client.Database.Read(ctx, b.Zone, b.ID)
+	if err != nil {
+		return nil, err
+	}
+	isNeedShutdown, err := b.collectUpdateInfo(db)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := service.NewUpdateChangeSet(db.ID)
+	b.planSettings(cs, db)
+
+	parameters, err := b.Client.Database.GetParameter(ctx, b.Zone, b.ID)
+	if err != nil {
+		return nil, err
+	}
+	desired := b.desiredParameters(parameters)
+	for _, name := range sortedKeys(desired) {
+		current := parameters.Settings[name]
+		switch {
+		case desired[name] == nil && current != nil:
+			cs.Delete("Parameters."+name, current)
+		case desired[name] != nil && fmt.Sprint(current) != fmt.Sprint(desired[name]):
+			cs.Update("Parameters."+name, current, desired[name])
+		}
+	}
+
+	if isNeedShutdown {
+		cs.NeedShutdown()
+	}
+	return cs, nil
+}
+
+func (b *Builder) planSettings(cs *service.ChangeSet, current *iaas.Database) {
+	cs.Set("Name", current.Name, b.Name)
+	cs.Set("Description", current.Description, b.Description)
+	cs.Set("Tags", current.Tags, b.Tags)
+	cs.Set("IconID", current.IconID, b.IconID)
+
+	currentCommon := current.CommonSetting
+	if currentCommon == nil {
+		currentCommon = &iaas.DatabaseSettingCommon{}
+	}
+	cs.Set("CommonSetting.WebUI", currentCommon.WebUI, b.CommonSetting.WebUI)
+	cs.Set("CommonSetting.ServicePort", currentCommon.ServicePort, b.CommonSetting.ServicePort)
+	cs.Set("CommonSetting.SourceNetwork", currentCommon.SourceNetwork, b.CommonSetting.SourceNetwork)
+	cs.Set("CommonSetting.DefaultUser", currentCommon.DefaultUser, b.CommonSetting.DefaultUser)
+	cs.SetSensitive("CommonSetting.UserPassword", currentCommon.UserPassword, b.CommonSetting.UserPassword)
+	cs.Set("CommonSetting.ReplicaUser", currentCommon.ReplicaUser, b.CommonSetting.ReplicaUser)
+	cs.SetSensitive("CommonSetting.ReplicaPassword", currentCommon.ReplicaPassword, b.CommonSetting.ReplicaPassword)
+
+	cs.Set("BackupSetting", current.BackupSetting, b.BackupSetting)
+	cs.Set("ReplicationSetting", current.ReplicationSetting, b.ReplicationSetting)
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	var keys []string
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
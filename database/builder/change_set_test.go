@@ -0,0 +1,115 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/power"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatabaseBuilder_ChangeSet(t *testing.T) {
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	caller := testutil.SingletonAPICaller()
+
+	swOp := iaas.NewSwitchOp(caller)
+	sw, err := swOp.Create(ctx, zone, &iaas.SwitchCreateRequest{Name: testutil.ResourceName("database-builder")})
+	require.NoError(t, err)
+	defer swOp.Delete(ctx, zone, sw.ID) //nolint:errcheck
+
+	builder := &Builder{
+		Zone:           zone,
+		PlanID:         types.DatabasePlans.DB10GB,
+		SwitchID:       sw.ID,
+		IPAddresses:    []string{"192.168.0.11"},
+		NetworkMaskLen: 24,
+		DefaultRoute:   "192.168.0.1",
+		Conf: &iaas.DatabaseRemarkDBConfCommon{
+			DatabaseName: types.RDBMSTypesPostgreSQL.String(),
+			DefaultUser:  "builder",
+			UserPassword: "builder-password-dummy",
+		},
+		CommonSetting: &iaas.DatabaseSettingCommon{
+			DefaultUser:  "builder",
+			UserPassword: "builder-password-dummy",
+		},
+		Name:         testutil.ResourceName("database-builder"),
+		Description:  "description",
+		Tags:         types.Tags{"tag1", "tag2"},
+		SetupOptions: getSetupOption(),
+		Client:       NewAPIClient(caller),
+	}
+
+	t.Run("create", func(t *testing.T) {
+		cs, err := builder.ChangeSet(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionCreate, cs.Action)
+		require.True(t, cs.HasChanges())
+	})
+
+	created, err := builder.Build(ctx)
+	require.NoError(t, err)
+
+	dbOp := iaas.NewDatabaseOp(caller)
+	defer func() {
+		power.ShutdownDatabase(ctx, dbOp, zone, created.ID, true) //nolint:errcheck
+		dbOp.Delete(ctx, zone, created.ID)                        //nolint:errcheck
+	}()
+
+	builder.ID = created.ID
+
+	t.Run("simple update", func(t *testing.T) {
+		name := builder.Name
+		builder.Name = name + "-upd"
+		defer func() { builder.Name = name }()
+
+		cs, err := builder.ChangeSet(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionUpdate, cs.Action)
+		require.Equal(t, service.UpdateLevelSimple, cs.UpdateLevel)
+		require.Equal(t, "Name", cs.Changes[0].Field)
+	})
+
+	t.Run("replica password", func(t *testing.T) {
+		builder.CommonSetting.ReplicaPassword = "replica-password-dummy"
+		defer func() { builder.CommonSetting.ReplicaPassword = "" }()
+
+		cs, err := builder.ChangeSet(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionUpdate, cs.Action)
+		require.Equal(t, service.UpdateLevelNeedShutdown, cs.UpdateLevel)
+
+		var change *service.FieldChange
+		for _, c := range cs.Changes {
+			if c.Field == "CommonSetting.ReplicaPassword" {
+				change = c
+			}
+		}
+		require.NotNil(t, change)
+		require.Equal(t, service.SensitiveValue, change.Desired)
+
+		// 変更内容の算出のみで更新されていないはず
+		db, err := dbOp.Read(ctx, zone, created.ID)
+		require.NoError(t, err)
+		require.Empty(t, db.CommonSetting.ReplicaPassword)
+	})
+}
@@ -18,16 +18,21 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ListParameter(req *ListParameterRequest) ([]*Parameter, error) {
 	return s.ListParameterWithContext(context.Background(), req)
 }
 
-func (s *Service) ListParameterWithContext(ctx context.Context, req *ListParameterRequest) ([]*Parameter, error) {
+func (s *Service) ListParameterWithContext(ctx context.Context, req *ListParameterRequest) (_ []*Parameter, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ListParameter", Resource: "Database", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "ListParameter", Resource: "Database", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
+
 	client := iaas.NewDatabaseOp(s.caller)
 	parameters, err := client.GetParameter(ctx, req.Zone, req.ID)
 	if err != nil {
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorCPU(req *MonitorCPURequest) ([]*iaas.MonitorCPUTimeValu
 	return s.MonitorCPUWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorCPUWithContext(ctx context.Context, req *MonitorCPURequest) ([]*iaas.MonitorCPUTimeValue, error) {
+func (s *Service) MonitorCPUWithContext(ctx context.Context, req *MonitorCPURequest) (_ []*iaas.MonitorCPUTimeValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorCPU", Resource: "Database", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorCPU", Resource: "Database", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDatabaseOp(s.caller)
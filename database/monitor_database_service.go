@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorDatabase(req *MonitorDatabaseRequest) ([]*iaas.MonitorD
 	return s.MonitorDatabaseWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorDatabaseWithContext(ctx context.Context, req *MonitorDatabaseRequest) ([]*iaas.MonitorDatabaseValue, error) {
+func (s *Service) MonitorDatabaseWithContext(ctx context.Context, req *MonitorDatabaseRequest) (_ []*iaas.MonitorDatabaseValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorDatabase", Resource: "Database", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorDatabase", Resource: "Database", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDatabaseOp(s.caller)
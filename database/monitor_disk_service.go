@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorDisk(req *MonitorDiskRequest) ([]*iaas.MonitorDiskValue
 	return s.MonitorDiskWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorDiskWithContext(ctx context.Context, req *MonitorDiskRequest) ([]*iaas.MonitorDiskValue, error) {
+func (s *Service) MonitorDiskWithContext(ctx context.Context, req *MonitorDiskRequest) (_ []*iaas.MonitorDiskValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorDisk", Resource: "Database", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorDisk", Resource: "Database", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDatabaseOp(s.caller)
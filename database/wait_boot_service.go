@@ -19,18 +19,47 @@ import (
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/wait"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
 )
 
 func (s *Service) WaitBoot(req *WaitBootRequest) error {
 	return s.WaitBootWithContext(context.Background(), req)
 }
 
-func (s *Service) WaitBootWithContext(ctx context.Context, req *WaitBootRequest) error {
+func (s *Service) WaitBootWithContext(ctx context.Context, req *WaitBootRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "WaitBoot", Resource: "Database", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "WaitBoot", Resource: "Database", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDatabaseOp(s.caller)
-	_, err := wait.UntilDatabaseIsUp(ctx, client, req.Zone, req.ID)
+	resource := progress.Resource{Kind: "Database", Zone: req.Zone, ID: req.ID}
+	observed := &observedDatabaseAPI{
+		DatabaseAPI: client,
+		read: progress.ObserveState(ctx, resource, func() (interface{}, error) {
+			return client.Read(ctx, req.Zone, req.ID)
+		}),
+	}
+	done := progress.StartPhase(ctx, resource, progress.PhaseWaitForBoot)
+	_, err = wait.UntilDatabaseIsUp(ctx, observed, req.Zone, req.ID)
+	done(err)
 	return err
 }
+
+// observedDatabaseAPI ReadをObserveStateでラップした関数に置き換えたDatabaseAPI
+type observedDatabaseAPI struct {
+	iaas.DatabaseAPI
+	read func() (interface{}, error)
+}
+
+func (c *observedDatabaseAPI) Read(ctx context.Context, zone string, id types.ID) (*iaas.Database, error) {
+	v, err := c.read()
+	if err != nil || v == nil {
+		return nil, err
+	}
+	return v.(*iaas.Database), nil
+}
@@ -18,19 +18,28 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
-	"github.com/sacloud/iaas-api-go/helper/wait"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
 )
 
 func (s *Service) WaitShutdown(req *WaitShutdownRequest) error {
 	return s.WaitShutdownWithContext(context.Background(), req)
 }
 
-func (s *Service) WaitShutdownWithContext(ctx context.Context, req *WaitShutdownRequest) error {
+func (s *Service) WaitShutdownWithContext(ctx context.Context, req *WaitShutdownRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "WaitShutdown", Resource: "Database", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "WaitShutdown", Resource: "Database", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDatabaseOp(s.caller)
-	_, err := wait.UntilDatabaseIsDown(ctx, client, req.Zone, req.ID)
+	resource := progress.Resource{Kind: "Database", Zone: req.Zone, ID: req.ID}
+	done := progress.StartPhase(ctx, resource, progress.PhaseWaitForShutdown)
+	_, err = iaas.WaiterForDown(progress.ObserveState(ctx, resource, func() (interface{}, error) {
+		return client.Read(ctx, req.Zone, req.ID)
+	})).WaitForState(ctx)
+	done(err)
 	return err
 }
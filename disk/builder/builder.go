@@ -25,6 +25,7 @@ import (
 	"github.com/sacloud/iaas-api-go/ostype"
 	"github.com/sacloud/iaas-api-go/types"
 	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
 	"github.com/sacloud/packages-go/size"
 )
 
@@ -38,6 +39,13 @@ type Builder interface {
 	NoWaitFlag() bool
 }
 
+// EphemeralResourceCleaner 構築時に生成した一時的なリソースを削除可能なビルダー
+//
+// EditParameterでIsSSHKeysEphemeral/IsNotesEphemeralが指定されている場合に生成されたSSHキー/スタートアップスクリプトが対象となる
+type EphemeralResourceCleaner interface {
+	CleanupEphemeralResources(ctx context.Context) error
+}
+
 // BuildResult ディスク構築結果
 type BuildResult struct {
 	DiskID          types.ID
@@ -92,7 +100,7 @@ func (d *FromUnixBuilder) Validate(ctx context.Context, zone string) error {
 func (d *FromUnixBuilder) Build(ctx context.Context, zone string, serverID types.ID) (*BuildResult, error) {
 	res, err := build(ctx, d.Client, zone, serverID, d.DistantFrom, d)
 	if err != nil {
-		return nil, err
+		return res, err
 	}
 	d.ID = res.DiskID
 
@@ -100,19 +108,8 @@ func (d *FromUnixBuilder) Build(ctx context.Context, zone string, serverID types
 		res.GeneratedSSHKey = d.generatedSSHKey
 	}
 
-	if d.EditParameter != nil {
-		if d.EditParameter.IsSSHKeysEphemeral && d.generatedSSHKey != nil {
-			if err := d.Client.SSHKey.Delete(ctx, d.generatedSSHKey.ID); err != nil {
-				return nil, err
-			}
-		}
-		if d.EditParameter.IsNotesEphemeral {
-			for _, note := range d.generatedNotes {
-				if err := d.Client.Note.Delete(ctx, note.ID); err != nil {
-					return nil, err
-				}
-			}
-		}
+	if err := d.CleanupEphemeralResources(ctx); err != nil {
+		return nil, err
 	}
 	return res, nil
 }
@@ -122,6 +119,14 @@ func (d *FromUnixBuilder) Update(ctx context.Context, zone string) (*UpdateResul
 	return update(ctx, d.Client, zone, d)
 }
 
+// CleanupEphemeralResources 構築時に生成した一時的なSSHキー/スタートアップスクリプトを削除する
+func (d *FromUnixBuilder) CleanupEphemeralResources(ctx context.Context) error {
+	sshKey, notes, err := cleanupEphemeralResources(ctx, d.Client, d.EditParameter, d.generatedSSHKey, d.generatedNotes)
+	d.generatedSSHKey = sshKey
+	d.generatedNotes = notes
+	return err
+}
+
 // DiskID ディスクID取得
 func (d *FromUnixBuilder) DiskID() types.ID {
 	return d.ID
@@ -219,7 +224,7 @@ func (d *FromFixedArchiveBuilder) Validate(ctx context.Context, zone string) err
 func (d *FromFixedArchiveBuilder) Build(ctx context.Context, zone string, serverID types.ID) (*BuildResult, error) {
 	res, err := build(ctx, d.Client, zone, serverID, d.DistantFrom, d)
 	if err != nil {
-		return nil, err
+		return res, err
 	}
 	d.ID = res.DiskID
 	if d.generatedSSHKey != nil {
@@ -331,26 +336,15 @@ func (d *FromDiskOrArchiveBuilder) Validate(ctx context.Context, zone string) er
 func (d *FromDiskOrArchiveBuilder) Build(ctx context.Context, zone string, serverID types.ID) (*BuildResult, error) {
 	res, err := build(ctx, d.Client, zone, serverID, d.DistantFrom, d)
 	if err != nil {
-		return nil, err
+		return res, err
 	}
 	d.ID = res.DiskID
 	if d.generatedSSHKey != nil {
 		res.GeneratedSSHKey = d.generatedSSHKey
 	}
 
-	if d.EditParameter != nil {
-		if d.EditParameter.IsSSHKeysEphemeral && d.generatedSSHKey != nil {
-			if err := d.Client.SSHKey.Delete(ctx, d.generatedSSHKey.ID); err != nil {
-				return nil, err
-			}
-		}
-		if d.EditParameter.IsNotesEphemeral {
-			for _, note := range d.generatedNotes {
-				if err := d.Client.Note.Delete(ctx, note.ID); err != nil {
-					return nil, err
-				}
-			}
-		}
+	if err := d.CleanupEphemeralResources(ctx); err != nil {
+		return nil, err
 	}
 	return res, nil
 }
@@ -360,6 +354,14 @@ func (d *FromDiskOrArchiveBuilder) Update(ctx context.Context, zone string) (*Up
 	return update(ctx, d.Client, zone, d)
 }
 
+// CleanupEphemeralResources 構築時に生成した一時的なSSHキー/スタートアップスクリプトを削除する
+func (d *FromDiskOrArchiveBuilder) CleanupEphemeralResources(ctx context.Context) error {
+	sshKey, notes, err := cleanupEphemeralResources(ctx, d.Client, d.EditParameter, d.generatedSSHKey, d.generatedNotes)
+	d.generatedSSHKey = sshKey
+	d.generatedNotes = notes
+	return err
+}
+
 // DiskID ディスクID取得
 func (d *FromDiskOrArchiveBuilder) DiskID() types.ID {
 	return d.ID
@@ -444,7 +446,7 @@ func (d *BlankBuilder) Validate(ctx context.Context, zone string) error {
 func (d *BlankBuilder) Build(ctx context.Context, zone string, serverID types.ID) (*BuildResult, error) {
 	res, err := build(ctx, d.Client, zone, serverID, d.DistantFrom, d)
 	if err != nil {
-		return nil, err
+		return res, err
 	}
 	d.ID = res.DiskID
 	return res, err
@@ -649,19 +651,22 @@ func build(ctx context.Context, client *APIClient, zone string, serverID types.I
 		return nil, err
 	}
 
+	resource := progress.Resource{Kind: "Disk", Zone: zone, ID: disk.ID}
+	progress.Emit(ctx, &progress.ResourceCreated{Resource: resource})
+
 	if builder.NoWaitFlag() {
 		return &BuildResult{DiskID: disk.ID}, nil
 	}
 
-	waiter := iaas.WaiterForReady(func() (interface{}, error) {
+	waiter := iaas.WaiterForReady(progress.ObserveState(ctx, resource, func() (interface{}, error) {
 		return client.Disk.Read(ctx, zone, disk.ID)
-	})
+	}))
+	done := progress.StartPhase(ctx, resource, progress.PhaseWaitForCopy)
 	lastState, err := waiter.WaitForState(ctx)
+	done(err)
 	if err != nil {
-		if lastState != nil {
-			return &BuildResult{DiskID: lastState.(*iaas.Disk).ID}, err
-		}
-		return nil, err
+		// WaitForStateはエラー時にnilを返すため、ロールバックできるよう作成済みのディスクのIDを返す
+		return &BuildResult{DiskID: disk.ID}, err
 	}
 	disk = lastState.(*iaas.Disk)
 
@@ -759,3 +764,31 @@ func updateLevel(disk *iaas.Disk, hasEditReq bool, b diskBuilder) service.Update
 	}
 	return service.UpdateLevelNone
 }
+
+// cleanupEphemeralResources 一時的なSSHキー/スタートアップスクリプトを削除し、削除できなかったものを返す
+func cleanupEphemeralResources(
+	ctx context.Context,
+	client *APIClient,
+	editParameter *UnixEditRequest,
+	sshKey *iaas.SSHKeyGenerated,
+	notes []*iaas.Note,
+) (*iaas.SSHKeyGenerated, []*iaas.Note, error) {
+	if editParameter == nil {
+		return sshKey, notes, nil
+	}
+	if editParameter.IsSSHKeysEphemeral && sshKey != nil {
+		if err := client.SSHKey.Delete(ctx, sshKey.ID); err != nil {
+			return sshKey, notes, err
+		}
+		sshKey = nil
+	}
+	if editParameter.IsNotesEphemeral {
+		for len(notes) > 0 {
+			if err := client.Note.Delete(ctx, notes[0].ID); err != nil {
+				return sshKey, notes, err
+			}
+			notes = notes[1:]
+		}
+	}
+	return sshKey, notes, nil
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/ostype"
+	"github.com/sacloud/iaas-api-go/testutil"
 	"github.com/sacloud/iaas-api-go/types"
 	"github.com/sacloud/packages-go/size"
 	"github.com/stretchr/testify/require"
@@ -101,3 +102,36 @@ func TestDiskFromUnixRequest_Validate(t *testing.T) {
 		require.Equal(t, tc.err, err)
 	}
 }
+
+type waitFailingDiskHandler struct {
+	CreateDiskHandler
+	err error
+}
+
+func (d *waitFailingDiskHandler) Read(ctx context.Context, zone string, id types.ID) (*iaas.Disk, error) {
+	return nil, d.err
+}
+
+func TestBlankBuilder_Build_waitFailed(t *testing.T) {
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	caller := testutil.SingletonAPICaller()
+
+	waitErr := errors.New("dummy")
+	client := NewBuildersAPIClient(caller)
+	client.Disk = &waitFailingDiskHandler{CreateDiskHandler: client.Disk, err: waitErr}
+
+	builder := &BlankBuilder{
+		Name:   testutil.ResourceName("disk-builder"),
+		SizeGB: 20,
+		PlanID: types.DiskPlans.SSD,
+		Client: client,
+	}
+	result, err := builder.Build(ctx, zone, types.ID(0))
+	require.True(t, errors.Is(err, waitErr))
+
+	// 作成済みのディスクを後始末できるようIDが返るはず
+	require.NotNil(t, result)
+	require.False(t, result.DiskID.IsEmpty())
+	require.NoError(t, iaas.NewDiskOp(caller).Delete(ctx, zone, result.DiskID))
+}
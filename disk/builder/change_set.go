@@ -0,0 +1,139 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSetter Build/Updateを行った場合の変更内容を返すことができるビルダー
+//
+// このパッケージのビルダーは全てChangeSetterを実装している
+type ChangeSetter interface {
+	ChangeSet(ctx context.Context, zone string) (*service.ChangeSet, error)
+}
+
+var (
+	_ ChangeSetter = (*FromUnixBuilder)(nil)
+	_ ChangeSetter = (*FromFixedArchiveBuilder)(nil)
+	_ ChangeSetter = (*FromDiskOrArchiveBuilder)(nil)
+	_ ChangeSetter = (*BlankBuilder)(nil)
+	_ ChangeSetter = (*ConnectedDiskBuilder)(nil)
+)
+
+// ChangeSet ビルダーがChangeSetterを実装していればその変更内容を返す、実装していない場合はエラーを返す
+func ChangeSet(ctx context.Context, builder Builder, zone string) (*service.ChangeSet, error) {
+	setter, ok := builder.(ChangeSetter)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement ChangeSetter", builder)
+	}
+	return setter.ChangeSet(ctx, zone)
+}
+
+// ChangeSet Build/Updateを行った場合の変更内容を返す
+func (d *FromUnixBuilder) ChangeSet(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	return plan(ctx, d.Client, zone, d, d.EditParameter != nil, func(cs *service.ChangeSet) {
+		cs.Create("OSType", d.OSType.String())
+		cs.Create("PlanID", d.PlanID)
+		cs.Create("SizeGB", d.SizeGB)
+		cs.Create("DistantFrom", d.DistantFrom)
+	})
+}
+
+// ChangeSet Build/Updateを行った場合の変更内容を返す
+func (d *FromFixedArchiveBuilder) ChangeSet(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	return plan(ctx, d.Client, zone, d, false, func(cs *service.ChangeSet) {
+		cs.Create("OSType", d.OSType.String())
+		cs.Create("PlanID", d.PlanID)
+		cs.Create("SizeGB", d.SizeGB)
+		cs.Create("DistantFrom", d.DistantFrom)
+	})
+}
+
+// ChangeSet Build/Updateを行った場合の変更内容を返す
+func (d *FromDiskOrArchiveBuilder) ChangeSet(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	return plan(ctx, d.Client, zone, d, d.EditParameter != nil, func(cs *service.ChangeSet) {
+		cs.Create("SourceDiskID", d.SourceDiskID)
+		cs.Create("SourceArchiveID", d.SourceArchiveID)
+		cs.Create("PlanID", d.PlanID)
+		cs.Create("SizeGB", d.SizeGB)
+		cs.Create("DistantFrom", d.DistantFrom)
+	})
+}
+
+// ChangeSet Build/Updateを行った場合の変更内容を返す
+func (d *BlankBuilder) ChangeSet(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	return plan(ctx, d.Client, zone, d, false, func(cs *service.ChangeSet) {
+		cs.Create("PlanID", d.PlanID)
+		cs.Create("SizeGB", d.SizeGB)
+		cs.Create("DistantFrom", d.DistantFrom)
+	})
+}
+
+// ChangeSet Build/Updateを行った場合の変更内容を返す
+//
+// 既存ディスクを利用するため、常に既存ディスクとの差分を返す
+func (d *ConnectedDiskBuilder) ChangeSet(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	return plan(ctx, d.Client, zone, d, d.EditParameter != nil, nil)
+}
+
+// plan 現在のディスクの状態とbuilderの値を比較し変更内容を返す
+//
+// 新規作成時はbuilder固有の値をcreateValuesで追加する
+func plan(ctx context.Context, client *APIClient, zone string, builder diskBuilder, hasEditReq bool, createValues func(cs *service.ChangeSet)) (*service.ChangeSet, error) {
+	desired := builder.updateDiskParameter()
+
+	if builder.DiskID().IsEmpty() {
+		cs := service.NewCreateChangeSet()
+		cs.Create("Name", desired.Name)
+		cs.Create("Description", desired.Description)
+		cs.Create("Tags", desired.Tags)
+		cs.Create("IconID", desired.IconID)
+		cs.Create("Connection", desired.Connection)
+		if createValues != nil {
+			createValues(cs)
+		}
+		if hasEditReq {
+			cs.Create("EditParameter", service.SensitiveValue)
+		}
+		return cs, nil
+	}
+
+	disk, err := client.Disk.Read(ctx, zone, builder.DiskID())
+	if err != nil {
+		return nil, err
+	}
+
+	cs := service.NewUpdateChangeSet(disk.ID)
+	cs.Update("Name", disk.Name, desired.Name)
+	cs.Update("Description", disk.Description, desired.Description)
+	cs.Update("Tags", disk.Tags, desired.Tags)
+	cs.Update("IconID", disk.IconID, desired.IconID)
+	if desired.Connection != types.EDiskConnection("") {
+		cs.Update("Connection", disk.Connection, desired.Connection)
+	}
+	if hasEditReq {
+		cs.Update("EditParameter", nil, service.SensitiveValue)
+	}
+
+	if updateLevel(disk, hasEditReq, builder) == service.UpdateLevelNeedShutdown {
+		cs.NeedShutdown()
+	}
+	return cs, nil
+}
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ConnectToServer(req *ConnectToServerRequest) error {
 	return s.ConnectToServerWithContext(context.Background(), req)
 }
 
-func (s *Service) ConnectToServerWithContext(ctx context.Context, req *ConnectToServerRequest) error {
+func (s *Service) ConnectToServerWithContext(ctx context.Context, req *ConnectToServerRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ConnectToServer", Resource: "Disk", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "ConnectToServer", Resource: "Disk", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDiskOp(s.caller)
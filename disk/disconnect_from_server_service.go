@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DisconnectFromServer(req *DisconnectFromServerRequest) error {
 	return s.DisconnectFromServerWithContext(context.Background(), req)
 }
 
-func (s *Service) DisconnectFromServerWithContext(ctx context.Context, req *DisconnectFromServerRequest) error {
+func (s *Service) DisconnectFromServerWithContext(ctx context.Context, req *DisconnectFromServerRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DisconnectFromServer", Resource: "Disk", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DisconnectFromServer", Resource: "Disk", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDiskOp(s.caller)
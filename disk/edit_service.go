@@ -19,15 +19,19 @@ import (
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/wait"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Edit(req *EditRequest) error {
 	return s.EditWithContext(context.Background(), req)
 }
 
-func (s *Service) EditWithContext(ctx context.Context, req *EditRequest) error {
+func (s *Service) EditWithContext(ctx context.Context, req *EditRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Edit", Resource: "Disk", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "Edit", Resource: "Disk", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	params, err := req.ToRequestParameter()
@@ -0,0 +1,72 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package disk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/wait"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/export"
+	"github.com/sacloud/packages-go/size"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskService_Export(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("disk-service-export")
+	zone := testutil.TestZone()
+
+	diskOp := iaas.NewDiskOp(caller)
+	disk, err := diskOp.Create(ctx, zone, &iaas.DiskCreateRequest{
+		DiskPlanID: types.DiskPlans.SSD,
+		Connection: types.DiskConnections.VirtIO,
+		SizeMB:     20 * size.GiB,
+		Name:       name,
+		Tags:       types.Tags{"tag1"},
+	}, []types.ID{})
+	require.NoError(t, err)
+	defer diskOp.Delete(ctx, zone, disk.ID) //nolint:errcheck
+
+	_, err = wait.UntilDiskIsReady(ctx, diskOp, zone, disk.ID)
+	require.NoError(t, err)
+
+	svc := New(caller)
+	exported, err := svc.Export(&ExportRequest{Zone: zone, ID: disk.ID})
+	require.NoError(t, err)
+	require.Equal(t, &ApplyRequest{
+		Zone:       zone,
+		ID:         disk.ID,
+		Name:       name,
+		Tags:       types.Tags{"tag1"},
+		DiskPlanID: types.DiskPlans.SSD,
+		Connection: types.DiskConnections.VirtIO,
+		SizeGB:     20,
+	}, exported)
+
+	data, err := export.Marshal(exported, export.FormatYAML)
+	require.NoError(t, err)
+
+	var loaded ApplyRequest
+	require.NoError(t, export.Unmarshal(data, export.FormatYAML, &loaded))
+	require.Equal(t, exported, &loaded)
+
+	_, err = svc.Apply(&loaded)
+	require.NoError(t, err)
+}
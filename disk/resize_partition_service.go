@@ -19,15 +19,19 @@ import (
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/wait"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ResizePartition(req *ResizePartitionRequest) error {
 	return s.ResizePartitionWithContext(context.Background(), req)
 }
 
-func (s *Service) ResizePartitionWithContext(ctx context.Context, req *ResizePartitionRequest) error {
+func (s *Service) ResizePartitionWithContext(ctx context.Context, req *ResizePartitionRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ResizePartition", Resource: "Disk", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "ResizePartition", Resource: "Disk", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDiskOp(s.caller)
@@ -35,6 +39,6 @@ func (s *Service) ResizePartitionWithContext(ctx context.Context, req *ResizePar
 		return err
 	}
 
-	_, err := wait.UntilDiskIsReady(ctx, client, req.Zone, req.ID)
+	_, err = wait.UntilDiskIsReady(ctx, client, req.Zone, req.ID)
 	return err
 }
@@ -18,19 +18,28 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
-	"github.com/sacloud/iaas-api-go/helper/wait"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
 )
 
 func (s *Service) WaitReady(req *WaitReadyRequest) error {
 	return s.WaitReadyWithContext(context.Background(), req)
 }
 
-func (s *Service) WaitReadyWithContext(ctx context.Context, req *WaitReadyRequest) error {
+func (s *Service) WaitReadyWithContext(ctx context.Context, req *WaitReadyRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "WaitReady", Resource: "Disk", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "WaitReady", Resource: "Disk", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewDiskOp(s.caller)
-	_, err := wait.UntilDiskIsReady(ctx, client, req.Zone, req.ID)
+	resource := progress.Resource{Kind: "Disk", Zone: req.Zone, ID: req.ID}
+	done := progress.StartPhase(ctx, resource, progress.PhaseWaitForReady)
+	_, err = iaas.WaiterForReady(progress.ObserveState(ctx, resource, func() (interface{}, error) {
+		return client.Read(ctx, req.Zone, req.ID)
+	})).WaitForState(ctx)
+	done(err)
 	return err
 }
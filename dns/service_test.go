@@ -16,12 +16,16 @@ package dns
 
 import (
 	"context"
+	"errors"
 	"testing"
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/testutil"
 	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/packages-go/pointer"
+	"github.com/stretchr/testify/require"
 )
 
 func TestService_CRUD(t *testing.T) {
@@ -76,3 +80,73 @@ func TestService_CRUD(t *testing.T) {
 		Parallel: true,
 	})
 }
+
+func TestService_Errors(t *testing.T) {
+	svc := New(testutil.SingletonAPICaller())
+
+	t.Run("validation", func(t *testing.T) {
+		_, err := svc.Update(&UpdateRequest{})
+		require.Error(t, err)
+		require.True(t, service.IsValidationError(err))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		_, err := svc.Update(&UpdateRequest{ID: types.ID(999999999999)})
+		require.Error(t, err)
+		require.True(t, service.IsNotFoundError(err))
+		require.True(t, iaas.IsNotFoundError(err))
+
+		var serviceErr *service.Error
+		require.True(t, errors.As(err, &serviceErr))
+		require.Equal(t, "Update", serviceErr.Op)
+		require.Equal(t, "DNS", serviceErr.Resource)
+		require.Equal(t, types.ID(999999999999), serviceErr.ID)
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		_, err := svc.Read(&ReadRequest{ID: types.ID(999999999999)})
+		require.Error(t, err)
+		require.True(t, service.IsNotFoundError(err))
+
+		var serviceErr *service.Error
+		require.True(t, errors.As(err, &serviceErr))
+		require.Equal(t, "Read", serviceErr.Op)
+		require.Equal(t, "DNS", serviceErr.Resource)
+		require.Equal(t, types.ID(999999999999), serviceErr.ID)
+	})
+}
+
+func TestService_FindWithFilter(t *testing.T) {
+	svc := New(testutil.SingletonAPICaller())
+	prefix := testutil.RandomPrefix()
+
+	var created []*iaas.DNS
+	for _, tag := range []string{"env=staging", "env=production"} {
+		dns, err := svc.Create(&CreateRequest{Name: prefix + tag[4:] + ".example.com", Tags: types.Tags{tag}})
+		require.NoError(t, err)
+		created = append(created, dns)
+	}
+	defer func() {
+		for _, dns := range created {
+			svc.Delete(&DeleteRequest{ID: dns.ID}) //nolint:errcheck
+		}
+	}()
+
+	found, err := svc.Find(&FindRequest{
+		Filter: filter.Filter{
+			NamePrefixes: []string{prefix},
+			ExcludeTags:  []string{"env=production"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, created[0].ID, found[0].ID)
+
+	var ids []types.ID
+	err = svc.FindAll(&FindRequest{Filter: filter.Filter{NamePrefixes: []string{prefix}}}, func(v *iaas.DNS) error {
+		ids = append(ids, v.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+}
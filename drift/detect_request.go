@@ -0,0 +1,30 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import "github.com/sacloud/packages-go/validate"
+
+type DetectRequest struct {
+	// Target 比較対象の定義
+	//
+	// 以下のパッケージの*ApplyRequestを指定する。IDの指定が必須。
+	// server/disk/vpcrouter/database/loadbalancer/nfs/mobilegateway/localrouter/
+	// containerregistry/certificateauthority/enhanceddb
+	Target interface{} `validate:"required"`
+}
+
+func (req *DetectRequest) Validate() error {
+	return validate.New().Struct(req)
+}
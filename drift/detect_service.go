@@ -0,0 +1,129 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/certificateauthority"
+	"github.com/sacloud/iaas-service-go/containerregistry"
+	"github.com/sacloud/iaas-service-go/database"
+	"github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/enhanceddb"
+	"github.com/sacloud/iaas-service-go/loadbalancer"
+	"github.com/sacloud/iaas-service-go/localrouter"
+	"github.com/sacloud/iaas-service-go/mobilegateway"
+	"github.com/sacloud/iaas-service-go/nfs"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/sacloud/iaas-service-go/vpcrouter"
+)
+
+// Detect 定義と実リソースを比較し、フィールドごとの差分を返す
+//
+// 比較には各サービスのPlanを利用するため、更新系のAPIは呼び出されない。
+func (s *Service) Detect(req *DetectRequest) (*Result, error) {
+	return s.DetectWithContext(context.Background(), req)
+}
+
+func (s *Service) DetectWithContext(ctx context.Context, req *DetectRequest) (_ *Result, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Detect", Resource: "Drift", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Detect", Resource: "Drift", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	t, err := s.target(req.Target)
+	if err != nil {
+		return nil, &service.Error{Op: "Detect", Resource: "Drift", Kind: service.ErrorKindValidation, Err: err}
+	}
+	if t.id.IsEmpty() {
+		return nil, &service.Error{Op: "Detect", Resource: t.resource, Zone: t.zone, Kind: service.ErrorKindValidation, Err: errors.New("ID is required")}
+	}
+
+	result := &Result{Resource: t.resource, Zone: t.zone, ID: t.id}
+	cs, err := t.plan(ctx)
+	if err != nil {
+		if service.IsNotFoundError(err) {
+			result.NotFound = true
+			return result, nil
+		}
+		return nil, &service.Error{Op: "Detect", Resource: t.resource, Zone: t.zone, ID: t.id, Err: err}
+	}
+	result.UpdateLevel = cs.UpdateLevel
+	result.Changes = cs.Changes
+	return result, nil
+}
+
+type target struct {
+	resource string
+	zone     string
+	id       types.ID
+	plan     func(ctx context.Context) (*service.ChangeSet, error)
+}
+
+func (s *Service) target(v interface{}) (*target, error) {
+	switch req := v.(type) {
+	case *server.ApplyRequest:
+		return &target{resource: "Server", zone: req.Zone, id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return server.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *disk.ApplyRequest:
+		return &target{resource: "Disk", zone: req.Zone, id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return disk.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *vpcrouter.ApplyRequest:
+		return &target{resource: "VPCRouter", zone: req.Zone, id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return vpcrouter.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *database.ApplyRequest:
+		return &target{resource: "Database", zone: req.Zone, id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return database.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *loadbalancer.ApplyRequest:
+		return &target{resource: "LoadBalancer", zone: req.Zone, id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return loadbalancer.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *nfs.ApplyRequest:
+		return &target{resource: "NFS", zone: req.Zone, id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return nfs.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *mobilegateway.ApplyRequest:
+		return &target{resource: "MobileGateway", zone: req.Zone, id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return mobilegateway.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *localrouter.ApplyRequest:
+		return &target{resource: "LocalRouter", id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return localrouter.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *containerregistry.ApplyRequest:
+		return &target{resource: "ContainerRegistry", id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return containerregistry.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *certificateauthority.ApplyRequest:
+		return &target{resource: "CertificateAuthority", id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return certificateauthority.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	case *enhanceddb.ApplyRequest:
+		return &target{resource: "EnhancedDB", id: req.ID, plan: func(ctx context.Context) (*service.ChangeSet, error) {
+			return enhanceddb.New(s.caller).PlanWithContext(ctx, req)
+		}}, nil
+	}
+	return nil, fmt.Errorf("unsupported target type: %T", v)
+}
@@ -0,0 +1,105 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/wait"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/packages-go/size"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Detect(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("drift-detect")
+	zone := testutil.TestZone()
+
+	diskOp := iaas.NewDiskOp(caller)
+	created, err := diskOp.Create(ctx, zone, &iaas.DiskCreateRequest{
+		DiskPlanID: types.DiskPlans.SSD,
+		Connection: types.DiskConnections.VirtIO,
+		SizeMB:     20 * size.GiB,
+		Name:       name,
+	}, []types.ID{})
+	require.NoError(t, err)
+	defer diskOp.Delete(ctx, zone, created.ID) //nolint:errcheck
+
+	_, err = wait.UntilDiskIsReady(ctx, diskOp, zone, created.ID)
+	require.NoError(t, err)
+
+	desired, err := disk.New(caller).Export(&disk.ExportRequest{Zone: zone, ID: created.ID})
+	require.NoError(t, err)
+
+	svc := New(caller)
+
+	t.Run("no drift", func(t *testing.T) {
+		result, err := svc.Detect(&DetectRequest{Target: desired})
+		require.NoError(t, err)
+		require.False(t, result.Drifted())
+		require.Equal(t, "Disk", result.Resource)
+		require.Equal(t, zone, result.Zone)
+		require.Equal(t, created.ID, result.ID)
+	})
+
+	t.Run("changed in live resource", func(t *testing.T) {
+		_, err := diskOp.Update(ctx, zone, created.ID, &iaas.DiskUpdateRequest{
+			Name:       name + "-changed",
+			Connection: types.DiskConnections.VirtIO,
+		})
+		require.NoError(t, err)
+
+		result, err := svc.Detect(&DetectRequest{Target: desired})
+		require.NoError(t, err)
+		require.True(t, result.Drifted())
+		require.False(t, result.NotFound)
+
+		var found bool
+		for _, c := range result.Changes {
+			if c.Field == "Name" {
+				found = true
+				require.Equal(t, name+"-changed", c.Current)
+				require.Equal(t, name, c.Desired)
+			}
+		}
+		require.True(t, found, "changes: %#v", result.Changes)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		target := *desired
+		target.ID = types.ID(1)
+		result, err := svc.Detect(&DetectRequest{Target: &target})
+		require.NoError(t, err)
+		require.True(t, result.NotFound)
+		require.True(t, result.Drifted())
+	})
+
+	t.Run("unsupported target", func(t *testing.T) {
+		_, err := svc.Detect(&DetectRequest{Target: &disk.ReadRequest{Zone: zone, ID: created.ID}})
+		require.True(t, service.IsValidationError(err))
+	})
+
+	t.Run("empty ID", func(t *testing.T) {
+		_, err := svc.Detect(&DetectRequest{Target: &disk.ApplyRequest{Zone: zone, Name: name}})
+		require.True(t, service.IsValidationError(err))
+	})
+}
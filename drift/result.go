@@ -0,0 +1,39 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package drift
+
+import (
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Result ドリフトの検出結果
+type Result struct {
+	Resource string   // リソース種別(例: Server)
+	Zone     string   // ゾーン名、グローバルリソースの場合は空
+	ID       types.ID // 対象リソースのID
+
+	// NotFound 実リソースが存在しない場合true
+	NotFound bool
+	// UpdateLevel 定義の状態へ戻すために必要な変更のレベル
+	UpdateLevel service.UpdateLevel
+	// Changes フィールドごとの差分、Currentが実リソースの値、Desiredが定義の値を表す
+	Changes []*service.FieldChange
+}
+
+// Drifted 実リソースが定義と異なる場合true
+func (r *Result) Drifted() bool {
+	return r.NotFound || len(r.Changes) > 0
+}
@@ -17,6 +17,7 @@ package enhanceddb
 import (
 	"context"
 
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/enhanceddb/builder"
 )
 
@@ -24,9 +25,12 @@ func (s *Service) Apply(req *ApplyRequest) (*builder.EnhancedDB, error) {
 	return s.ApplyWithContext(context.Background(), req)
 }
 
-func (s *Service) ApplyWithContext(ctx context.Context, req *ApplyRequest) (*builder.EnhancedDB, error) {
+func (s *Service) ApplyWithContext(ctx context.Context, req *ApplyRequest) (_ *builder.EnhancedDB, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Apply", Resource: "EnhancedDB", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Apply", Resource: "EnhancedDB", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	builder, err := req.Builder(s.caller)
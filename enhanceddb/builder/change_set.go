@@ -0,0 +1,54 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"errors"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSet Buildを行った場合の変更内容を返す
+//
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context) (*service.ChangeSet, error) {
+	current := &iaas.EnhancedDB{}
+	var cs *service.ChangeSet
+
+	if b.ID.IsEmpty() {
+		cs = service.NewCreateChangeSet()
+		cs.Create("DatabaseName", b.DatabaseName)
+	} else {
+		db, err := b.Client.Read(ctx, b.ID)
+		if err != nil {
+			return nil, err
+		}
+		if db.DatabaseName != b.DatabaseName {
+			return nil, errors.New("DatabaseName cannot be changed")
+		}
+		cs = service.NewUpdateChangeSet(db.ID)
+		current = db
+	}
+
+	cs.Set("Name", current.Name, b.Name)
+	cs.Set("Description", current.Description, b.Description)
+	cs.Set("Tags", current.Tags, b.Tags)
+	cs.Set("IconID", current.IconID, b.IconID)
+	// パスワードは参照できないため、指定されていれば常に変更ありとする
+	cs.SetSensitive("Password", nil, b.Password)
+	return cs, nil
+}
@@ -17,6 +17,7 @@ package enhanceddb
 import (
 	"context"
 
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/enhanceddb/builder"
 )
 
@@ -24,9 +25,12 @@ func (s *Service) Create(req *CreateRequest) (*builder.EnhancedDB, error) {
 	return s.CreateWithContext(context.Background(), req)
 }
 
-func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (*builder.EnhancedDB, error) {
+func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (_ *builder.EnhancedDB, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Create", Resource: "EnhancedDB", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Create", Resource: "EnhancedDB", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	return s.ApplyWithContext(ctx, req.ApplyRequest())
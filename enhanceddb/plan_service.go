@@ -0,0 +1,44 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package enhanceddb
+
+import (
+	"context"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Plan Applyを行った場合の変更内容を返す
+//
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (s *Service) Plan(req *ApplyRequest) (*service.ChangeSet, error) {
+	return s.PlanWithContext(context.Background(), req)
+}
+
+// PlanWithContext Applyを行った場合の変更内容を返す
+func (s *Service) PlanWithContext(ctx context.Context, req *ApplyRequest) (_ *service.ChangeSet, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Plan", Resource: "EnhancedDB", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Plan", Resource: "EnhancedDB", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	builder, err := req.Builder(s.caller)
+	if err != nil {
+		return nil, err
+	}
+	return builder.ChangeSet(ctx)
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/enhanceddb/builder"
 )
 
@@ -25,10 +26,14 @@ func (s *Service) Read(req *ReadRequest) (*builder.EnhancedDB, error) {
 	return s.ReadWithContext(context.Background(), req)
 }
 
-func (s *Service) ReadWithContext(ctx context.Context, req *ReadRequest) (*builder.EnhancedDB, error) {
+func (s *Service) ReadWithContext(ctx context.Context, req *ReadRequest) (_ *builder.EnhancedDB, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Read", Resource: "EnhancedDB", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Read", Resource: "EnhancedDB", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
+
 	client := iaas.NewEnhancedDBOp(s.caller)
 	return builder.Read(ctx, client, req.ID)
 }
@@ -16,8 +16,8 @@ package enhanceddb
 
 import (
 	"context"
-	"fmt"
 
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/enhanceddb/builder"
 )
 
@@ -25,14 +25,17 @@ func (s *Service) Update(req *UpdateRequest) (*builder.EnhancedDB, error) {
 	return s.UpdateWithContext(context.Background(), req)
 }
 
-func (s *Service) UpdateWithContext(ctx context.Context, req *UpdateRequest) (*builder.EnhancedDB, error) {
+func (s *Service) UpdateWithContext(ctx context.Context, req *UpdateRequest) (_ *builder.EnhancedDB, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Update", Resource: "EnhancedDB", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Update", Resource: "EnhancedDB", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	applyRequest, err := req.ApplyRequest(ctx, s.caller)
 	if err != nil {
-		return nil, fmt.Errorf("processing request parameter failed: %s", err)
+		return nil, &service.Error{Op: "Update", Resource: "EnhancedDB", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	return s.ApplyWithContext(ctx, applyRequest)
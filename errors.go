@@ -0,0 +1,193 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iaas
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	api "github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// ErrorKind エラーの種別
+type ErrorKind string
+
+const (
+	// ErrorKindUnknown 種別不明
+	ErrorKindUnknown ErrorKind = ""
+	// ErrorKindNotFound 対象リソースが存在しない
+	ErrorKindNotFound ErrorKind = "not-found"
+	// ErrorKindConflict リソースの状態が操作と競合している(起動中のため削除できないなど)
+	ErrorKindConflict ErrorKind = "conflict"
+	// ErrorKindValidation リクエストパラメータが不正
+	ErrorKindValidation ErrorKind = "validation"
+	// ErrorKindMaxRetryCountExceeded リトライ最大数超過
+	ErrorKindMaxRetryCountExceeded ErrorKind = "max-retry-count-exceeded"
+)
+
+// ErrorKinder 自身のエラー種別を返せるエラーが実装するインターフェース
+type ErrorKinder interface {
+	ErrorKind() ErrorKind
+}
+
+// Error 各サービスが返すエラー
+//
+// 操作名やリソース種別、ゾーン、IDを保持し、元のエラーをラップする。
+// errors.Is/errors.Asで元のエラーを参照できるほか、iaas.APIErrorも実装しているため
+// iaas.IsNotFoundErrorなどiaas-api-goのヘルパーもそのまま利用可能。
+type Error struct {
+	Op       string    // 操作名(例: Update)
+	Resource string    // リソース種別(例: Server)
+	Zone     string    // ゾーン名、グローバルリソースの場合は空
+	ID       types.ID  // 対象リソースのID、未確定の場合は空
+	Kind     ErrorKind // エラー種別、空の場合はErrから判定する
+	Err      error     // 元のエラー
+}
+
+// Error errorインターフェースの実装
+func (e *Error) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Resource)
+	switch {
+	case e.Zone != "" && !e.ID.IsEmpty():
+		fmt.Fprintf(&sb, "[%s:%s]", e.Zone, e.ID)
+	case e.Zone != "":
+		fmt.Fprintf(&sb, "[%s]", e.Zone)
+	case !e.ID.IsEmpty():
+		fmt.Fprintf(&sb, "[%s]", e.ID)
+	}
+	if sb.Len() > 0 {
+		sb.WriteString(" ")
+	}
+	fmt.Fprintf(&sb, "%s failed", e.Op)
+	if e.Err != nil {
+		fmt.Fprintf(&sb, ": %s", e.Err)
+	}
+	return sb.String()
+}
+
+// Unwrap 元のエラーを返す
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorKind エラー種別を返す
+func (e *Error) ErrorKind() ErrorKind {
+	if e.Kind != ErrorKindUnknown {
+		return e.Kind
+	}
+	return KindOf(e.Err)
+}
+
+// ResponseCode ラップしているiaas.APIErrorのレスポンスコード、APIエラー以外の場合は0
+func (e *Error) ResponseCode() int {
+	if apiErr := e.apiError(); apiErr != nil {
+		return apiErr.ResponseCode()
+	}
+	return 0
+}
+
+// Code ラップしているiaas.APIErrorのエラーコード
+func (e *Error) Code() string {
+	if apiErr := e.apiError(); apiErr != nil {
+		return apiErr.Code()
+	}
+	return ""
+}
+
+// Message ラップしているiaas.APIErrorのメッセージ
+func (e *Error) Message() string {
+	if apiErr := e.apiError(); apiErr != nil {
+		return apiErr.Message()
+	}
+	return ""
+}
+
+// Serial ラップしているiaas.APIErrorのシリアルコード
+func (e *Error) Serial() string {
+	if apiErr := e.apiError(); apiErr != nil {
+		return apiErr.Serial()
+	}
+	return ""
+}
+
+// OrigErr ラップしているiaas.APIErrorのオリジナルのエラー
+func (e *Error) OrigErr() *api.APIErrorResponse {
+	if apiErr := e.apiError(); apiErr != nil {
+		return apiErr.OrigErr()
+	}
+	return nil
+}
+
+func (e *Error) apiError() api.APIError {
+	var apiErr api.APIError
+	if errors.As(e.Err, &apiErr) {
+		return apiErr
+	}
+	return nil
+}
+
+// KindOf エラーの種別を判定する
+//
+// ErrorKinderを実装したエラー、iaas.APIError(レスポンスコード)の順に判定し、
+// ラップされたエラーもたどる。
+func KindOf(err error) ErrorKind {
+	if err == nil {
+		return ErrorKindUnknown
+	}
+
+	var kinder ErrorKinder
+	if errors.As(err, &kinder) {
+		if kind := kinder.ErrorKind(); kind != ErrorKindUnknown {
+			return kind
+		}
+	}
+
+	var apiErr api.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.ResponseCode() {
+		case http.StatusNotFound:
+			return ErrorKindNotFound
+		case http.StatusConflict:
+			return ErrorKindConflict
+		case http.StatusBadRequest:
+			return ErrorKindValidation
+		}
+	}
+	return ErrorKindUnknown
+}
+
+// IsNotFoundError 対象リソースが存在しないことを示すエラーか
+func IsNotFoundError(err error) bool {
+	return KindOf(err) == ErrorKindNotFound
+}
+
+// IsConflictError リソースの状態と操作が競合したことを示すエラーか
+func IsConflictError(err error) bool {
+	return KindOf(err) == ErrorKindConflict
+}
+
+// IsValidationError リクエストパラメータが不正であることを示すエラーか
+func IsValidationError(err error) bool {
+	return KindOf(err) == ErrorKindValidation
+}
+
+// IsMaxRetryCountExceededError リトライ最大数を超過したことを示すエラーか
+func IsMaxRetryCountExceededError(err error) bool {
+	return KindOf(err) == ErrorKindMaxRetryCountExceeded
+}
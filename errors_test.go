@@ -0,0 +1,100 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iaas
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	api "github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyKindError struct {
+	kind ErrorKind
+}
+
+func (e *dummyKindError) Error() string {
+	return string(e.kind)
+}
+
+func (e *dummyKindError) ErrorKind() ErrorKind {
+	return e.kind
+}
+
+func TestError(t *testing.T) {
+	notFound := api.NewAPIError(http.MethodGet, nil, http.StatusNotFound, &api.APIErrorResponse{ErrorCode: "not_found"})
+
+	t.Run("message", func(t *testing.T) {
+		cases := []struct {
+			err    *Error
+			expect string
+		}{
+			{
+				err:    &Error{Op: "Update", Resource: "DNS", ID: types.ID(1), Err: errors.New("foo")},
+				expect: "DNS[1] Update failed: foo",
+			},
+			{
+				err:    &Error{Op: "Delete", Resource: "Server", Zone: "is1a", ID: types.ID(1), Err: errors.New("foo")},
+				expect: "Server[is1a:1] Delete failed: foo",
+			},
+			{
+				err:    &Error{Op: "Create", Resource: "Server", Zone: "is1a"},
+				expect: "Server[is1a] Create failed",
+			},
+		}
+		for _, tc := range cases {
+			require.Equal(t, tc.expect, tc.err.Error())
+		}
+	})
+
+	t.Run("wrapping api error", func(t *testing.T) {
+		err := &Error{Op: "Update", Resource: "DNS", ID: types.ID(1), Err: notFound}
+
+		require.True(t, errors.Is(err, notFound))
+		require.True(t, IsNotFoundError(err))
+		require.True(t, IsNotFoundError(fmt.Errorf("wrapped: %w", err)))
+		require.True(t, api.IsNotFoundError(err))
+		require.Equal(t, "not_found", err.Code())
+
+		var apiErr api.APIError
+		require.True(t, errors.As(fmt.Errorf("wrapped: %w", err), &apiErr))
+		require.Equal(t, http.StatusNotFound, apiErr.ResponseCode())
+	})
+
+	t.Run("kind", func(t *testing.T) {
+		cases := []struct {
+			err    error
+			expect ErrorKind
+		}{
+			{err: nil, expect: ErrorKindUnknown},
+			{err: errors.New("foo"), expect: ErrorKindUnknown},
+			{err: notFound, expect: ErrorKindNotFound},
+			{err: api.NewAPIError(http.MethodPut, nil, http.StatusConflict, nil), expect: ErrorKindConflict},
+			{err: api.NewAPIError(http.MethodPut, nil, http.StatusBadRequest, nil), expect: ErrorKindValidation},
+			{err: &Error{Kind: ErrorKindValidation, Err: errors.New("foo")}, expect: ErrorKindValidation},
+			{err: &Error{Err: &dummyKindError{kind: ErrorKindMaxRetryCountExceeded}}, expect: ErrorKindMaxRetryCountExceeded},
+			{err: &Error{Kind: ErrorKindConflict, Err: notFound}, expect: ErrorKindConflict},
+		}
+		for _, tc := range cases {
+			require.Equal(t, tc.expect, KindOf(tc.err), "error: %v", tc.err)
+		}
+
+		require.False(t, api.IsNotFoundError(&Error{Kind: ErrorKindValidation, Err: errors.New("foo")}))
+	})
+}
@@ -16,23 +16,27 @@ package esme
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Logs(req *LogsRequest) ([]*iaas.ESMELogs, error) {
 	return s.LogsWithContext(context.Background(), req)
 }
 
-func (s *Service) LogsWithContext(ctx context.Context, req *LogsRequest) ([]*iaas.ESMELogs, error) {
+func (s *Service) LogsWithContext(ctx context.Context, req *LogsRequest) (_ []*iaas.ESMELogs, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Logs", Resource: "ESME", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Logs", Resource: "ESME", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
+
 	client := iaas.NewESMEOp(s.caller)
-	_, err := client.Read(ctx, req.ID)
+	_, err = client.Read(ctx, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("reading ESME[%s] failed: %s", req.ID, err)
+		return nil, &service.Error{Op: "Logs", Resource: "ESME", ID: req.ID, Err: err}
 	}
 
 	return client.Logs(ctx, req.ID)
@@ -19,21 +19,25 @@ import (
 	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) SendMessage(req *SendMessageRequest) (*iaas.ESMESendMessageResult, error) {
 	return s.SendMessageWithContext(context.Background(), req)
 }
 
-func (s *Service) SendMessageWithContext(ctx context.Context, req *SendMessageRequest) (*iaas.ESMESendMessageResult, error) {
+func (s *Service) SendMessageWithContext(ctx context.Context, req *SendMessageRequest) (_ *iaas.ESMESendMessageResult, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "SendMessage", Resource: "ESME", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "SendMessage", Resource: "ESME", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewESMEOp(s.caller)
-	_, err := client.Read(ctx, req.ID)
+	_, err = client.Read(ctx, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("reading ESME[%s] failed: %s", req.ID, err)
+		return nil, &service.Error{Op: "SendMessage", Resource: "ESME", ID: req.ID, Err: err}
 	}
 
 	params := req.ToRequestParameter()
@@ -43,6 +47,6 @@ func (s *Service) SendMessageWithContext(ctx context.Context, req *SendMessageRe
 	case *iaas.ESMESendMessageWithInputtedOTPRequest:
 		return client.SendMessageWithInputtedOTP(ctx, req.ID, p)
 	default:
-		return nil, fmt.Errorf("processing request parameter failed")
+		return nil, &service.Error{Op: "SendMessage", Resource: "ESME", ID: req.ID, Kind: service.ErrorKindValidation, Err: fmt.Errorf("processing request parameter failed: unsupported parameter type %T", params)}
 	}
 }
@@ -0,0 +1,99 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Format 出力形式
+type Format string
+
+const (
+	// FormatYAML YAML形式
+	FormatYAML = Format("yaml")
+	// FormatJSON JSON形式
+	FormatJSON = Format("json")
+)
+
+// Marshal Exportサービスが返したApplyRequestなどを指定の形式で出力する
+//
+// YAMLのキーはJSONと同じくGoのフィールド名(またはjsonタグ)となり、Unmarshalで元の値に復元できる。
+func Marshal(v interface{}, format Format) ([]byte, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatJSON:
+		return data, nil
+	case FormatYAML:
+		return jsonToYAML(data)
+	default:
+		return nil, fmt.Errorf("unsupported format: %q", format)
+	}
+}
+
+// Unmarshal Marshalで出力したデータをvへ読み込む
+func Unmarshal(data []byte, format Format, v interface{}) error {
+	switch format {
+	case FormatJSON:
+		return json.Unmarshal(data, v)
+	case FormatYAML:
+		var values interface{}
+		if err := yaml.Unmarshal(data, &values); err != nil {
+			return err
+		}
+		j, err := json.Marshal(values)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(j, v)
+	default:
+		return fmt.Errorf("unsupported format: %q", format)
+	}
+}
+
+// jsonToYAML JSONをキーの順序を保ったままブロックスタイルのYAMLへ変換する
+func jsonToYAML(data []byte) ([]byte, error) {
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+
+	buf := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetStyle(n)
+	}
+}
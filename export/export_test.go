@@ -0,0 +1,72 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package export
+
+import (
+	"testing"
+
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshal(t *testing.T) {
+	source := &server.ApplyRequest{
+		Zone:     "is1a",
+		ID:       types.ID(123456789012),
+		Name:     "example",
+		Tags:     types.Tags{"tag1", "tag2"},
+		CPU:      2,
+		MemoryGB: 4,
+		NetworkInterfaces: []*server.NetworkInterface{
+			{Upstream: "shared"},
+		},
+		Disks: []*disk.ApplyRequest{
+			{
+				Zone:       "is1a",
+				ID:         types.ID(223456789012),
+				Name:       "example",
+				Tags:       types.Tags{},
+				DiskPlanID: types.DiskPlans.SSD,
+				Connection: types.DiskConnections.VirtIO,
+				SizeGB:     20,
+			},
+		},
+	}
+
+	for _, format := range []Format{FormatYAML, FormatJSON} {
+		t.Run(string(format), func(t *testing.T) {
+			data, err := Marshal(source, format)
+			require.NoError(t, err)
+
+			var dest server.ApplyRequest
+			require.NoError(t, Unmarshal(data, format, &dest))
+			require.Equal(t, source, &dest)
+		})
+	}
+
+	t.Run("yaml keys", func(t *testing.T) {
+		data, err := Marshal(source, FormatYAML)
+		require.NoError(t, err)
+		require.Contains(t, string(data), "Name: example\n")
+		require.Contains(t, string(data), "ID: 123456789012\n")
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := Marshal(source, Format("xml"))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,241 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"strings"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-api-go/search/keys"
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// Filter Find系サービスで共通利用できる検索条件
+//
+// API側の検索条件として表現できるもの(ID/スコープ/Availability)はFindConditionにも設定され、
+// 全ての条件がAPIからの取得後にクライアント側で適用される。
+// 対象リソースが持たない項目(例: スイッチに対するScopes)を指定した場合は何も一致しない。
+//
+// 名前の部分一致には各FindRequestのNamesを利用する。
+type Filter struct {
+	// IDs いずれかのIDに一致
+	IDs []types.ID
+	// NamePrefixes 名前がいずれかの文字列で始まる
+	NamePrefixes []string
+	// AnyTags いずれかのタグを持つ
+	AnyTags []string
+	// ExcludeTags いずれのタグも持たない
+	ExcludeTags []string
+	// CreatedAfter 指定日時より後に作成された
+	CreatedAfter time.Time
+	// CreatedBefore 指定日時より前に作成された
+	CreatedBefore time.Time
+	// Availabilities いずれかの有効状態に一致
+	Availabilities []types.EAvailability
+	// InstanceStatuses いずれかのインスタンスステータスに一致
+	InstanceStatuses []types.EServerInstanceStatus
+	// Scopes いずれかのスコープに一致
+	Scopes []types.EScope
+}
+
+type idGetter interface {
+	GetID() types.ID
+}
+
+type nameGetter interface {
+	GetName() string
+}
+
+type tagsGetter interface {
+	GetTags() types.Tags
+}
+
+type createdAtGetter interface {
+	GetCreatedAt() time.Time
+}
+
+type availabilityGetter interface {
+	GetAvailability() types.EAvailability
+}
+
+type instanceStatusGetter interface {
+	GetInstanceStatus() types.EServerInstanceStatus
+}
+
+type scopeGetter interface {
+	GetScope() types.EScope
+}
+
+// IsEmpty 条件が指定されていない場合true
+func (f *Filter) IsEmpty() bool {
+	return f == nil || (len(f.IDs) == 0 &&
+		len(f.NamePrefixes) == 0 &&
+		len(f.AnyTags) == 0 &&
+		len(f.ExcludeTags) == 0 &&
+		f.CreatedAfter.IsZero() &&
+		f.CreatedBefore.IsZero() &&
+		len(f.Availabilities) == 0 &&
+		len(f.InstanceStatuses) == 0 &&
+		len(f.Scopes) == 0)
+}
+
+// SetCondition API側で解釈可能な条件をconditionに設定する
+//
+// Tは検索対象のリソースの型(例: *iaas.Server)で、Tが持たない項目は設定されない。
+// condition.Filterに同じキーが設定済みの場合は上書きしない。
+func SetCondition[T any](f *Filter, condition *iaas.FindCondition) {
+	if f.IsEmpty() {
+		return
+	}
+	if condition.Filter == nil {
+		condition.Filter = search.Filter{}
+	}
+	set := func(key string, values []interface{}) {
+		k := search.Key(key)
+		if _, exists := condition.Filter[k]; !exists && len(values) > 0 {
+			condition.Filter[k] = search.OrEqual(values...)
+		}
+	}
+
+	var zero T
+	var ids []interface{}
+	for _, id := range f.IDs {
+		ids = append(ids, id)
+	}
+	set(keys.ID, ids)
+
+	if _, ok := any(zero).(scopeGetter); ok {
+		var scopes []interface{}
+		for _, scope := range f.Scopes {
+			scopes = append(scopes, scope.String())
+		}
+		set(keys.Scope, scopes)
+	}
+	if _, ok := any(zero).(availabilityGetter); ok {
+		var availabilities []interface{}
+		for _, availability := range f.Availabilities {
+			availabilities = append(availabilities, string(availability))
+		}
+		set(keys.Availability, availabilities)
+	}
+}
+
+// Match vが全ての条件に一致する場合true
+func (f *Filter) Match(v interface{}) bool {
+	if f.IsEmpty() {
+		return true
+	}
+
+	if len(f.IDs) > 0 {
+		target, ok := v.(idGetter)
+		if !ok || !contains(f.IDs, target.GetID()) {
+			return false
+		}
+	}
+	if len(f.NamePrefixes) > 0 {
+		target, ok := v.(nameGetter)
+		if !ok || !hasAnyPrefix(target.GetName(), f.NamePrefixes) {
+			return false
+		}
+	}
+	if len(f.AnyTags) > 0 || len(f.ExcludeTags) > 0 {
+		target, ok := v.(tagsGetter)
+		if !ok {
+			return false
+		}
+		tags := target.GetTags()
+		if len(f.AnyTags) > 0 && !containsAnyTag(tags, f.AnyTags) {
+			return false
+		}
+		if containsAnyTag(tags, f.ExcludeTags) {
+			return false
+		}
+	}
+	if !f.CreatedAfter.IsZero() || !f.CreatedBefore.IsZero() {
+		target, ok := v.(createdAtGetter)
+		if !ok {
+			return false
+		}
+		createdAt := target.GetCreatedAt()
+		if !f.CreatedAfter.IsZero() && !createdAt.After(f.CreatedAfter) {
+			return false
+		}
+		if !f.CreatedBefore.IsZero() && !createdAt.Before(f.CreatedBefore) {
+			return false
+		}
+	}
+	if len(f.Availabilities) > 0 {
+		target, ok := v.(availabilityGetter)
+		if !ok || !contains(f.Availabilities, target.GetAvailability()) {
+			return false
+		}
+	}
+	if len(f.InstanceStatuses) > 0 {
+		target, ok := v.(instanceStatusGetter)
+		if !ok || !contains(f.InstanceStatuses, target.GetInstanceStatus()) {
+			return false
+		}
+	}
+	if len(f.Scopes) > 0 {
+		target, ok := v.(scopeGetter)
+		if !ok || !contains(f.Scopes, target.GetScope()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Apply valuesのうち条件に一致するものを返す
+func Apply[T any](f *Filter, values []T) []T {
+	if f.IsEmpty() {
+		return values
+	}
+	var results []T
+	for _, v := range values {
+		if f.Match(v) {
+			results = append(results, v)
+		}
+	}
+	return results
+}
+
+func contains[T comparable](values []T, v T) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAnyTag(tags types.Tags, targets []string) bool {
+	for _, t := range targets {
+		if contains(tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
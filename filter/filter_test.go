@@ -0,0 +1,114 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilter_Match(t *testing.T) {
+	now := time.Now()
+	server := &iaas.Server{
+		ID:             types.ID(1),
+		Name:           "web-01",
+		Tags:           types.Tags{"env=staging", "role=web"},
+		Availability:   types.Availabilities.Available,
+		InstanceStatus: types.ServerInstanceStatuses.Up,
+		CreatedAt:      now,
+	}
+
+	cases := []struct {
+		name   string
+		filter *Filter
+		expect bool
+	}{
+		{name: "nil", filter: nil, expect: true},
+		{name: "empty", filter: &Filter{}, expect: true},
+		{name: "ids", filter: &Filter{IDs: []types.ID{2, 1}}, expect: true},
+		{name: "ids not matched", filter: &Filter{IDs: []types.ID{2}}, expect: false},
+		{name: "name prefixes", filter: &Filter{NamePrefixes: []string{"db-", "web-"}}, expect: true},
+		{name: "name prefixes not matched", filter: &Filter{NamePrefixes: []string{"01"}}, expect: false},
+		{name: "any tags", filter: &Filter{AnyTags: []string{"env=production", "env=staging"}}, expect: true},
+		{name: "any tags not matched", filter: &Filter{AnyTags: []string{"env=production"}}, expect: false},
+		{name: "exclude tags", filter: &Filter{ExcludeTags: []string{"role=web"}}, expect: false},
+		{name: "created after", filter: &Filter{CreatedAfter: now.Add(-time.Hour)}, expect: true},
+		{name: "created after not matched", filter: &Filter{CreatedAfter: now}, expect: false},
+		{name: "created before", filter: &Filter{CreatedBefore: now.Add(time.Hour)}, expect: true},
+		{name: "created before not matched", filter: &Filter{CreatedBefore: now}, expect: false},
+		{name: "availabilities", filter: &Filter{Availabilities: []types.EAvailability{types.Availabilities.Available}}, expect: true},
+		{name: "availabilities not matched", filter: &Filter{Availabilities: []types.EAvailability{types.Availabilities.Failed}}, expect: false},
+		{name: "instance statuses", filter: &Filter{InstanceStatuses: []types.EServerInstanceStatus{types.ServerInstanceStatuses.Up}}, expect: true},
+		{name: "instance statuses not matched", filter: &Filter{InstanceStatuses: []types.EServerInstanceStatus{types.ServerInstanceStatuses.Down}}, expect: false},
+		{name: "unsupported field", filter: &Filter{Scopes: []types.EScope{types.Scopes.User}}, expect: false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, tc.filter.Match(server))
+		})
+	}
+}
+
+func TestSetCondition(t *testing.T) {
+	f := &Filter{
+		IDs:            []types.ID{1, 2},
+		Scopes:         []types.EScope{types.Scopes.Shared},
+		Availabilities: []types.EAvailability{types.Availabilities.Available},
+		NamePrefixes:   []string{"foo"},
+	}
+
+	t.Run("archive", func(t *testing.T) {
+		condition := &iaas.FindCondition{}
+		SetCondition[*iaas.Archive](f, condition)
+		require.Equal(t, search.Filter{
+			search.Key("ID"):           search.OrEqual(types.ID(1), types.ID(2)),
+			search.Key("Scope"):        search.OrEqual("shared"),
+			search.Key("Availability"): search.OrEqual("available"),
+		}, condition.Filter)
+	})
+
+	t.Run("packet filter", func(t *testing.T) {
+		condition := &iaas.FindCondition{}
+		SetCondition[*iaas.PacketFilter](f, condition)
+		require.Equal(t, search.Filter{
+			search.Key("ID"): search.OrEqual(types.ID(1), types.ID(2)),
+		}, condition.Filter)
+	})
+
+	t.Run("existing keys are kept", func(t *testing.T) {
+		condition := &iaas.FindCondition{Filter: search.Filter{search.Key("Scope"): search.OrEqual("user")}}
+		SetCondition[*iaas.Archive](f, condition)
+		require.Equal(t, search.OrEqual("user"), condition.Filter[search.Key("Scope")])
+	})
+}
+
+func TestApply(t *testing.T) {
+	values := []*iaas.Switch{
+		{ID: 1, Name: "sw-1", Tags: types.Tags{"env=staging"}},
+		{ID: 2, Name: "sw-2", Tags: types.Tags{"env=production"}},
+		{ID: 3, Name: "other", Tags: types.Tags{"env=staging"}},
+	}
+
+	require.Equal(t, values, Apply(nil, values))
+	require.Equal(t, []*iaas.Switch{values[0]}, Apply(&Filter{
+		NamePrefixes: []string{"sw-"},
+		ExcludeTags:  []string{"env=production"},
+	}, values))
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
 	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -28,6 +29,8 @@ type FindRequest struct {
 	Tags  []string     `service:"-"`
 	Scope types.EScope `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -54,5 +57,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.Scope) {
 		condition.Filter[search.Key("Scope")] = search.OrEqual(req.Scope)
 	}
+	filter.SetCondition[*iaas.Icon](&req.Filter, condition)
 	return condition, nil
 }
@@ -17,6 +17,7 @@ package iface
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -31,6 +32,8 @@ type FindRequest struct {
 	PacketFilterIDs   []string `service:"-"`
 	PacketFilterNames []string `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -63,5 +66,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.PacketFilterNames) {
 		condition.Filter[search.Key("PacketFilter.Name")] = search.AndEqual(req.PacketFilterNames...)
 	}
+	filter.SetCondition[*iaas.Interface](&req.Filter, condition)
 	return condition, nil
 }
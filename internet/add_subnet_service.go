@@ -16,29 +16,32 @@ package internet
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) AddSubnet(req *AddSubnetRequest) (*iaas.Subnet, error) {
 	return s.AddSubnetWithContext(context.Background(), req)
 }
 
-func (s *Service) AddSubnetWithContext(ctx context.Context, req *AddSubnetRequest) (*iaas.Subnet, error) {
+func (s *Service) AddSubnetWithContext(ctx context.Context, req *AddSubnetRequest) (_ *iaas.Subnet, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "AddSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "AddSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewInternetOp(s.caller)
 	current, err := client.Read(ctx, req.Zone, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("reading the internet resource[%s] failed: %s", req.ID, err)
+		return nil, &service.Error{Op: "AddSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Err: err}
 	}
 
 	params, err := req.ToRequestParameter(current)
 	if err != nil {
-		return nil, fmt.Errorf("processing request parameter failed: %s", err)
+		return nil, &service.Error{Op: "AddSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 	result, err := client.AddSubnet(ctx, req.Zone, req.ID, params)
 	if err != nil {
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DeleteSubnet(req *DeleteSubnetRequest) error {
 	return s.DeleteSubnetWithContext(context.Background(), req)
 }
 
-func (s *Service) DeleteSubnetWithContext(ctx context.Context, req *DeleteSubnetRequest) error {
+func (s *Service) DeleteSubnetWithContext(ctx context.Context, req *DeleteSubnetRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DeleteSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DeleteSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewInternetOp(s.caller)
@@ -16,24 +16,27 @@ package internet
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DisableIPv6(req *DisableIPv6Request) error {
 	return s.DisableIPv6WithContext(context.Background(), req)
 }
 
-func (s *Service) DisableIPv6WithContext(ctx context.Context, req *DisableIPv6Request) error {
+func (s *Service) DisableIPv6WithContext(ctx context.Context, req *DisableIPv6Request) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DisableIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DisableIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	internetOp := iaas.NewInternetOp(s.caller)
 	current, err := internetOp.Read(ctx, req.Zone, req.ID)
 	if err != nil {
-		return fmt.Errorf("reading the internet resource[%s] failed: %s", req.ID, err)
+		return &service.Error{Op: "DisableIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Err: err}
 	}
 
 	if len(current.Switch.IPv6Nets) == 0 {
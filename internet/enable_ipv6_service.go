@@ -16,24 +16,27 @@ package internet
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) EnableIPv6(req *EnableIPv6Request) (*iaas.IPv6Net, error) {
 	return s.EnableIPv6WithContext(context.Background(), req)
 }
 
-func (s *Service) EnableIPv6WithContext(ctx context.Context, req *EnableIPv6Request) (*iaas.IPv6Net, error) {
+func (s *Service) EnableIPv6WithContext(ctx context.Context, req *EnableIPv6Request) (_ *iaas.IPv6Net, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "EnableIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "EnableIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	internetOp := iaas.NewInternetOp(s.caller)
 	current, err := internetOp.Read(ctx, req.Zone, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("reading the internet resource[%s] failed: %s", req.ID, err)
+		return nil, &service.Error{Op: "EnableIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Err: err}
 	}
 
 	ipv6Op := iaas.NewIPv6NetOp(s.caller)
@@ -16,24 +16,27 @@ package internet
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ListSubnet(req *ListSubnetRequest) ([]*iaas.Subnet, error) {
 	return s.ListSubnetWithContext(context.Background(), req)
 }
 
-func (s *Service) ListSubnetWithContext(ctx context.Context, req *ListSubnetRequest) ([]*iaas.Subnet, error) {
+func (s *Service) ListSubnetWithContext(ctx context.Context, req *ListSubnetRequest) (_ []*iaas.Subnet, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ListSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "ListSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	internetOp := iaas.NewInternetOp(s.caller)
 	current, err := internetOp.Read(ctx, req.Zone, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("reading the internet resource[%s] failed: %s", req.ID, err)
+		return nil, &service.Error{Op: "ListSubnet", Resource: "Internet", Zone: req.Zone, ID: req.ID, Err: err}
 	}
 
 	// Note: *iaas.InternetのSwitch.Subnetsでは情報が不足しているため1件ずつReadする
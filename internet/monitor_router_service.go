@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorRouter(req *MonitorRouterRequest) ([]*iaas.MonitorRoute
 	return s.MonitorRouterWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorRouterWithContext(ctx context.Context, req *MonitorRouterRequest) ([]*iaas.MonitorRouterValue, error) {
+func (s *Service) MonitorRouterWithContext(ctx context.Context, req *MonitorRouterRequest) (_ []*iaas.MonitorRouterValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorRouter", Resource: "Internet", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorRouter", Resource: "Internet", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewInternetOp(s.caller)
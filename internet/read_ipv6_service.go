@@ -16,24 +16,27 @@ package internet
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ReadIPv6(req *ReadIPv6Request) (*iaas.IPv6Net, error) {
 	return s.ReadIPv6WithContext(context.Background(), req)
 }
 
-func (s *Service) ReadIPv6WithContext(ctx context.Context, req *ReadIPv6Request) (*iaas.IPv6Net, error) {
+func (s *Service) ReadIPv6WithContext(ctx context.Context, req *ReadIPv6Request) (_ *iaas.IPv6Net, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ReadIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "ReadIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	internetOp := iaas.NewInternetOp(s.caller)
 	current, err := internetOp.Read(ctx, req.Zone, req.ID)
 	if err != nil {
-		return nil, fmt.Errorf("reading the internet resource[%s] failed: %s", req.ID, err)
+		return nil, &service.Error{Op: "ReadIPv6", Resource: "Internet", Zone: req.Zone, ID: req.ID, Err: err}
 	}
 	if len(current.Switch.IPv6Nets) == 0 {
 		return nil, iaas.NewNoResultsError()
@@ -17,6 +17,7 @@ package internetplan
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -27,6 +28,8 @@ type FindRequest struct {
 
 	Names []string `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -47,5 +50,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.Names) {
 		condition.Filter[search.Key("Name")] = search.AndEqual(req.Names...)
 	}
+	filter.SetCondition[*iaas.InternetPlan](&req.Filter, condition)
 	return condition, nil
 }
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) List(req *ListRequest) ([]*iaas.IPAddress, error) {
 	return s.ListWithContext(context.Background(), req)
 }
 
-func (s *Service) ListWithContext(ctx context.Context, req *ListRequest) ([]*iaas.IPAddress, error) {
+func (s *Service) ListWithContext(ctx context.Context, req *ListRequest) (_ []*iaas.IPAddress, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "List", Resource: "IPAddress", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "List", Resource: "IPAddress", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewIPAddressOp(s.caller)
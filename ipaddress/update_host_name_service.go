@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 const (
@@ -31,13 +32,16 @@ func (s *Service) UpdateHostName(req *UpdateHostNameRequest) error {
 	return s.UpdateHostNameWithContext(context.Background(), req)
 }
 
-func (s *Service) UpdateHostNameWithContext(ctx context.Context, req *UpdateHostNameRequest) error {
+func (s *Service) UpdateHostNameWithContext(ctx context.Context, req *UpdateHostNameRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "UpdateHostName", Resource: "IPAddress", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "UpdateHostName", Resource: "IPAddress", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewIPAddressOp(s.caller)
-	_, err := client.Read(ctx, req.Zone, req.IPAddress)
+	_, err = client.Read(ctx, req.Zone, req.IPAddress)
 	if err != nil {
 		return err
 	}
@@ -53,7 +57,7 @@ func (s *Service) UpdateHostNameWithContext(ctx context.Context, req *UpdateHost
 	success := false
 	for i < req.RetryMax {
 		if err := ctx.Err(); err != nil {
-			return fmt.Errorf("updating the HostName for %s failed: %s", req.IPAddress, err)
+			return &service.Error{Op: "UpdateHostName", Resource: "IPAddress", Zone: req.Zone, Err: fmt.Errorf("updating the HostName for %s failed: %w", req.IPAddress, err)}
 		}
 		if _, err = client.UpdateHostName(ctx, req.Zone, req.IPAddress, req.HostName); err == nil {
 			success = true
@@ -64,7 +68,7 @@ func (s *Service) UpdateHostNameWithContext(ctx context.Context, req *UpdateHost
 	}
 
 	if !success {
-		return fmt.Errorf("updating the HostName for %s failed: giving up on setting the HostName: max attempts exceeded", req.IPAddress)
+		return &service.Error{Op: "UpdateHostName", Resource: "IPAddress", Zone: req.Zone, Kind: service.ErrorKindMaxRetryCountExceeded, Err: fmt.Errorf("updating the HostName for %s failed: giving up on setting the HostName: max attempts exceeded: %w", req.IPAddress, err)}
 	}
 	return nil
 }
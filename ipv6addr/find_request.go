@@ -17,6 +17,7 @@ package ipv6addr
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -29,6 +30,8 @@ type FindRequest struct {
 	HostNames     []string `service:"-"`
 	IPv6Addresses []string `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -55,5 +58,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.IPv6Addresses) {
 		condition.Filter[search.Key("IPv6Addr")] = search.AndEqual(req.IPv6Addresses...)
 	}
+	filter.SetCondition[*iaas.IPv6Addr](&req.Filter, condition)
 	return condition, nil
 }
@@ -16,24 +16,27 @@ package ipv6addr
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Update(req *UpdateRequest) (*iaas.IPv6Addr, error) {
 	return s.UpdateWithContext(context.Background(), req)
 }
 
-func (s *Service) UpdateWithContext(ctx context.Context, req *UpdateRequest) (*iaas.IPv6Addr, error) {
+func (s *Service) UpdateWithContext(ctx context.Context, req *UpdateRequest) (_ *iaas.IPv6Addr, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Update", Resource: "IPv6Addr", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Update", Resource: "IPv6Addr", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewIPv6AddrOp(s.caller)
-	_, err := client.Read(ctx, req.Zone, req.IPv6Addr)
+	_, err = client.Read(ctx, req.Zone, req.IPv6Addr)
 	if err != nil {
-		return nil, fmt.Errorf("reading IPv6Addr[%s] failed: %s", req.IPv6Addr, err)
+		return nil, &service.Error{Op: "Update", Resource: "IPv6Addr", Zone: req.Zone, Err: err}
 	}
 
 	return client.Update(ctx, req.Zone, req.IPv6Addr, &iaas.IPv6AddrUpdateRequest{HostName: req.HostName})
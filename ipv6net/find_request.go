@@ -17,6 +17,7 @@ package ipv6net
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -30,6 +31,8 @@ type FindRequest struct {
 	InternetIDs   []string `service:"-"`
 	InternetNames []string `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -59,5 +62,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.InternetNames) {
 		condition.Filter[search.Key("Switch.Internet.Name")] = search.AndEqual(req.InternetNames...)
 	}
+	filter.SetCondition[*iaas.IPv6Net](&req.Filter, condition)
 	return condition, nil
 }
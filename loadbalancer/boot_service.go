@@ -19,15 +19,19 @@ import (
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/power"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Boot(req *BootRequest) error {
 	return s.BootWithContext(context.Background(), req)
 }
 
-func (s *Service) BootWithContext(ctx context.Context, req *BootRequest) error {
+func (s *Service) BootWithContext(ctx context.Context, req *BootRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Boot", Resource: "LoadBalancer", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "Boot", Resource: "LoadBalancer", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewLoadBalancerOp(s.caller)
@@ -19,6 +19,7 @@ import (
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
 	localrouter "github.com/sacloud/iaas-service-go/localrouter/builder"
 )
 
@@ -61,7 +62,20 @@ func BuilderFromResource(ctx context.Context, caller iaas.APICaller, id types.ID
 }
 
 func (b *Builder) Build(ctx context.Context) (*iaas.LocalRouter, error) {
-	builder := &localrouter.Builder{
+	builder := b.builder()
+	if b.ID.IsEmpty() {
+		return builder.Build(ctx)
+	}
+	return builder.Update(ctx, b.ID)
+}
+
+// ChangeSet Buildを行った場合の変更内容を返す
+func (b *Builder) ChangeSet(ctx context.Context) (*service.ChangeSet, error) {
+	return b.builder().ChangeSet(ctx, b.ID)
+}
+
+func (b *Builder) builder() *localrouter.Builder {
+	return &localrouter.Builder{
 		Name:         b.Name,
 		Description:  b.Description,
 		Tags:         b.Tags,
@@ -73,9 +87,4 @@ func (b *Builder) Build(ctx context.Context) (*iaas.LocalRouter, error) {
 		SettingsHash: b.SettingsHash,
 		Client:       localrouter.NewAPIClient(b.Caller),
 	}
-
-	if b.ID.IsEmpty() {
-		return builder.Build(ctx)
-	}
-	return builder.Update(ctx, b.ID)
 }
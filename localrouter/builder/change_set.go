@@ -0,0 +1,54 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package localrouter
+
+import (
+	"context"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSet Build/Updateを行った場合の変更内容を返す
+//
+// idが空の場合はBuild、それ以外の場合はUpdateを行った場合の変更内容を返します。
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context, id types.ID) (*service.ChangeSet, error) {
+	if err := b.Validate(ctx); err != nil {
+		return nil, err
+	}
+
+	current := &iaas.LocalRouter{}
+	cs := service.NewCreateChangeSet()
+	if !id.IsEmpty() {
+		lr, err := b.Client.LocalRouter.Read(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		current = lr
+		cs = service.NewUpdateChangeSet(id)
+	}
+
+	cs.Set("Name", current.Name, b.Name)
+	cs.Set("Description", current.Description, b.Description)
+	cs.Set("Tags", current.Tags, b.Tags)
+	cs.Set("IconID", current.IconID, b.IconID)
+	cs.Set("Switch", current.Switch, b.Switch)
+	cs.Set("Interface", current.Interface, b.Interface)
+	cs.Set("Peers", current.Peers, b.Peers)
+	cs.Set("StaticRoutes", current.StaticRoutes, b.StaticRoutes)
+	return cs, nil
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorLocalRouter(req *MonitorLocalRouterRequest) ([]*iaas.Mo
 	return s.MonitorLocalRouterWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorLocalRouterWithContext(ctx context.Context, req *MonitorLocalRouterRequest) ([]*iaas.MonitorLocalRouterValue, error) {
+func (s *Service) MonitorLocalRouterWithContext(ctx context.Context, req *MonitorLocalRouterRequest) (_ []*iaas.MonitorLocalRouterValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorLocalRouter", Resource: "LocalRouter", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorLocalRouter", Resource: "LocalRouter", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewLocalRouterOp(s.caller)
@@ -0,0 +1,180 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/packages-go/validate"
+)
+
+const (
+	// Namespace メトリクス名の接頭辞
+	Namespace = "sakuracloud"
+	// DefaultWindow モニタリング値を取得する期間のデフォルト値
+	DefaultWindow = 15 * time.Minute
+	// DefaultTimeout 1回の収集処理のタイムアウトのデフォルト値
+	DefaultTimeout = time.Minute
+	// DefaultParallelism 同時にモニタリング値を取得するリソース数のデフォルト値
+	DefaultParallelism = 4
+)
+
+// Options Collectorの設定
+type Options struct {
+	// Kinds 収集対象のリソース種別、省略時は全ての種別
+	Kinds []string `validate:"omitempty,dive,required"`
+	// Zones ゾーンに属するリソースの収集対象ゾーン、省略時は全ゾーン
+	Zones []string `validate:"omitempty,dive,required"`
+	// Tags 指定したタグを全て持つリソースのみを収集対象とする
+	Tags []string
+
+	// Window モニタリング値を取得する期間、期間内の最新の値がメトリクスとなる
+	Window time.Duration `validate:"min=0"`
+	// Timeout 1回の収集処理のタイムアウト
+	Timeout time.Duration `validate:"min=0"`
+	// Parallelism 同時にモニタリング値を取得するリソース数
+	Parallelism int `validate:"min=0"`
+
+	// ErrorHandler 収集中にエラーが発生した場合に呼ばれる、省略時は無視される
+	// 複数の種別の収集は並行して行われるため、並行して呼ばれる場合がある
+	ErrorHandler func(kind string, err error)
+}
+
+func (o *Options) Validate() error {
+	return validate.New().Struct(o)
+}
+
+// Collector Monitor*サービスから取得した値をPrometheusのメトリクスとして提供するprometheus.Collector
+type Collector struct {
+	caller  iaas.APICaller
+	options Options
+	sources []*source
+
+	success  *prometheus.Desc
+	duration *prometheus.Desc
+}
+
+var _ prometheus.Collector = (*Collector)(nil)
+
+// NewCollector Collectorを返す
+func NewCollector(caller iaas.APICaller, options *Options) (*Collector, error) {
+	if options == nil {
+		options = &Options{}
+	}
+	if err := options.Validate(); err != nil {
+		return nil, &service.Error{Op: "NewCollector", Resource: "Metrics", Kind: service.ErrorKindValidation, Err: err}
+	}
+	o := *options
+	if o.Window == 0 {
+		o.Window = DefaultWindow
+	}
+	if o.Timeout == 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.Parallelism == 0 {
+		o.Parallelism = DefaultParallelism
+	}
+
+	sources, err := selectSources(o.Kinds)
+	if err != nil {
+		return nil, &service.Error{Op: "NewCollector", Resource: "Metrics", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	return &Collector{
+		caller:  caller,
+		options: o,
+		sources: sources,
+		success: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "exporter", "collect_success"),
+			"1 if the last collection of the kind succeeded, otherwise 0",
+			[]string{"kind"}, nil,
+		),
+		duration: prometheus.NewDesc(
+			prometheus.BuildFQName(Namespace, "exporter", "collect_duration_seconds"),
+			"Duration of the last collection of the kind",
+			[]string{"kind"}, nil,
+		),
+	}, nil
+}
+
+// Handler Collectorのメトリクスを返すhttp.Handlerを返す
+func Handler(caller iaas.APICaller, options *Options) (http.Handler, error) {
+	collector, err := NewCollector(caller, options)
+	if err != nil {
+		return nil, err
+	}
+	registry := prometheus.NewRegistry()
+	if err := registry.Register(collector); err != nil {
+		return nil, err
+	}
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{}), nil
+}
+
+// Describe prometheus.Collectorの実装
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.success
+	ch <- c.duration
+	for _, s := range c.sources {
+		for _, d := range s.descs {
+			ch <- d
+		}
+	}
+}
+
+// Collect prometheus.Collectorの実装
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.options.Timeout)
+	defer cancel()
+
+	p := &collectParameter{
+		caller:      c.caller,
+		zones:       c.options.Zones,
+		tags:        c.options.Tags,
+		end:         time.Now(),
+		parallelism: c.options.Parallelism,
+	}
+	p.start = p.end.Add(-c.options.Window)
+
+	var wg sync.WaitGroup
+	for _, s := range c.sources {
+		wg.Add(1)
+		go func(s *source) {
+			defer wg.Done()
+			started := time.Now()
+			err := s.collect(ctx, p, ch)
+			success := 1.0
+			if err != nil {
+				success = 0
+				c.handleError(s.kind, err)
+			}
+			ch <- prometheus.MustNewConstMetric(c.success, prometheus.GaugeValue, success, s.kind)
+			ch <- prometheus.MustNewConstMetric(c.duration, prometheus.GaugeValue, time.Since(started).Seconds(), s.kind)
+		}(s)
+	}
+	wg.Wait()
+}
+
+func (c *Collector) handleError(kind string, err error) {
+	if c.options.ErrorHandler != nil {
+		c.options.ErrorHandler(kind, err)
+	}
+}
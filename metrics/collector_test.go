@@ -0,0 +1,118 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	zone := testutil.TestZone()
+	tag := "metrics-test"
+
+	created, err := iaas.NewServerOp(caller).Create(context.Background(), zone, &iaas.ServerCreateRequest{
+		CPU:                  1,
+		MemoryMB:             1024,
+		ServerPlanCommitment: types.Commitments.Standard,
+		ServerPlanGeneration: types.PlanGenerations.Default,
+		ConnectedSwitches:    []*iaas.ConnectedSwitch{{Scope: types.Scopes.Shared}},
+		InterfaceDriver:      types.InterfaceDrivers.VirtIO,
+		Name:                 "metrics-test",
+		Tags:                 types.Tags{tag},
+	})
+	require.NoError(t, err)
+	defer iaas.NewServerOp(caller).Delete(context.Background(), zone, created.ID) // nolint
+
+	var handled []error
+	collector, err := NewCollector(caller, &Options{
+		Kinds:        []string{"server", "SIM"},
+		Zones:        []string{zone},
+		Tags:         []string{tag},
+		ErrorHandler: func(_ string, err error) { handled = append(handled, err) },
+	})
+	require.NoError(t, err)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(t, registry.Register(collector))
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Empty(t, handled)
+
+	metrics := make(map[string]*prometheusMetrics)
+	for _, f := range families {
+		metrics[f.GetName()] = &prometheusMetrics{labels: make(map[string]bool)}
+		for _, m := range f.GetMetric() {
+			metrics[f.GetName()].count++
+			for _, l := range m.GetLabel() {
+				metrics[f.GetName()].labels[l.GetName()+"="+l.GetValue()] = true
+			}
+		}
+	}
+
+	cpu := metrics["sakuracloud_server_cpu_time"]
+	require.NotNil(t, cpu)
+	require.Equal(t, 1, cpu.count)
+	require.Contains(t, cpu.labels, "id="+created.ID.String())
+	require.Contains(t, cpu.labels, "name=metrics-test")
+	require.Contains(t, cpu.labels, "zone="+zone)
+
+	success := metrics["sakuracloud_exporter_collect_success"]
+	require.NotNil(t, success)
+	require.Equal(t, 2, success.count)
+	require.Contains(t, success.labels, "kind=Server")
+	require.Contains(t, success.labels, "kind=SIM")
+}
+
+type prometheusMetrics struct {
+	count  int
+	labels map[string]bool
+}
+
+func TestHandler(t *testing.T) {
+	handler, err := Handler(testutil.SingletonAPICaller(), &Options{
+		Kinds: []string{"Server"},
+		Zones: []string{testutil.TestZone()},
+		Tags:  []string{"metrics-test-not-exists"},
+	})
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	require.Equal(t, 200, rec.Code)
+
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	require.True(t, strings.Contains(string(body), `sakuracloud_exporter_collect_success{kind="Server"} 1`), string(body))
+}
+
+func TestNewCollector_validation(t *testing.T) {
+	_, err := NewCollector(testutil.SingletonAPICaller(), &Options{Kinds: []string{"unknown"}})
+	require.True(t, service.IsValidationError(err))
+
+	_, err = NewCollector(testutil.SingletonAPICaller(), &Options{Window: -1})
+	require.True(t, service.IsValidationError(err))
+}
@@ -0,0 +1,207 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/multizone"
+)
+
+// resourceLabels 全てのメトリクスに付与されるラベル
+var resourceLabels = []string{"id", "name", "zone"}
+
+type collectParameter struct {
+	caller      iaas.APICaller
+	zones       []string
+	tags        []string
+	start       time.Time
+	end         time.Time
+	parallelism int
+}
+
+type resource interface {
+	GetID() types.ID
+	GetName() string
+}
+
+type monitorValue interface {
+	GetTime() time.Time
+}
+
+// source リソース種別ごとの収集処理
+type source struct {
+	kind    string
+	descs   []*prometheus.Desc
+	collect func(ctx context.Context, p *collectParameter, ch chan<- prometheus.Metric) error
+}
+
+// newSource リソースを検索し、見つかったリソースごとに各monitorで値を収集するsourceを返す
+func newSource[R resource](kind string, find func(ctx context.Context, p *collectParameter) ([]*multizone.Result[R], error), monitors ...*monitor[R]) *source {
+	var descs []*prometheus.Desc
+	for _, m := range monitors {
+		descs = append(descs, m.descs...)
+	}
+
+	collect := func(ctx context.Context, p *collectParameter, ch chan<- prometheus.Metric) error {
+		found, err := find(ctx, p)
+		if err != nil && len(found) == 0 {
+			return err
+		}
+		errs := []error{err}
+
+		var mu sync.Mutex
+		var wg sync.WaitGroup
+		sem := make(chan struct{}, p.parallelism)
+		for _, r := range found {
+			for _, m := range monitors {
+				wg.Add(1)
+				go func(r *multizone.Result[R], m *monitor[R]) {
+					defer wg.Done()
+					sem <- struct{}{}
+					defer func() { <-sem }()
+
+					if err := m.collect(ctx, p, r, ch); err != nil {
+						mu.Lock()
+						errs = append(errs, fmt.Errorf("%s[%s:%s]: %w", kind, r.Zone, r.Value.GetID(), err))
+						mu.Unlock()
+					}
+				}(r, m)
+			}
+		}
+		wg.Wait()
+		return errors.Join(errs...)
+	}
+
+	return &source{kind: kind, descs: descs, collect: collect}
+}
+
+// series 1つのリソースから取得したモニタリング値の系列
+type series[V monitorValue] struct {
+	labels []string // monitorのextraLabelsに対応する値
+	values []V
+}
+
+// gauge モニタリング値から算出するメトリクス
+type gauge[V monitorValue] struct {
+	name  string
+	help  string
+	value func(v V) float64
+}
+
+// monitor 1つのMonitor*サービスの呼び出しとメトリクスへの変換
+type monitor[R resource] struct {
+	descs   []*prometheus.Desc
+	collect func(ctx context.Context, p *collectParameter, r *multizone.Result[R], ch chan<- prometheus.Metric) error
+}
+
+// newMonitor fetchで取得した系列ごとに、期間内の最新の値をgaugesとして出力するmonitorを返す
+func newMonitor[R resource, V monitorValue](subsystem string, extraLabels []string, fetch func(ctx context.Context, p *collectParameter, zone string, r R) ([]*series[V], error), gauges ...*gauge[V]) *monitor[R] {
+	labels := append(append([]string{}, resourceLabels...), extraLabels...)
+	descs := make([]*prometheus.Desc, len(gauges))
+	for i, g := range gauges {
+		descs[i] = prometheus.NewDesc(prometheus.BuildFQName(Namespace, subsystem, g.name), g.help, labels, nil)
+	}
+
+	collect := func(ctx context.Context, p *collectParameter, r *multizone.Result[R], ch chan<- prometheus.Metric) error {
+		results, err := fetch(ctx, p, r.Zone, r.Value)
+		if err != nil {
+			return err
+		}
+		for _, s := range results {
+			v, ok := latest(s.values)
+			if !ok {
+				continue
+			}
+			labelValues := append([]string{r.Value.GetID().String(), r.Value.GetName(), r.Zone}, s.labels...)
+			for i, g := range gauges {
+				ch <- prometheus.NewMetricWithTimestamp(v.GetTime(),
+					prometheus.MustNewConstMetric(descs[i], prometheus.GaugeValue, g.value(v), labelValues...),
+				)
+			}
+		}
+		return nil
+	}
+
+	return &monitor[R]{descs: descs, collect: collect}
+}
+
+// latest 最新のモニタリング値を返す
+func latest[V monitorValue](values []V) (V, bool) {
+	var result V
+	found := false
+	for _, v := range values {
+		if !found || v.GetTime().After(result.GetTime()) {
+			result = v
+			found = true
+		}
+	}
+	return result, found
+}
+
+// global グローバルリソースの検索結果をmultizone.Resultへ変換する
+func global[R resource](values []R, err error) ([]*multizone.Result[R], error) {
+	var results []*multizone.Result[R]
+	for _, v := range values {
+		results = append(results, &multizone.Result[R]{Value: v})
+	}
+	return results, err
+}
+
+// single 単一の系列を返す
+func single[V monitorValue](values []V, err error) ([]*series[V], error) {
+	if err != nil {
+		return nil, err
+	}
+	return []*series[V]{{values: values}}, nil
+}
+
+// Kinds 収集可能なリソース種別の一覧を返す
+func Kinds() []string {
+	var kinds []string
+	for _, s := range sources {
+		kinds = append(kinds, s.kind)
+	}
+	return kinds
+}
+
+func selectSources(kinds []string) ([]*source, error) {
+	if len(kinds) == 0 {
+		return sources, nil
+	}
+	var selected []*source
+	for _, kind := range kinds {
+		found := false
+		for _, s := range sources {
+			if strings.EqualFold(s.kind, kind) {
+				selected = append(selected, s)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unsupported kind: %q", kind)
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,273 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package metrics
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-service-go/database"
+	"github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/internet"
+	"github.com/sacloud/iaas-service-go/loadbalancer"
+	"github.com/sacloud/iaas-service-go/localrouter"
+	"github.com/sacloud/iaas-service-go/mobilegateway"
+	"github.com/sacloud/iaas-service-go/multizone"
+	"github.com/sacloud/iaas-service-go/nfs"
+	"github.com/sacloud/iaas-service-go/proxylb"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/sacloud/iaas-service-go/sim"
+	"github.com/sacloud/iaas-service-go/simplemonitor"
+	"github.com/sacloud/iaas-service-go/vpcrouter"
+)
+
+var (
+	cpuTimeGauges = []*gauge[*iaas.MonitorCPUTimeValue]{
+		{name: "cpu_time", help: "CPU time", value: func(v *iaas.MonitorCPUTimeValue) float64 { return v.CPUTime }},
+	}
+	interfaceGauges = []*gauge[*iaas.MonitorInterfaceValue]{
+		{name: "receive", help: "Traffic received by the NIC", value: func(v *iaas.MonitorInterfaceValue) float64 { return v.Receive }},
+		{name: "send", help: "Traffic sent by the NIC", value: func(v *iaas.MonitorInterfaceValue) float64 { return v.Send }},
+	}
+)
+
+// sources 収集可能なリソース種別ごとのsource
+var sources = []*source{
+	newSource("Server",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.Server], error) {
+			return server.New(p.caller).FindAllZonesWithContext(ctx, &server.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: server.FindRequest{Tags: p.tags},
+			})
+		},
+		newMonitor("server", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.Server) ([]*series[*iaas.MonitorCPUTimeValue], error) {
+				return single(server.New(p.caller).MonitorCPUWithContext(ctx, &server.MonitorCPURequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			cpuTimeGauges...,
+		),
+	),
+	newSource("Disk",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.Disk], error) {
+			return disk.New(p.caller).FindAllZonesWithContext(ctx, &disk.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: disk.FindRequest{Tags: p.tags},
+			})
+		},
+		newMonitor("disk", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.Disk) ([]*series[*iaas.MonitorDiskValue], error) {
+				return single(disk.New(p.caller).MonitorDiskWithContext(ctx, &disk.MonitorDiskRequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			&gauge[*iaas.MonitorDiskValue]{name: "read", help: "Disk read", value: func(v *iaas.MonitorDiskValue) float64 { return v.Read }},
+			&gauge[*iaas.MonitorDiskValue]{name: "write", help: "Disk write", value: func(v *iaas.MonitorDiskValue) float64 { return v.Write }},
+		),
+	),
+	newSource("VPCRouter",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.VPCRouter], error) {
+			return vpcrouter.New(p.caller).FindAllZonesWithContext(ctx, &vpcrouter.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: vpcrouter.FindRequest{Tags: p.tags},
+			})
+		},
+		newMonitor("vpc_router", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.VPCRouter) ([]*series[*iaas.MonitorCPUTimeValue], error) {
+				return single(vpcrouter.New(p.caller).MonitorCPUWithContext(ctx, &vpcrouter.MonitorCPURequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			cpuTimeGauges...,
+		),
+		newMonitor("vpc_router", []string{"nic"},
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.VPCRouter) ([]*series[*iaas.MonitorInterfaceValue], error) {
+				var results []*series[*iaas.MonitorInterfaceValue]
+				for _, nic := range r.Interfaces {
+					values, err := vpcrouter.New(p.caller).MonitorInterfaceWithContext(ctx, &vpcrouter.MonitorInterfaceRequest{
+						Zone: zone, ID: r.ID, Index: nic.Index, Start: p.start, End: p.end,
+					})
+					if err != nil {
+						return nil, err
+					}
+					results = append(results, &series[*iaas.MonitorInterfaceValue]{labels: []string{strconv.Itoa(nic.Index)}, values: values})
+				}
+				return results, nil
+			},
+			interfaceGauges...,
+		),
+	),
+	newSource("LoadBalancer",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.LoadBalancer], error) {
+			return loadbalancer.New(p.caller).FindAllZonesWithContext(ctx, &loadbalancer.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: loadbalancer.FindRequest{Tags: p.tags},
+			})
+		},
+		newMonitor("load_balancer", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.LoadBalancer) ([]*series[*iaas.MonitorInterfaceValue], error) {
+				return single(loadbalancer.New(p.caller).MonitorInterfaceWithContext(ctx, &loadbalancer.MonitorInterfaceRequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			interfaceGauges...,
+		),
+	),
+	newSource("MobileGateway",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.MobileGateway], error) {
+			return mobilegateway.New(p.caller).FindAllZonesWithContext(ctx, &mobilegateway.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: mobilegateway.FindRequest{Tags: p.tags},
+			})
+		},
+		newMonitor("mobile_gateway", []string{"nic"},
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.MobileGateway) ([]*series[*iaas.MonitorInterfaceValue], error) {
+				var results []*series[*iaas.MonitorInterfaceValue]
+				for _, nic := range r.Interfaces {
+					values, err := mobilegateway.New(p.caller).MonitorInterfaceWithContext(ctx, &mobilegateway.MonitorInterfaceRequest{
+						Zone: zone, ID: r.ID, Index: nic.Index, Start: p.start, End: p.end,
+					})
+					if err != nil {
+						return nil, err
+					}
+					results = append(results, &series[*iaas.MonitorInterfaceValue]{labels: []string{strconv.Itoa(nic.Index)}, values: values})
+				}
+				return results, nil
+			},
+			interfaceGauges...,
+		),
+	),
+	newSource("NFS",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.NFS], error) {
+			return nfs.New(p.caller).FindAllZonesWithContext(ctx, &nfs.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: nfs.FindRequest{Tags: p.tags},
+			})
+		},
+		newMonitor("nfs", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.NFS) ([]*series[*iaas.MonitorFreeDiskSizeValue], error) {
+				return single(nfs.New(p.caller).MonitorFreeDiskSizeWithContext(ctx, &nfs.MonitorFreeDiskSizeRequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			&gauge[*iaas.MonitorFreeDiskSizeValue]{name: "free_disk_size", help: "Free disk size", value: func(v *iaas.MonitorFreeDiskSizeValue) float64 { return v.FreeDiskSize }},
+		),
+		newMonitor("nfs", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.NFS) ([]*series[*iaas.MonitorInterfaceValue], error) {
+				return single(nfs.New(p.caller).MonitorInterfaceWithContext(ctx, &nfs.MonitorInterfaceRequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			interfaceGauges...,
+		),
+	),
+	newSource("Database",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.Database], error) {
+			return database.New(p.caller).FindAllZonesWithContext(ctx, &database.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: database.FindRequest{Tags: p.tags},
+			})
+		},
+		newMonitor("database", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.Database) ([]*series[*iaas.MonitorCPUTimeValue], error) {
+				return single(database.New(p.caller).MonitorCPUWithContext(ctx, &database.MonitorCPURequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			cpuTimeGauges...,
+		),
+		newMonitor("database", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.Database) ([]*series[*iaas.MonitorInterfaceValue], error) {
+				return single(database.New(p.caller).MonitorInterfaceWithContext(ctx, &database.MonitorInterfaceRequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			interfaceGauges...,
+		),
+		newMonitor("database", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.Database) ([]*series[*iaas.MonitorDatabaseValue], error) {
+				return single(database.New(p.caller).MonitorDatabaseWithContext(ctx, &database.MonitorDatabaseRequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			&gauge[*iaas.MonitorDatabaseValue]{name: "memory_size_total", help: "Total memory size", value: func(v *iaas.MonitorDatabaseValue) float64 { return v.TotalMemorySize }},
+			&gauge[*iaas.MonitorDatabaseValue]{name: "memory_size_used", help: "Used memory size", value: func(v *iaas.MonitorDatabaseValue) float64 { return v.UsedMemorySize }},
+			&gauge[*iaas.MonitorDatabaseValue]{name: "disk1_size_total", help: "Total size of the system disk", value: func(v *iaas.MonitorDatabaseValue) float64 { return v.TotalDisk1Size }},
+			&gauge[*iaas.MonitorDatabaseValue]{name: "disk1_size_used", help: "Used size of the system disk", value: func(v *iaas.MonitorDatabaseValue) float64 { return v.UsedDisk1Size }},
+			&gauge[*iaas.MonitorDatabaseValue]{name: "disk2_size_total", help: "Total size of the backup disk", value: func(v *iaas.MonitorDatabaseValue) float64 { return v.TotalDisk2Size }},
+			&gauge[*iaas.MonitorDatabaseValue]{name: "disk2_size_used", help: "Used size of the backup disk", value: func(v *iaas.MonitorDatabaseValue) float64 { return v.UsedDisk2Size }},
+			&gauge[*iaas.MonitorDatabaseValue]{name: "binlog_used_size_kib", help: "Used size of the binary logs in KiB", value: func(v *iaas.MonitorDatabaseValue) float64 { return v.BinlogUsedSizeKiB }},
+			&gauge[*iaas.MonitorDatabaseValue]{name: "replication_delay_seconds", help: "Replication delay in seconds", value: func(v *iaas.MonitorDatabaseValue) float64 { return v.DelayTimeSec }},
+		),
+	),
+	newSource("Internet",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.Internet], error) {
+			return internet.New(p.caller).FindAllZonesWithContext(ctx, &internet.FindAllZonesRequest{
+				Zones: p.zones, Parallelism: p.parallelism, FindRequest: internet.FindRequest{Tags: p.tags},
+			})
+		},
+		newMonitor("internet", nil,
+			func(ctx context.Context, p *collectParameter, zone string, r *iaas.Internet) ([]*series[*iaas.MonitorRouterValue], error) {
+				return single(internet.New(p.caller).MonitorRouterWithContext(ctx, &internet.MonitorRouterRequest{Zone: zone, ID: r.ID, Start: p.start, End: p.end}))
+			},
+			&gauge[*iaas.MonitorRouterValue]{name: "in", help: "Inbound traffic of the router", value: func(v *iaas.MonitorRouterValue) float64 { return v.In }},
+			&gauge[*iaas.MonitorRouterValue]{name: "out", help: "Outbound traffic of the router", value: func(v *iaas.MonitorRouterValue) float64 { return v.Out }},
+		),
+	),
+	newSource("SIM",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.SIM], error) {
+			var values []*iaas.SIM
+			err := sim.New(p.caller).FindAllWithContext(ctx, &sim.FindRequest{Tags: p.tags}, func(v *iaas.SIM) error {
+				values = append(values, v)
+				return nil
+			})
+			return global(values, err)
+		},
+		newMonitor("sim", nil,
+			func(ctx context.Context, p *collectParameter, _ string, r *iaas.SIM) ([]*series[*iaas.MonitorLinkValue], error) {
+				return single(sim.New(p.caller).MonitorSIMWithContext(ctx, &sim.MonitorSIMRequest{ID: r.ID, Start: p.start, End: p.end}))
+			},
+			&gauge[*iaas.MonitorLinkValue]{name: "uplink_bps", help: "Uplink traffic in bps", value: func(v *iaas.MonitorLinkValue) float64 { return v.UplinkBPS }},
+			&gauge[*iaas.MonitorLinkValue]{name: "downlink_bps", help: "Downlink traffic in bps", value: func(v *iaas.MonitorLinkValue) float64 { return v.DownlinkBPS }},
+		),
+	),
+	newSource("ProxyLB",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.ProxyLB], error) {
+			var values []*iaas.ProxyLB
+			err := proxylb.New(p.caller).FindAllWithContext(ctx, &proxylb.FindRequest{Tags: p.tags}, func(v *iaas.ProxyLB) error {
+				values = append(values, v)
+				return nil
+			})
+			return global(values, err)
+		},
+		newMonitor("proxylb", nil,
+			func(ctx context.Context, p *collectParameter, _ string, r *iaas.ProxyLB) ([]*series[*iaas.MonitorConnectionValue], error) {
+				return single(proxylb.New(p.caller).MonitorConnectionWithContext(ctx, &proxylb.MonitorConnectionRequest{ID: r.ID, Start: p.start, End: p.end}))
+			},
+			&gauge[*iaas.MonitorConnectionValue]{name: "active_connections", help: "Number of active connections", value: func(v *iaas.MonitorConnectionValue) float64 { return v.ActiveConnections }},
+			&gauge[*iaas.MonitorConnectionValue]{name: "connections_per_second", help: "Number of connections per second", value: func(v *iaas.MonitorConnectionValue) float64 { return v.ConnectionsPerSec }},
+		),
+	),
+	newSource("SimpleMonitor",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.SimpleMonitor], error) {
+			var values []*iaas.SimpleMonitor
+			err := simplemonitor.New(p.caller).FindAllWithContext(ctx, &simplemonitor.FindRequest{Tags: p.tags}, func(v *iaas.SimpleMonitor) error {
+				values = append(values, v)
+				return nil
+			})
+			return global(values, err)
+		},
+		newMonitor("simple_monitor", nil,
+			func(ctx context.Context, p *collectParameter, _ string, r *iaas.SimpleMonitor) ([]*series[*iaas.MonitorResponseTimeSecValue], error) {
+				return single(simplemonitor.New(p.caller).MonitorResponseTimeWithContext(ctx, &simplemonitor.MonitorResponseTimeRequest{ID: r.ID, Start: p.start, End: p.end}))
+			},
+			&gauge[*iaas.MonitorResponseTimeSecValue]{name: "response_time_seconds", help: "Response time of the monitored target", value: func(v *iaas.MonitorResponseTimeSecValue) float64 { return v.ResponseTimeSec }},
+		),
+	),
+	newSource("LocalRouter",
+		func(ctx context.Context, p *collectParameter) ([]*multizone.Result[*iaas.LocalRouter], error) {
+			var values []*iaas.LocalRouter
+			err := localrouter.New(p.caller).FindAllWithContext(ctx, &localrouter.FindRequest{Tags: p.tags}, func(v *iaas.LocalRouter) error {
+				values = append(values, v)
+				return nil
+			})
+			return global(values, err)
+		},
+		newMonitor("local_router", nil,
+			func(ctx context.Context, p *collectParameter, _ string, r *iaas.LocalRouter) ([]*series[*iaas.MonitorLocalRouterValue], error) {
+				return single(localrouter.New(p.caller).MonitorLocalRouterWithContext(ctx, &localrouter.MonitorLocalRouterRequest{ID: r.ID, Start: p.start, End: p.end}))
+			},
+			&gauge[*iaas.MonitorLocalRouterValue]{name: "receive_bytes_per_second", help: "Received bytes per second", value: func(v *iaas.MonitorLocalRouterValue) float64 { return v.ReceiveBytesPerSec }},
+			&gauge[*iaas.MonitorLocalRouterValue]{name: "send_bytes_per_second", help: "Sent bytes per second", value: func(v *iaas.MonitorLocalRouterValue) float64 { return v.SendBytesPerSec }},
+		),
+	),
+}
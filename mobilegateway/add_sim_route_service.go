@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) AddSIMRoute(req *AddSIMRouteRequest) error {
 	return s.AddSIMRouteWithContext(context.Background(), req)
 }
 
-func (s *Service) AddSIMRouteWithContext(ctx context.Context, req *AddSIMRouteRequest) error {
+func (s *Service) AddSIMRouteWithContext(ctx context.Context, req *AddSIMRouteRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "AddSIMRoute", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "AddSIMRoute", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
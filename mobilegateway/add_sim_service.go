@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) AddSIM(req *AddSIMRequest) error {
 	return s.AddSIMWithContext(context.Background(), req)
 }
 
-func (s *Service) AddSIMWithContext(ctx context.Context, req *AddSIMRequest) error {
+func (s *Service) AddSIMWithContext(ctx context.Context, req *AddSIMRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "AddSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "AddSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	mgwOp := iaas.NewMobileGatewayOp(s.caller)
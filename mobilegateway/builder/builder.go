@@ -19,7 +19,6 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
-	"time"
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/accessor"
@@ -148,11 +147,10 @@ func FromResource(ctx context.Context, caller iaas.APICaller, zone string, id ty
 }
 
 func (b *Builder) init() {
-	if b.SetupOptions == nil {
-		b.SetupOptions = &setup2.Options{}
-	}
-	b.SetupOptions.Init()
-	b.SetupOptions.ProvisioningRetryCount = 1
+	// 呼び出し元と共有している可能性があるためコピーに対して変更する
+	options := b.SetupOptions.WithDefaults()
+	options.ProvisioningRetryCount = 1
+	b.SetupOptions = options
 }
 
 // Validate 設定値の検証
@@ -221,7 +219,9 @@ func (b *Builder) create(ctx context.Context, zone string) (*iaas.MobileGateway,
 			}
 
 			// [HACK] スイッチ接続直後だとエラーになることがあるため数秒待つ
-			time.Sleep(b.SetupOptions.NICUpdateWaitDuration)
+			if err := setup2.Wait(ctx, b.SetupOptions.NICUpdateWaitDuration); err != nil {
+				return err
+			}
 
 			// Interface設定
 			updated, err := b.Client.MobileGateway.UpdateSettings(ctx, zone, id, &iaas.MobileGatewayUpdateSettingsRequest{
@@ -304,6 +304,7 @@ func (b *Builder) create(ctx context.Context, zone string) (*iaas.MobileGateway,
 		},
 		IsWaitForCopy: !b.NoWait,
 		IsWaitForUp:   !b.NoWait && b.SetupOptions.BootAfterBuild,
+		Kind:          "MobileGateway",
 		Options:       b.SetupOptions,
 	}
 
@@ -366,7 +367,9 @@ func (b *Builder) update(ctx context.Context, zone string, id types.ID) (*iaas.M
 					return nil, err
 				}
 				// [HACK] スイッチ接続直後だとエラーになることがあるため数秒待つ
-				time.Sleep(b.SetupOptions.NICUpdateWaitDuration)
+				if err := setup2.Wait(ctx, b.SetupOptions.NICUpdateWaitDuration); err != nil {
+					return nil, err
+				}
 
 				updated, err := b.Client.MobileGateway.UpdateSettings(ctx, zone, id, &iaas.MobileGatewayUpdateSettingsRequest{
 					InternetConnectionEnabled:       types.StringFlag(b.InternetConnectionEnabled),
@@ -394,7 +397,9 @@ func (b *Builder) update(ctx context.Context, zone string, id types.ID) (*iaas.M
 				}
 
 				// [HACK] スイッチ接続直後だとエラーになることがあるため数秒待つ
-				time.Sleep(b.SetupOptions.NICUpdateWaitDuration)
+				if err := setup2.Wait(ctx, b.SetupOptions.NICUpdateWaitDuration); err != nil {
+					return nil, err
+				}
 			}
 
 			// Interface設定
@@ -0,0 +1,145 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSet Buildを行った場合の変更内容を返す
+//
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context) (*service.ChangeSet, error) {
+	b.init()
+	if err := b.Validate(ctx, b.Zone); err != nil {
+		return nil, err
+	}
+
+	if b.ID.IsEmpty() {
+		cs := service.NewCreateChangeSet()
+		cs.Create("Name", b.Name)
+		cs.Create("Description", b.Description)
+		cs.Create("Tags", b.Tags)
+		cs.Create("IconID", b.IconID)
+		if b.PrivateInterface != nil {
+			cs.Create("PrivateInterface", *b.PrivateInterface)
+		}
+		cs.Create("InternetConnectionEnabled", b.InternetConnectionEnabled)
+		cs.Create("InterDeviceCommunicationEnabled", b.InterDeviceCommunicationEnabled)
+		cs.Create("StaticRoutes", b.StaticRoutes)
+		cs.Create("DNS", b.DNS)
+		cs.Create("TrafficConfig", b.TrafficConfig)
+		for _, sim := range b.SIMs {
+			cs.Create(fmt.Sprintf("SIMs[%s]", sim.SIMID), sim.IPAddress)
+		}
+		cs.Create("SIMRoutes", b.simRouteStrings())
+		return cs, nil
+	}
+
+	mgw, err := b.Client.MobileGateway.Read(ctx, b.Zone, b.ID)
+	if err != nil {
+		return nil, err
+	}
+	isNeedShutdown, err := b.collectUpdateInfo(mgw)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := service.NewUpdateChangeSet(mgw.ID)
+	cs.Update("Name", mgw.Name, b.Name)
+	cs.Update("Description", mgw.Description, b.Description)
+	cs.Update("Tags", mgw.Tags, b.Tags)
+	cs.Update("IconID", mgw.IconID, b.IconID)
+
+	currentInterface := b.currentPrivateInterfaceState(mgw)
+	switch {
+	case currentInterface == nil && b.PrivateInterface != nil:
+		cs.Create("PrivateInterface", *b.PrivateInterface)
+	case currentInterface != nil && b.PrivateInterface == nil:
+		cs.Delete("PrivateInterface", *currentInterface)
+	case currentInterface != nil && b.PrivateInterface != nil:
+		cs.Update("PrivateInterface", *currentInterface, *b.PrivateInterface)
+	}
+	if isNeedShutdown {
+		cs.NeedShutdown()
+	}
+
+	cs.Update("InternetConnectionEnabled", mgw.InternetConnectionEnabled.Bool(), b.InternetConnectionEnabled)
+	cs.Update("InterDeviceCommunicationEnabled", mgw.InterDeviceCommunicationEnabled.Bool(), b.InterDeviceCommunicationEnabled)
+	if len(b.StaticRoutes) > 0 {
+		cs.Update("StaticRoutes", mgw.StaticRoutes, b.StaticRoutes)
+	}
+
+	trafficConfig, err := b.Client.MobileGateway.GetTrafficConfig(ctx, b.Zone, b.ID)
+	if err != nil && !iaas.IsNotFoundError(err) {
+		return nil, err
+	}
+	cs.Update("TrafficConfig", trafficConfig, b.TrafficConfig)
+
+	dns, err := b.Client.MobileGateway.GetDNS(ctx, b.Zone, b.ID)
+	if err != nil && !iaas.IsNotFoundError(err) {
+		return nil, err
+	}
+	if b.DNS != nil {
+		cs.Update("DNS", dns, b.DNS)
+	}
+
+	currentSIMs, err := b.currentConnectedSIMs(ctx, b.Zone, b.ID)
+	if err != nil {
+		return nil, err
+	}
+	added, updated, deleted := b.changedSIMs(currentSIMs, b.SIMs)
+	for _, sim := range deleted {
+		cs.Delete(fmt.Sprintf("SIMs[%s]", sim.SIMID), sim.IPAddress)
+	}
+	for _, sim := range updated {
+		for _, c := range currentSIMs {
+			if c.SIMID == sim.SIMID {
+				cs.Update(fmt.Sprintf("SIMs[%s]", sim.SIMID), c.IPAddress, sim.IPAddress)
+			}
+		}
+	}
+	for _, sim := range added {
+		cs.Create(fmt.Sprintf("SIMs[%s]", sim.SIMID), sim.IPAddress)
+	}
+
+	currentSIMRoutes, err := b.currentSIMRoutes(ctx, b.Zone, b.ID)
+	if err != nil {
+		return nil, err
+	}
+	var currentRoutes []string
+	for _, r := range currentSIMRoutes {
+		currentRoutes = append(currentRoutes, simRouteString(types.StringID(r.ResourceID), r.Prefix))
+	}
+	cs.Update("SIMRoutes", currentRoutes, b.simRouteStrings())
+	return cs, nil
+}
+
+func (b *Builder) simRouteStrings() []string {
+	var results []string
+	for _, r := range b.SIMRoutes {
+		results = append(results, simRouteString(r.SIMID, r.Prefix))
+	}
+	return results
+}
+
+func simRouteString(simID types.ID, prefix string) string {
+	return fmt.Sprintf("%s:%s", prefix, simID)
+}
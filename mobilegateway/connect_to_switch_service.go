@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ConnectToSwitch(req *ConnectToSwitchRequest) error {
 	return s.ConnectToSwitchWithContext(context.Background(), req)
 }
 
-func (s *Service) ConnectToSwitchWithContext(ctx context.Context, req *ConnectToSwitchRequest) error {
+func (s *Service) ConnectToSwitchWithContext(ctx context.Context, req *ConnectToSwitchRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ConnectToSwitch", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "ConnectToSwitch", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
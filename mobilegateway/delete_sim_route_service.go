@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DeleteSIMRoute(req *DeleteSIMRouteRequest) error {
 	return s.DeleteSIMRouteWithContext(context.Background(), req)
 }
 
-func (s *Service) DeleteSIMRouteWithContext(ctx context.Context, req *DeleteSIMRouteRequest) error {
+func (s *Service) DeleteSIMRouteWithContext(ctx context.Context, req *DeleteSIMRouteRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DeleteSIMRoute", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DeleteSIMRoute", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
@@ -19,15 +19,19 @@ import (
 	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DeleteSIM(req *DeleteSIMRequest) error {
 	return s.DeleteSIMWithContext(context.Background(), req)
 }
 
-func (s *Service) DeleteSIMWithContext(ctx context.Context, req *DeleteSIMRequest) error {
+func (s *Service) DeleteSIMWithContext(ctx context.Context, req *DeleteSIMRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DeleteSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DeleteSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	mgwOp := iaas.NewMobileGatewayOp(s.caller)
@@ -38,7 +42,7 @@ func (s *Service) DeleteSIMWithContext(ctx context.Context, req *DeleteSIMReques
 		return err
 	}
 	if !sims.Exists(req.SIMID) {
-		return fmt.Errorf("SIM[%s] not found in MobileGatewaySIMs", req.SIMID.String())
+		return &service.Error{Op: "DeleteSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindNotFound, Err: fmt.Errorf("SIM[%s] not found in MobileGatewaySIMs", req.SIMID.String())}
 	}
 
 	if err := simOp.ClearIP(ctx, req.SIMID); err != nil {
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DeleteTrafficConfig(req *DeleteTrafficConfigRequest) error {
 	return s.DeleteTrafficConfigWithContext(context.Background(), req)
 }
 
-func (s *Service) DeleteTrafficConfigWithContext(ctx context.Context, req *DeleteTrafficConfigRequest) error {
+func (s *Service) DeleteTrafficConfigWithContext(ctx context.Context, req *DeleteTrafficConfigRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DeleteTrafficConfig", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DeleteTrafficConfig", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
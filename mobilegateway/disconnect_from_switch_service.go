@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DisconnectFromSwitch(req *DisconnectFromSwitchRequest) error {
 	return s.DisconnectFromSwitchWithContext(context.Background(), req)
 }
 
-func (s *Service) DisconnectFromSwitchWithContext(ctx context.Context, req *DisconnectFromSwitchRequest) error {
+func (s *Service) DisconnectFromSwitchWithContext(ctx context.Context, req *DisconnectFromSwitchRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DisconnectFromSwitch", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DisconnectFromSwitch", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) GetDNS(req *GetDNSRequest) (*iaas.MobileGatewayDNSSetting, error) {
 	return s.GetDNSWithContext(context.Background(), req)
 }
 
-func (s *Service) GetDNSWithContext(ctx context.Context, req *GetDNSRequest) (*iaas.MobileGatewayDNSSetting, error) {
+func (s *Service) GetDNSWithContext(ctx context.Context, req *GetDNSRequest) (_ *iaas.MobileGatewayDNSSetting, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "GetDNS", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "GetDNS", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) GetTrafficConfig(req *GetTrafficConfigRequest) (*iaas.MobileGatewayTrafficControl, error) {
 	return s.GetTrafficConfigWithContext(context.Background(), req)
 }
 
-func (s *Service) GetTrafficConfigWithContext(ctx context.Context, req *GetTrafficConfigRequest) (*iaas.MobileGatewayTrafficControl, error) {
+func (s *Service) GetTrafficConfigWithContext(ctx context.Context, req *GetTrafficConfigRequest) (_ *iaas.MobileGatewayTrafficControl, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "GetTrafficConfig", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "GetTrafficConfig", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
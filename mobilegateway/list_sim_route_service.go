@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ListSIMRoute(req *ListSIMRouteRequest) (iaas.MobileGatewaySIMRoutes, error) {
 	return s.ListSIMRouteWithContext(context.Background(), req)
 }
 
-func (s *Service) ListSIMRouteWithContext(ctx context.Context, req *ListSIMRouteRequest) (iaas.MobileGatewaySIMRoutes, error) {
+func (s *Service) ListSIMRouteWithContext(ctx context.Context, req *ListSIMRouteRequest) (_ iaas.MobileGatewaySIMRoutes, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ListSIMRoute", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "ListSIMRoute", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
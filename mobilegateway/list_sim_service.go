@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ListSIM(req *ListSIMRequest) (iaas.MobileGatewaySIMs, error) {
 	return s.ListSIMWithContext(context.Background(), req)
 }
 
-func (s *Service) ListSIMWithContext(ctx context.Context, req *ListSIMRequest) (iaas.MobileGatewaySIMs, error) {
+func (s *Service) ListSIMWithContext(ctx context.Context, req *ListSIMRequest) (_ iaas.MobileGatewaySIMs, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ListSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "ListSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) SetTrafficConfig(req *SetTrafficConfigRequest) error {
 	return s.SetTrafficConfigWithContext(context.Background(), req)
 }
 
-func (s *Service) SetTrafficConfigWithContext(ctx context.Context, req *SetTrafficConfigRequest) error {
+func (s *Service) SetTrafficConfigWithContext(ctx context.Context, req *SetTrafficConfigRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "SetTrafficConfig", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "SetTrafficConfig", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) TrafficStatus(req *TrafficStatusRequest) (*iaas.MobileGatewayTrafficStatus, error) {
 	return s.TrafficStatusWithContext(context.Background(), req)
 }
 
-func (s *Service) TrafficStatusWithContext(ctx context.Context, req *TrafficStatusRequest) (*iaas.MobileGatewayTrafficStatus, error) {
+func (s *Service) TrafficStatusWithContext(ctx context.Context, req *TrafficStatusRequest) (_ *iaas.MobileGatewayTrafficStatus, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "TrafficStatus", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "TrafficStatus", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
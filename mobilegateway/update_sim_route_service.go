@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) UpdateSIMRoute(req *UpdateSIMRouteRequest) error {
 	return s.UpdateSIMRouteWithContext(context.Background(), req)
 }
 
-func (s *Service) UpdateSIMRouteWithContext(ctx context.Context, req *UpdateSIMRouteRequest) error {
+func (s *Service) UpdateSIMRouteWithContext(ctx context.Context, req *UpdateSIMRouteRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "UpdateSIMRoute", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "UpdateSIMRoute", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
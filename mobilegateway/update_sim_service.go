@@ -19,15 +19,19 @@ import (
 	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) UpdateSIM(req *UpdateSIMRequest) error {
 	return s.UpdateSIMWithContext(context.Background(), req)
 }
 
-func (s *Service) UpdateSIMWithContext(ctx context.Context, req *UpdateSIMRequest) error {
+func (s *Service) UpdateSIMWithContext(ctx context.Context, req *UpdateSIMRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "UpdateSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "UpdateSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	mgwOp := iaas.NewMobileGatewayOp(s.caller)
@@ -38,7 +42,7 @@ func (s *Service) UpdateSIMWithContext(ctx context.Context, req *UpdateSIMReques
 		return err
 	}
 	if !sims.Exists(req.SIMID) {
-		return fmt.Errorf("SIM[%s] not found in MobileGatewaySIMs", req.SIMID.String())
+		return &service.Error{Op: "UpdateSIM", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindNotFound, Err: fmt.Errorf("SIM[%s] not found in MobileGatewaySIMs", req.SIMID.String())}
 	}
 
 	return simOp.AssignIP(ctx, req.SIMID, &iaas.SIMAssignIPRequest{IP: req.IPAddress})
@@ -19,18 +19,28 @@ import (
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/wait"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
 )
 
 func (s *Service) WaitBoot(req *WaitBootRequest) error {
 	return s.WaitBootWithContext(context.Background(), req)
 }
 
-func (s *Service) WaitBootWithContext(ctx context.Context, req *WaitBootRequest) error {
+func (s *Service) WaitBootWithContext(ctx context.Context, req *WaitBootRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "WaitBoot", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "WaitBoot", Resource: "MobileGateway", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewMobileGatewayOp(s.caller)
-	_, err := wait.UntilMobileGatewayIsUp(ctx, client, req.Zone, req.ID)
+	resource := progress.Resource{Kind: "MobileGateway", Zone: req.Zone, ID: req.ID}
+	done := progress.StartPhase(ctx, resource, progress.PhaseWaitForBoot)
+	_, err = iaas.WaiterForApplianceUp(progress.ObserveState(ctx, resource, func() (interface{}, error) {
+		return client.Read(ctx, req.Zone, req.ID)
+	}), wait.ApplianceNotFoundRetryCount).WaitForState(ctx)
+	done(err)
 	return err
 }
@@ -0,0 +1,146 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multizone
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-service-go/zone"
+)
+
+// DefaultParallelism 同時に処理するゾーン数のデフォルト値
+const DefaultParallelism = 4
+
+// FindFunc 単一ゾーンでの検索処理
+type FindFunc[T any] func(ctx context.Context, zone string) ([]T, error)
+
+// Result ゾーン情報付きの検索結果
+type Result[T any] struct {
+	Zone  string
+	Value T
+}
+
+// ZoneError 特定のゾーンでの処理中に発生したエラー
+type ZoneError struct {
+	Zone string
+	Err  error
+}
+
+// Error errorインターフェースの実装
+func (e *ZoneError) Error() string {
+	return fmt.Sprintf("zone[%s]: %s", e.Zone, e.Err)
+}
+
+// Unwrap 元のエラーを返す
+func (e *ZoneError) Unwrap() error {
+	return e.Err
+}
+
+// Error 1つ以上のゾーンで処理が失敗したことを示すエラー
+//
+// 成功したゾーンの結果はこのエラーと共に返される。
+type Error struct {
+	Errors []*ZoneError
+}
+
+// Error errorインターフェースの実装
+func (e *Error) Error() string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("failed in %d zone(s): %s", len(e.Errors), strings.Join(messages, ", "))
+}
+
+// Unwrap ゾーンごとのエラーを返す
+func (e *Error) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// Zones zoneサービスを用いて利用可能な全ゾーンの名前を返す
+func Zones(ctx context.Context, caller iaas.APICaller) ([]string, error) {
+	zones, err := zone.New(caller).FindWithContext(ctx, &zone.FindRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, z := range zones {
+		names = append(names, z.Name)
+	}
+	return names, nil
+}
+
+// Find 複数ゾーンに対しfindを並列に実行し、ゾーン情報付きの結果を返す
+//
+// zonesが空の場合は全ゾーンが対象となる。parallelismが0以下の場合はDefaultParallelismが利用される。
+// 結果はzonesの順に並ぶ。一部のゾーンで失敗した場合は成功したゾーンの結果と*Errorを返す。
+func Find[T any](ctx context.Context, caller iaas.APICaller, zones []string, parallelism int, find FindFunc[T]) ([]*Result[T], error) {
+	if len(zones) == 0 {
+		all, err := Zones(ctx, caller)
+		if err != nil {
+			return nil, err
+		}
+		zones = all
+	}
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	values := make([][]T, len(zones))
+	errs := make([]error, len(zones))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, parallelism)
+	for i := range zones {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			select {
+			case sem <- struct{}{}:
+				defer func() { <-sem }()
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			values[i], errs[i] = find(ctx, zones[i])
+		}(i)
+	}
+	wg.Wait()
+
+	var results []*Result[T]
+	zoneErr := &Error{}
+	for i, z := range zones {
+		if errs[i] != nil {
+			zoneErr.Errors = append(zoneErr.Errors, &ZoneError{Zone: z, Err: errs[i]})
+			continue
+		}
+		for _, v := range values[i] {
+			results = append(results, &Result[T]{Zone: z, Value: v})
+		}
+	}
+	if len(zoneErr.Errors) > 0 {
+		return results, zoneErr
+	}
+	return results, nil
+}
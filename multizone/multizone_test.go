@@ -0,0 +1,91 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package multizone
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("results are annotated with zone", func(t *testing.T) {
+		results, err := Find(ctx, nil, []string{"is1a", "tk1a"}, 1, func(ctx context.Context, zone string) ([]string, error) {
+			return []string{zone + "-1", zone + "-2"}, nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []*Result[string]{
+			{Zone: "is1a", Value: "is1a-1"},
+			{Zone: "is1a", Value: "is1a-2"},
+			{Zone: "tk1a", Value: "tk1a-1"},
+			{Zone: "tk1a", Value: "tk1a-2"},
+		}, results)
+	})
+
+	t.Run("partial errors", func(t *testing.T) {
+		findErr := errors.New("dummy")
+		results, err := Find(ctx, nil, []string{"is1a", "is1b", "tk1a"}, 0, func(ctx context.Context, zone string) ([]string, error) {
+			if zone == "is1b" {
+				return nil, findErr
+			}
+			return []string{zone}, nil
+		})
+		require.Len(t, results, 2)
+
+		var zoneErr *Error
+		require.True(t, errors.As(err, &zoneErr))
+		require.Len(t, zoneErr.Errors, 1)
+		require.Equal(t, "is1b", zoneErr.Errors[0].Zone)
+		require.True(t, errors.Is(err, findErr))
+	})
+
+	t.Run("parallelism", func(t *testing.T) {
+		var running, maxRunning int32
+		_, err := Find(ctx, nil, []string{"is1a", "is1b", "tk1a", "tk1b", "tk1v"}, 2, func(ctx context.Context, zone string) ([]string, error) {
+			current := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				prev := atomic.LoadInt32(&maxRunning)
+				if current <= prev || atomic.CompareAndSwapInt32(&maxRunning, prev, current) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+			return nil, nil
+		})
+		require.NoError(t, err)
+		require.LessOrEqual(t, maxRunning, int32(2))
+	})
+
+	t.Run("all zones", func(t *testing.T) {
+		caller := testutil.SingletonAPICaller()
+		zones, err := Zones(ctx, caller)
+		require.NoError(t, err)
+		require.NotEmpty(t, zones)
+
+		results, err := Find(ctx, caller, nil, 0, func(ctx context.Context, zone string) ([]string, error) {
+			return []string{zone}, nil
+		})
+		require.NoError(t, err)
+		require.Len(t, results, len(zones))
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorFreeDiskSize(req *MonitorFreeDiskSizeRequest) ([]*iaas.
 	return s.MonitorFreeDiskSizeWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorFreeDiskSizeWithContext(ctx context.Context, req *MonitorFreeDiskSizeRequest) ([]*iaas.MonitorFreeDiskSizeValue, error) {
+func (s *Service) MonitorFreeDiskSizeWithContext(ctx context.Context, req *MonitorFreeDiskSizeRequest) (_ []*iaas.MonitorFreeDiskSizeValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorFreeDiskSize", Resource: "NFS", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorFreeDiskSize", Resource: "NFS", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewNFSOp(s.caller)
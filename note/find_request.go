@@ -18,6 +18,7 @@ import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
 	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -29,6 +30,8 @@ type FindRequest struct {
 	Tags  []string     `service:"-"`
 	Scope types.EScope `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -55,5 +58,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.Scope) {
 		condition.Filter[search.Key("Scope")] = search.OrEqual(req.Scope)
 	}
+	filter.SetCondition[*iaas.Note](&req.Filter, condition)
 	return condition, nil
 }
@@ -0,0 +1,97 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package iaas
+
+import (
+	"context"
+	"errors"
+
+	api "github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// Operation 各サービスの操作
+type Operation struct {
+	Name     string   // 操作名(例: Update)
+	Resource string   // リソース種別(例: Server)
+	Zone     string   // ゾーン名、グローバルリソースの場合は空
+	ID       types.ID // 対象リソースのID、未確定の場合は空
+
+	Request interface{} // 操作のリクエスト、リクエストを持たない操作の場合はnil
+}
+
+// OperationHook 各サービスの操作の開始/終了を受け取るインターフェース
+//
+// 各サービスのコンストラクタに渡すAPICaller(CallerWrapperでラップされたものを含む)がこのインターフェースを実装している場合、
+// サービスの操作ごとにStartOperationが呼ばれる
+type OperationHook interface {
+	// StartOperation 操作の開始時(リクエストの検証前)に呼ばれる、検証エラーで失敗した操作も終了時の関数に渡される
+	//
+	// 戻り値のcontextは操作中のAPI呼び出しに引き継がれ、戻り値の関数は操作の終了時に結果のエラーを引数に呼ばれる
+	StartOperation(ctx context.Context, op *Operation) (context.Context, func(err error))
+}
+
+// CallerWrapper 他のAPICallerをラップするAPICallerが実装するインターフェース
+//
+// StartOperationはUnwrapをたどり、ラップされたAPICallerのOperationHookも呼び出す
+type CallerWrapper interface {
+	// Unwrap ラップしているAPICallerを返す
+	Unwrap() api.APICaller
+}
+
+// StartOperation callerとcallerがラップしているAPICallerのうち、OperationHookを実装しているもののStartOperationを外側から順に呼び出す
+//
+// 戻り値の関数は操作の終了時に結果のエラーを渡して呼び出す。
+// エラーは操作名やリソース種別などを付与した*Errorでラップして返すため、各サービスでは
+//
+//	defer func() { err = finish(err) }()
+//
+// のようにして結果のエラーを置き換える
+func StartOperation(ctx context.Context, caller api.APICaller, op *Operation) (context.Context, func(err error) error) {
+	var finishes []func(error)
+	for caller != nil {
+		if hook, ok := caller.(OperationHook); ok {
+			var finish func(error)
+			ctx, finish = hook.StartOperation(ctx, op)
+			finishes = append(finishes, finish)
+		}
+		wrapper, ok := caller.(CallerWrapper)
+		if !ok {
+			break
+		}
+		caller = wrapper.Unwrap()
+	}
+	return ctx, func(err error) error {
+		err = wrapOperationError(op, err)
+		for i := len(finishes) - 1; i >= 0; i-- {
+			finishes[i](err)
+		}
+		return err
+	}
+}
+
+// wrapOperationError errをopの情報を持つ*Errorでラップする
+//
+// errが同じ操作の*Errorの場合はそのまま返す
+func wrapOperationError(op *Operation, err error) error {
+	if err == nil {
+		return nil
+	}
+	var serviceErr *Error
+	if errors.As(err, &serviceErr) && serviceErr.Op == op.Name && serviceErr.Resource == op.Resource {
+		return err
+	}
+	return &Error{Op: op.Name, Resource: op.Resource, Zone: op.Zone, ID: op.ID, Err: err}
+}
@@ -0,0 +1,64 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"context"
+	"errors"
+)
+
+// DefaultPageSize 1ページあたりの取得件数のデフォルト値
+const DefaultPageSize = 100
+
+// ErrStop コールバックからこのエラーを返すと、エラーとせずに列挙を中断する
+var ErrStop = errors.New("stop iteration")
+
+// PageFunc fromとcountを指定して1ページ分の結果と総件数を取得する
+type PageFunc[T any] func(ctx context.Context, from, count int) (values []T, total int, err error)
+
+// Each fromの位置から全ページを順に取得し、要素ごとにfnを呼び出す
+//
+// pageSizeが0以下の場合はDefaultPageSizeが利用される。
+// 総件数に達するか空のページが返されるまで取得を続ける。
+// fnがエラーを返した場合やctxがキャンセルされた場合は中断してそのエラーを返す(ErrStopの場合はnil)。
+func Each[T any](ctx context.Context, from, pageSize int, page PageFunc[T], fn func(v T) error) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		values, total, err := page(ctx, from, pageSize)
+		if err != nil {
+			return err
+		}
+		for _, v := range values {
+			if err := fn(v); err != nil {
+				if errors.Is(err, ErrStop) {
+					return nil
+				}
+				return err
+			}
+		}
+
+		from += len(values)
+		if len(values) == 0 || from >= total {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package pagination
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func dummyPageFunc(total int, calls *[][2]int) PageFunc[int] {
+	return func(ctx context.Context, from, count int) ([]int, int, error) {
+		*calls = append(*calls, [2]int{from, count})
+		var values []int
+		for i := from; i < from+count && i < total; i++ {
+			values = append(values, i)
+		}
+		return values, total, nil
+	}
+}
+
+func TestEach(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all pages", func(t *testing.T) {
+		var calls [][2]int
+		var values []int
+		err := Each(ctx, 0, 3, dummyPageFunc(7, &calls), func(v int) error {
+			values = append(values, v)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 2, 3, 4, 5, 6}, values)
+		require.Equal(t, [][2]int{{0, 3}, {3, 3}, {6, 3}}, calls)
+	})
+
+	t.Run("from and default page size", func(t *testing.T) {
+		var calls [][2]int
+		count := 0
+		err := Each(ctx, 5, 0, dummyPageFunc(250, &calls), func(v int) error {
+			count++
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, 245, count)
+		require.Equal(t, [][2]int{{5, DefaultPageSize}, {105, DefaultPageSize}, {205, DefaultPageSize}}, calls)
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		var calls [][2]int
+		var values []int
+		err := Each(ctx, 0, 2, dummyPageFunc(10, &calls), func(v int) error {
+			if v == 3 {
+				return ErrStop
+			}
+			values = append(values, v)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, []int{0, 1, 2}, values)
+		require.Len(t, calls, 2)
+	})
+
+	t.Run("callback error", func(t *testing.T) {
+		var calls [][2]int
+		fnErr := errors.New("dummy")
+		err := Each(ctx, 0, 2, dummyPageFunc(10, &calls), func(v int) error {
+			return fnErr
+		})
+		require.ErrorIs(t, err, fnErr)
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		var calls [][2]int
+		err := Each(ctx, 0, 2, dummyPageFunc(10, &calls), func(v int) error {
+			if v == 1 {
+				cancel()
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Len(t, calls, 1)
+	})
+}
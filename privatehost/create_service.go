@@ -19,15 +19,19 @@ import (
 	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Create(req *CreateRequest) (*iaas.PrivateHost, error) {
 	return s.CreateWithContext(context.Background(), req)
 }
 
-func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (*iaas.PrivateHost, error) {
+func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (_ *iaas.PrivateHost, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Create", Resource: "PrivateHost", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Create", Resource: "PrivateHost", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	if req.PlanID.IsEmpty() {
@@ -43,7 +47,7 @@ func (s *Service) CreateWithContext(ctx context.Context, req *CreateRequest) (*i
 			}
 		}
 		if req.PlanID.IsEmpty() {
-			return nil, fmt.Errorf("PrivateHostPlan with Class=%q not found", req.Class)
+			return nil, &service.Error{Op: "Create", Resource: "PrivateHost", Zone: req.Zone, Kind: service.ErrorKindNotFound, Err: fmt.Errorf("PrivateHostPlan with Class=%q not found", req.Class)}
 		}
 	}
 
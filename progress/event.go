@@ -0,0 +1,185 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package progress
+
+import (
+	"time"
+
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// EventType イベントの種別
+type EventType string
+
+const (
+	// EventTypePhaseStarted 処理の開始
+	EventTypePhaseStarted = EventType("phase-started")
+	// EventTypePhaseFinished 処理の終了
+	EventTypePhaseFinished = EventType("phase-finished")
+	// EventTypeAvailabilityChanged リソースの状態(Availability/InstanceStatus)の変化
+	EventTypeAvailabilityChanged = EventType("availability-changed")
+	// EventTypeRetryAttempt リトライ
+	EventTypeRetryAttempt = EventType("retry-attempt")
+	// EventTypeResourceCreated リソースの作成
+	EventTypeResourceCreated = EventType("resource-created")
+	// EventTypeResourceDeleted リソースの削除
+	EventTypeResourceDeleted = EventType("resource-deleted")
+	// EventTypeCopyProgress ディスク/アーカイブのコピーの進捗
+	EventTypeCopyProgress = EventType("copy-progress")
+)
+
+// Phase 処理の段階
+type Phase string
+
+const (
+	// PhaseWaitForCopy ディスク/アーカイブのコピー待ち
+	PhaseWaitForCopy = Phase("wait-for-copy")
+	// PhaseWaitForReady 利用可能になるまでの待ち
+	PhaseWaitForReady = Phase("wait-for-ready")
+	// PhaseWaitForBoot 起動待ち
+	PhaseWaitForBoot = Phase("wait-for-boot")
+	// PhaseWaitForShutdown シャットダウン待ち
+	PhaseWaitForShutdown = Phase("wait-for-shutdown")
+	// PhaseProvisioning 作成後、起動前のプロビジョニング
+	PhaseProvisioning = Phase("provisioning")
+	// PhaseTransfer アーカイブのゾーン間転送
+	PhaseTransfer = Phase("transfer")
+	// PhaseValidate プランの参照などを含むパラメータの検証
+	PhaseValidate = Phase("validate")
+	// PhaseBuildDisks ディスクの作成と接続
+	PhaseBuildDisks = Phase("build-disks")
+	// PhaseReconcileDisks 既存リソースのディスク構成の更新
+	PhaseReconcileDisks = Phase("reconcile-disks")
+	// PhaseReconcileInterfaces 既存リソースのNIC構成の更新
+	PhaseReconcileInterfaces = Phase("reconcile-interfaces")
+	// PhaseChangePlan プラン変更
+	PhaseChangePlan = Phase("change-plan")
+	// PhaseBoot 起動(起動待ちを含む)
+	PhaseBoot = Phase("boot")
+	// PhaseShutdown シャットダウン(シャットダウン待ちを含む)
+	PhaseShutdown = Phase("shutdown")
+	// PhaseSendNMI ACPIシャットダウンがタイムアウトした後のNMI送信(停止待ちを含む)
+	PhaseSendNMI = Phase("send-nmi")
+	// PhaseForceShutdown 段階的なシャットダウンの最後に行う強制停止
+	PhaseForceShutdown = Phase("force-shutdown")
+	// PhaseSetup リソースの作成(リトライ時は削除&再作成)
+	PhaseSetup = Phase("setup")
+	// PhaseDelete 作成失敗時のリソース削除
+	PhaseDelete = Phase("delete")
+)
+
+// Resource イベントの対象リソース
+type Resource struct {
+	Kind string // リソース種別(Server/Disk/Archiveなど)
+	Zone string // グローバルリソースの場合は空
+	ID   types.ID
+}
+
+// Target イベントの対象リソースを返す
+func (r Resource) Target() Resource {
+	return r
+}
+
+// Event 各サービス/ビルダーから通知されるイベント
+//
+// 実際の型はPhaseStartedやAvailabilityChangedなどのいずれか
+type Event interface {
+	EventType() EventType
+	Target() Resource
+}
+
+// PhaseStarted 処理の開始イベント
+type PhaseStarted struct {
+	Resource
+	Phase Phase
+}
+
+// EventType イベントの種別を返す
+func (e *PhaseStarted) EventType() EventType {
+	return EventTypePhaseStarted
+}
+
+// PhaseFinished 処理の終了イベント
+type PhaseFinished struct {
+	Resource
+	Phase   Phase
+	Elapsed time.Duration
+	Err     error // 処理が失敗した場合のエラー
+}
+
+// EventType イベントの種別を返す
+func (e *PhaseFinished) EventType() EventType {
+	return EventTypePhaseFinished
+}
+
+// AvailabilityChanged リソースの状態の変化イベント
+type AvailabilityChanged struct {
+	Resource
+	Availability   types.EAvailability
+	InstanceStatus types.EServerInstanceStatus // InstanceStatusを持たないリソースの場合は空
+}
+
+// EventType イベントの種別を返す
+func (e *AvailabilityChanged) EventType() EventType {
+	return EventTypeAvailabilityChanged
+}
+
+// RetryAttempt リトライイベント
+type RetryAttempt struct {
+	Resource
+	Phase       Phase
+	Attempt     int           // 失敗した試行の回数(1始まり)
+	MaxAttempts int           // 最大試行回数
+	Wait        time.Duration // 次の試行までの待ち時間
+	Err         error         // 直前の試行でのエラー
+}
+
+// EventType イベントの種別を返す
+func (e *RetryAttempt) EventType() EventType {
+	return EventTypeRetryAttempt
+}
+
+// ResourceCreated リソースの作成イベント
+type ResourceCreated struct {
+	Resource
+}
+
+// EventType イベントの種別を返す
+func (e *ResourceCreated) EventType() EventType {
+	return EventTypeResourceCreated
+}
+
+// ResourceDeleted リソースの削除イベント
+type ResourceDeleted struct {
+	Resource
+}
+
+// EventType イベントの種別を返す
+func (e *ResourceDeleted) EventType() EventType {
+	return EventTypeResourceDeleted
+}
+
+// CopyProgress ディスク/アーカイブのコピーの進捗イベント
+type CopyProgress struct {
+	Resource
+	MigratedMB int
+	SizeMB     int
+	Percent    int
+}
+
+// EventType イベントの種別を返す
+func (e *CopyProgress) EventType() EventType {
+	return EventTypeCopyProgress
+}
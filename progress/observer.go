@@ -0,0 +1,148 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package progress
+
+import (
+	"context"
+	"time"
+
+	"github.com/sacloud/iaas-api-go/accessor"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/packages-go/wait"
+)
+
+// Observer イベントの受信者
+//
+// OnEventはAPI呼び出しを行っているgoroutineから同期的に呼ばれるため、時間のかかる処理は行わないこと
+type Observer interface {
+	OnEvent(ctx context.Context, event Event)
+}
+
+// ObserverFunc 関数をObserverとして利用するためのアダプタ
+type ObserverFunc func(ctx context.Context, event Event)
+
+// OnEvent イベントを受信する
+func (f ObserverFunc) OnEvent(ctx context.Context, event Event) {
+	f(ctx, event)
+}
+
+type observerKey struct{}
+
+type observers []Observer
+
+func (o observers) OnEvent(ctx context.Context, event Event) {
+	for _, observer := range o {
+		observer.OnEvent(ctx, event)
+	}
+}
+
+// WithObserver Observerを設定したcontextを返す
+//
+// 各サービスの*WithContext系メソッドにこのcontextを渡すとイベントが通知される。
+// ctxに既にObserverが設定されている場合はそれらにも引き続き通知される
+func WithObserver(ctx context.Context, observer ...Observer) context.Context {
+	var current observers
+	if o, ok := ctx.Value(observerKey{}).(observers); ok {
+		current = o
+	}
+	merged := append(append(observers{}, current...), observer...)
+	return context.WithValue(ctx, observerKey{}, merged)
+}
+
+// Enabled ctxにObserverが設定されているか
+func Enabled(ctx context.Context) bool {
+	o, ok := ctx.Value(observerKey{}).(observers)
+	return ok && len(o) > 0
+}
+
+// Emit ctxに設定されたObserverにイベントを通知する
+func Emit(ctx context.Context, event Event) {
+	if o, ok := ctx.Value(observerKey{}).(observers); ok {
+		o.OnEvent(ctx, event)
+	}
+}
+
+// StartPhase PhaseStartedイベントを通知し、処理終了時にPhaseFinishedイベントを通知するための関数を返す
+func StartPhase(ctx context.Context, resource Resource, phase Phase) func(err error) {
+	if !Enabled(ctx) {
+		return func(error) {}
+	}
+	started := time.Now()
+	Emit(ctx, &PhaseStarted{Resource: resource, Phase: phase})
+	return func(err error) {
+		Emit(ctx, &PhaseFinished{Resource: resource, Phase: phase, Elapsed: time.Since(started), Err: err})
+	}
+}
+
+type copyProgressAccessor interface {
+	GetMigratedMB() int
+	GetSizeMB() int
+}
+
+// ObserveState readFuncで読み込んだリソースの状態が変化した際にイベントを通知するようreadFuncをラップする
+//
+// iaas.StatePollingWaiterなどのReadFuncとして利用する。
+// Availability/InstanceStatusが変化した場合はAvailabilityChanged、コピーの進捗が変化した場合はCopyProgressを通知する
+func ObserveState(ctx context.Context, resource Resource, readFunc wait.StateReadFunc) wait.StateReadFunc {
+	if !Enabled(ctx) {
+		return readFunc
+	}
+
+	var availability types.EAvailability
+	var instanceStatus types.EServerInstanceStatus
+	percent := -1
+
+	return func() (interface{}, error) {
+		state, err := readFunc()
+		if err != nil || state == nil {
+			return state, err
+		}
+
+		var currentAvailability types.EAvailability
+		var currentInstanceStatus types.EServerInstanceStatus
+		if v, ok := state.(accessor.Availability); ok {
+			currentAvailability = v.GetAvailability()
+		}
+		if v, ok := state.(accessor.InstanceStatus); ok {
+			currentInstanceStatus = v.GetInstanceStatus()
+		}
+		if currentAvailability != availability || currentInstanceStatus != instanceStatus {
+			availability = currentAvailability
+			instanceStatus = currentInstanceStatus
+			Emit(ctx, &AvailabilityChanged{
+				Resource:       resource,
+				Availability:   availability,
+				InstanceStatus: instanceStatus,
+			})
+		}
+
+		if v, ok := state.(copyProgressAccessor); ok && v.GetSizeMB() > 0 {
+			current := v.GetMigratedMB() * 100 / v.GetSizeMB()
+			if current > 100 {
+				current = 100
+			}
+			if current != percent && (percent >= 0 || availability.IsMigrating()) {
+				percent = current
+				Emit(ctx, &CopyProgress{
+					Resource:   resource,
+					MigratedMB: v.GetMigratedMB(),
+					SizeMB:     v.GetSizeMB(),
+					Percent:    percent,
+				})
+			}
+		}
+		return state, nil
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package progress
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	events []Event
+}
+
+func (r *recorder) OnEvent(_ context.Context, event Event) {
+	r.events = append(r.events, event)
+}
+
+func TestWithObserver(t *testing.T) {
+	first := &recorder{}
+	second := &recorder{}
+
+	ctx := WithObserver(context.Background(), first)
+	ctx = WithObserver(ctx, second)
+
+	resource := Resource{Kind: "Server", Zone: "is1a", ID: 1}
+	Emit(ctx, &ResourceCreated{Resource: resource})
+
+	require.Len(t, first.events, 1)
+	require.Len(t, second.events, 1)
+	require.Equal(t, EventTypeResourceCreated, first.events[0].EventType())
+	require.Equal(t, resource, first.events[0].Target())
+
+	// Observerが未設定の場合は何もしない
+	Emit(context.Background(), &ResourceCreated{Resource: resource})
+	require.False(t, Enabled(context.Background()))
+}
+
+func TestStartPhase(t *testing.T) {
+	r := &recorder{}
+	ctx := WithObserver(context.Background(), r)
+	resource := Resource{Kind: "Disk", Zone: "is1a", ID: 1}
+
+	done := StartPhase(ctx, resource, PhaseWaitForCopy)
+	done(errors.New("dummy"))
+
+	require.Len(t, r.events, 2)
+	require.Equal(t, &PhaseStarted{Resource: resource, Phase: PhaseWaitForCopy}, r.events[0])
+	finished := r.events[1].(*PhaseFinished)
+	require.Equal(t, PhaseWaitForCopy, finished.Phase)
+	require.EqualError(t, finished.Err, "dummy")
+}
+
+func TestObserveState(t *testing.T) {
+	r := &recorder{}
+	ctx := WithObserver(context.Background(), r)
+	resource := Resource{Kind: "Disk", Zone: "is1a", ID: 1}
+
+	states := []*iaas.Disk{
+		{Availability: types.Availabilities.Migrating, SizeMB: 100, MigratedMB: 0},
+		{Availability: types.Availabilities.Migrating, SizeMB: 100, MigratedMB: 0},
+		{Availability: types.Availabilities.Migrating, SizeMB: 100, MigratedMB: 50},
+		{Availability: types.Availabilities.Available, SizeMB: 100, MigratedMB: 100},
+	}
+	read := ObserveState(ctx, resource, func() (interface{}, error) {
+		state := states[0]
+		states = states[1:]
+		return state, nil
+	})
+	for i := 0; i < 4; i++ {
+		_, err := read()
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []Event{
+		&AvailabilityChanged{Resource: resource, Availability: types.Availabilities.Migrating},
+		&CopyProgress{Resource: resource, MigratedMB: 0, SizeMB: 100, Percent: 0},
+		&CopyProgress{Resource: resource, MigratedMB: 50, SizeMB: 100, Percent: 50},
+		&AvailabilityChanged{Resource: resource, Availability: types.Availabilities.Available},
+		&CopyProgress{Resource: resource, MigratedMB: 100, SizeMB: 100, Percent: 100},
+	}, r.events)
+}
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) DeleteCertificates(req *DeleteCertificatesRequest) error {
 	return s.DeleteCertificatesWithContext(context.Background(), req)
 }
 
-func (s *Service) DeleteCertificatesWithContext(ctx context.Context, req *DeleteCertificatesRequest) error {
+func (s *Service) DeleteCertificatesWithContext(ctx context.Context, req *DeleteCertificatesRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DeleteCertificates", Resource: "ProxyLB", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "DeleteCertificates", Resource: "ProxyLB", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewProxyLBOp(s.caller)
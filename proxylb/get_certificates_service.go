@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) GetCertificates(req *GetCertificatesRequest) (*iaas.ProxyLBCertificates, error) {
 	return s.GetCertificatesWithContext(context.Background(), req)
 }
 
-func (s *Service) GetCertificatesWithContext(ctx context.Context, req *GetCertificatesRequest) (*iaas.ProxyLBCertificates, error) {
+func (s *Service) GetCertificatesWithContext(ctx context.Context, req *GetCertificatesRequest) (_ *iaas.ProxyLBCertificates, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "GetCertificates", Resource: "ProxyLB", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "GetCertificates", Resource: "ProxyLB", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewProxyLBOp(s.caller)
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) HealthStatus(req *HealthStatusRequest) (*iaas.ProxyLBHealth, error) {
 	return s.HealthStatusWithContext(context.Background(), req)
 }
 
-func (s *Service) HealthStatusWithContext(ctx context.Context, req *HealthStatusRequest) (*iaas.ProxyLBHealth, error) {
+func (s *Service) HealthStatusWithContext(ctx context.Context, req *HealthStatusRequest) (_ *iaas.ProxyLBHealth, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "HealthStatus", Resource: "ProxyLB", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "HealthStatus", Resource: "ProxyLB", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewProxyLBOp(s.caller)
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorConnection(req *MonitorConnectionRequest) ([]*iaas.Moni
 	return s.MonitorConnectionWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorConnectionWithContext(ctx context.Context, req *MonitorConnectionRequest) ([]*iaas.MonitorConnectionValue, error) {
+func (s *Service) MonitorConnectionWithContext(ctx context.Context, req *MonitorConnectionRequest) (_ []*iaas.MonitorConnectionValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorConnection", Resource: "ProxyLB", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorConnection", Resource: "ProxyLB", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewProxyLBOp(s.caller)
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) RenewLetsEncryptCert(req *RenewLetsEncryptCertRequest) error {
 	return s.RenewLetsEncryptCertWithContext(context.Background(), req)
 }
 
-func (s *Service) RenewLetsEncryptCertWithContext(ctx context.Context, req *RenewLetsEncryptCertRequest) error {
+func (s *Service) RenewLetsEncryptCertWithContext(ctx context.Context, req *RenewLetsEncryptCertRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "RenewLetsEncryptCert", Resource: "ProxyLB", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "RenewLetsEncryptCert", Resource: "ProxyLB", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewProxyLBOp(s.caller)
@@ -18,19 +18,23 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) SetCertificates(req *SetCertificatesRequest) error {
 	return s.SetCertificatesWithContext(context.Background(), req)
 }
 
-func (s *Service) SetCertificatesWithContext(ctx context.Context, req *SetCertificatesRequest) error {
+func (s *Service) SetCertificatesWithContext(ctx context.Context, req *SetCertificatesRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "SetCertificates", Resource: "ProxyLB", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "SetCertificates", Resource: "ProxyLB", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewProxyLBOp(s.caller)
-	_, err := client.SetCertificates(ctx, req.ID, &iaas.ProxyLBSetCertificatesRequest{
+	_, err = client.SetCertificates(ctx, req.ID, &iaas.ProxyLBSetCertificatesRequest{
 		PrimaryCerts:    req.PrimaryCerts,
 		AdditionalCerts: req.AdditionalCerts,
 	})
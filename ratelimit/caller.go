@@ -0,0 +1,113 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-service-go/serviceutil"
+	"github.com/sacloud/packages-go/validate"
+)
+
+// Options Callerの制限値
+type Options struct {
+	// Limit 全てのリクエストに対する制限
+	Limit
+	// Zones ゾーンごとの制限、Limitと合わせて適用される
+	Zones map[string]Limit `validate:"dive"`
+}
+
+func (o *Options) Validate() error {
+	return validate.New().Struct(o)
+}
+
+// Caller リクエストの頻度と同時実行数を制限するiaas.APICaller
+//
+// 全てのサービスはAPI呼び出しにiaas.APICallerを用いるため、
+// サービスやビルダー、setup.RetryableSetupなどのポーリング処理を含め全ての呼び出しに制限が適用される。
+// 同じCallerを複数のサービスで共有すると、それらのサービス全体での制限となる。
+type Caller struct {
+	caller iaas.APICaller
+	global *limiter
+	zones  map[string]*limiter
+}
+
+// NewCaller callerをラップし、optsに従って制限するCallerを返す
+func NewCaller(caller iaas.APICaller, opts *Options) (*Caller, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+
+	c := &Caller{
+		caller: caller,
+		global: newLimiter(opts.Limit),
+		zones:  map[string]*limiter{},
+	}
+	for zone, l := range opts.Zones {
+		c.zones[zone] = newLimiter(l)
+	}
+	return c, nil
+}
+
+// Do iaas.APICallerの実装
+//
+// 制限を超える場合はリクエストの開始を待ち合わせる。待ち合わせ中にctxがキャンセルされた場合はctx.Err()を返す。
+func (c *Caller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	// 他のゾーンのリクエストを妨げないよう、ゾーンごとの制限を先に待ち合わせる
+	limiters := []*limiter{c.global}
+	if zl, ok := c.zones[zoneFromURL(uri)]; ok {
+		limiters = []*limiter{zl, c.global}
+	}
+
+	for _, l := range limiters {
+		if err := l.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	var releases []func()
+	defer func() {
+		for _, release := range releases {
+			release()
+		}
+	}()
+	for _, l := range limiters {
+		release, err := l.acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+
+	return c.caller.Do(ctx, method, uri, body)
+}
+
+// Unwrap ラップしているiaas.APICallerを返す
+func (c *Caller) Unwrap() iaas.APICaller {
+	return c.caller
+}
+
+// zoneFromURL APIのURLからゾーン名を取り出す
+//
+// 例: https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/server
+func zoneFromURL(uri string) string {
+	if u, ok := serviceutil.ParseAPIURL(uri); ok {
+		return u.Zone
+	}
+	return ""
+}
@@ -0,0 +1,121 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type dummyCaller struct {
+	mu       sync.Mutex
+	delay    time.Duration
+	inFlight map[string]int
+	max      map[string]int
+}
+
+func newDummyCaller(delay time.Duration) *dummyCaller {
+	return &dummyCaller{delay: delay, inFlight: map[string]int{}, max: map[string]int{}}
+}
+
+func (c *dummyCaller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	zone := zoneFromURL(uri)
+	c.mu.Lock()
+	c.inFlight[zone]++
+	c.inFlight[""]++
+	for _, key := range []string{zone, ""} {
+		if c.inFlight[key] > c.max[key] {
+			c.max[key] = c.inFlight[key]
+		}
+	}
+	c.mu.Unlock()
+
+	time.Sleep(c.delay)
+
+	c.mu.Lock()
+	c.inFlight[zone]--
+	c.inFlight[""]--
+	c.mu.Unlock()
+	return []byte(`{}`), nil
+}
+
+func apiURL(zone string) string {
+	if zone == "" {
+		return "https://secure.sakura.ad.jp/cloud/api/cloud/1.1/region"
+	}
+	return "https://secure.sakura.ad.jp/cloud/zone/" + zone + "/api/cloud/1.1/server"
+}
+
+func callConcurrently(t *testing.T, caller *Caller, urls ...string) {
+	var wg sync.WaitGroup
+	for _, u := range urls {
+		wg.Add(1)
+		go func(u string) {
+			defer wg.Done()
+			_, err := caller.Do(context.Background(), "GET", u, nil)
+			require.NoError(t, err)
+		}(u)
+	}
+	wg.Wait()
+}
+
+func TestCaller_Rate(t *testing.T) {
+	caller, err := NewCaller(newDummyCaller(0), &Options{Limit: Limit{Rate: 20, Burst: 1}})
+	require.NoError(t, err)
+
+	started := time.Now()
+	callConcurrently(t, caller, apiURL("is1a"), apiURL("is1a"), apiURL("tk1a"), apiURL(""), apiURL(""))
+	// 1件目は即時、残りの4件は50msごとに開始される
+	require.GreaterOrEqual(t, time.Since(started), 190*time.Millisecond)
+}
+
+func TestCaller_MaxInFlight(t *testing.T) {
+	dummy := newDummyCaller(20 * time.Millisecond)
+	caller, err := NewCaller(dummy, &Options{
+		Limit: Limit{MaxInFlight: 3},
+		Zones: map[string]Limit{"is1a": {MaxInFlight: 1}},
+	})
+	require.NoError(t, err)
+
+	callConcurrently(t, caller,
+		apiURL("is1a"), apiURL("is1a"), apiURL("is1a"),
+		apiURL("tk1a"), apiURL("tk1a"), apiURL("tk1a"),
+	)
+	require.Equal(t, 1, dummy.max["is1a"])
+	require.LessOrEqual(t, dummy.max[""], 3)
+	require.Equal(t, 2, dummy.max["tk1a"])
+}
+
+func TestCaller_Cancel(t *testing.T) {
+	caller, err := NewCaller(newDummyCaller(0), &Options{Limit: Limit{Rate: 0.1}})
+	require.NoError(t, err)
+
+	_, err = caller.Do(context.Background(), "GET", apiURL("is1a"), nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = caller.Do(ctx, "GET", apiURL("is1a"), nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestNewCaller_Validate(t *testing.T) {
+	_, err := NewCaller(newDummyCaller(0), &Options{Limit: Limit{Rate: -1}})
+	require.Error(t, err)
+}
@@ -0,0 +1,116 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit リクエストの制限値
+type Limit struct {
+	// Rate 1秒あたりに開始できるリクエスト数、0の場合は無制限
+	Rate float64 `validate:"min=0"`
+	// Burst 連続して開始できるリクエスト数の上限、省略時はRateを切り上げた値(最低1)
+	Burst int `validate:"min=0"`
+	// MaxInFlight 同時に実行できるリクエスト数、0の場合は無制限
+	MaxInFlight int `validate:"min=0"`
+}
+
+// limiter Limitに基づきリクエストの開始を待ち合わせる
+type limiter struct {
+	bucket *tokenBucket
+	slots  chan struct{}
+}
+
+func newLimiter(l Limit) *limiter {
+	lim := &limiter{}
+	if l.Rate > 0 {
+		burst := l.Burst
+		if burst <= 0 {
+			burst = int(math.Max(1, math.Ceil(l.Rate)))
+		}
+		lim.bucket = newTokenBucket(l.Rate, burst)
+	}
+	if l.MaxInFlight > 0 {
+		lim.slots = make(chan struct{}, l.MaxInFlight)
+	}
+	return lim
+}
+
+// wait Rateを超えないようリクエストの開始を待つ
+func (l *limiter) wait(ctx context.Context) error {
+	if l.bucket == nil {
+		return nil
+	}
+	return l.bucket.wait(ctx)
+}
+
+// acquire MaxInFlightを超えないよう実行枠を確保する、戻り値のfuncはリクエスト完了時に呼ぶ必要がある
+func (l *limiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return func() { <-l.slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // 1秒あたりに補充されるトークン数
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait トークンを1つ消費する、トークンがない場合は補充されるかctxがキャンセルされるまで待つ
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
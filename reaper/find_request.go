@@ -0,0 +1,38 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"time"
+
+	"github.com/sacloud/packages-go/validate"
+)
+
+type FindRequest struct {
+	Policy
+
+	// Zones 対象のゾーン、省略時は全ゾーン
+	Zones []string `validate:"omitempty,dive,required"`
+	// Kinds 対象のリソース種別、省略時はDefaultKinds
+	Kinds []string `validate:"omitempty,dive,required"`
+	// Now 有効期限の判定に用いる日時、省略時は現在時刻
+	Now time.Time
+
+	Parallelism int `validate:"min=0"` // 同時に検索するゾーン数、省略時はmultizone.DefaultParallelism
+}
+
+func (req *FindRequest) Validate() error {
+	return validate.New().Struct(req)
+}
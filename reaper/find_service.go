@@ -0,0 +1,88 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/bulk"
+)
+
+// Find 有効期限が切れたリソースを返す
+//
+// Expiredは有効期限が古い順、Invalidはゾーン/IDの順に並ぶ
+func (s *Service) Find(req *FindRequest) (*Report, error) {
+	return s.FindWithContext(context.Background(), req)
+}
+
+func (s *Service) FindWithContext(ctx context.Context, req *FindRequest) (_ *Report, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Find", Resource: "Reaper", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Find", Resource: "Reaper", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	kinds := req.Kinds
+	if len(kinds) == 0 {
+		kinds = DefaultKinds
+	}
+	targets, err := bulk.New(s.caller).FindWithContext(ctx, &bulk.FindRequest{
+		Selector:    bulk.Selector{Kinds: kinds, Zones: req.Zones},
+		Parallelism: req.Parallelism,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := req.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	report := &Report{}
+	for _, t := range targets {
+		expiresAt, tag, ok, err := req.Policy.ExpiresAt(t.Tags, t.CreatedAt)
+		if err != nil {
+			report.Invalid = append(report.Invalid, &Invalid{Target: t, Tag: tag, Err: err})
+			continue
+		}
+		if ok && !expiresAt.After(now) {
+			report.Expired = append(report.Expired, &Expired{Target: t, ExpiresAt: expiresAt, Tag: tag})
+		}
+	}
+
+	sort.SliceStable(report.Expired, func(i, j int) bool {
+		a, b := report.Expired[i], report.Expired[j]
+		if !a.ExpiresAt.Equal(b.ExpiresAt) {
+			return a.ExpiresAt.Before(b.ExpiresAt)
+		}
+		return targetLess(a.Target, b.Target)
+	})
+	sort.SliceStable(report.Invalid, func(i, j int) bool {
+		return targetLess(report.Invalid[i].Target, report.Invalid[j].Target)
+	})
+	return report, nil
+}
+
+func targetLess(a, b *bulk.Target) bool {
+	if a.Zone != b.Zone {
+		return a.Zone < b.Zone
+	}
+	return a.ID < b.ID
+}
@@ -0,0 +1,123 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+const (
+	// DefaultExpireTagKey 有効期限を日時で示すタグのキー(例: @expire=2026-10-20T00:00Z)
+	DefaultExpireTagKey = "@expire"
+	// DefaultTTLTagKey 作成日時からの有効期間を示すタグのキー(例: ttl=4h)
+	DefaultTTLTagKey = "ttl"
+)
+
+var expireLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// Policy 有効期限を示すタグの解釈方法
+type Policy struct {
+	ExpireTagKey string // 省略時はDefaultExpireTagKey
+	TTLTagKey    string // 省略時はDefaultTTLTagKey
+}
+
+func (p *Policy) expireTagKey() string {
+	if p.ExpireTagKey == "" {
+		return DefaultExpireTagKey
+	}
+	return p.ExpireTagKey
+}
+
+func (p *Policy) ttlTagKey() string {
+	if p.TTLTagKey == "" {
+		return DefaultTTLTagKey
+	}
+	return p.TTLTagKey
+}
+
+// ExpiresAt タグから有効期限を算出する
+//
+// TTLはcreatedAtを起点に算出する。複数のタグが指定されている場合は最も早い有効期限を返す。
+// 有効期限を示すタグを持たない場合はfalseを返す。値を解釈できないタグがある場合はエラーを返す。
+func (p *Policy) ExpiresAt(tags types.Tags, createdAt time.Time) (time.Time, string, bool, error) {
+	var expiresAt time.Time
+	var found string
+	for _, tag := range tags {
+		key, value, ok := strings.Cut(tag, "=")
+		if !ok {
+			continue
+		}
+
+		var t time.Time
+		switch key {
+		case p.expireTagKey():
+			v, err := parseExpire(value)
+			if err != nil {
+				return time.Time{}, tag, false, err
+			}
+			t = v
+		case p.ttlTagKey():
+			d, err := parseTTL(value)
+			if err != nil {
+				return time.Time{}, tag, false, err
+			}
+			t = createdAt.Add(d)
+		default:
+			continue
+		}
+
+		if found == "" || t.Before(expiresAt) {
+			expiresAt = t
+			found = tag
+		}
+	}
+	return expiresAt, found, found != "", nil
+}
+
+func parseExpire(value string) (time.Time, error) {
+	for _, layout := range expireLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid expiry time: %q", value)
+}
+
+// parseTTL time.ParseDurationの形式に加え、日数を"7d"のように指定できる
+func parseTTL(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid ttl: %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid ttl: %q", value)
+	}
+	return d, nil
+}
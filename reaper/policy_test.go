@@ -0,0 +1,112 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolicy_ExpiresAt(t *testing.T) {
+	createdAt := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		msg       string
+		policy    Policy
+		tags      types.Tags
+		expiresAt time.Time
+		tag       string
+		ok        bool
+		err       bool
+	}{
+		{
+			msg:  "no expiry tags",
+			tags: types.Tags{"env=staging", "@expire"},
+		},
+		{
+			msg:       "expire without seconds",
+			tags:      types.Tags{"@expire=2026-10-20T00:00Z"},
+			expiresAt: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			tag:       "@expire=2026-10-20T00:00Z",
+			ok:        true,
+		},
+		{
+			msg:       "expire with offset",
+			tags:      types.Tags{"@expire=2026-10-20T09:00:00+09:00"},
+			expiresAt: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			tag:       "@expire=2026-10-20T09:00:00+09:00",
+			ok:        true,
+		},
+		{
+			msg:       "expire date only",
+			tags:      types.Tags{"@expire=2026-10-20"},
+			expiresAt: time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC),
+			tag:       "@expire=2026-10-20",
+			ok:        true,
+		},
+		{
+			msg:       "ttl",
+			tags:      types.Tags{"ttl=4h"},
+			expiresAt: createdAt.Add(4 * time.Hour),
+			tag:       "ttl=4h",
+			ok:        true,
+		},
+		{
+			msg:       "ttl in days",
+			tags:      types.Tags{"ttl=7d"},
+			expiresAt: createdAt.Add(7 * 24 * time.Hour),
+			tag:       "ttl=7d",
+			ok:        true,
+		},
+		{
+			msg:       "earliest wins",
+			tags:      types.Tags{"@expire=2026-10-20T00:00Z", "ttl=1h"},
+			expiresAt: createdAt.Add(time.Hour),
+			tag:       "ttl=1h",
+			ok:        true,
+		},
+		{
+			msg:       "custom keys",
+			policy:    Policy{ExpireTagKey: "expire-at", TTLTagKey: "lifetime"},
+			tags:      types.Tags{"ttl=1h", "lifetime=2h"},
+			expiresAt: createdAt.Add(2 * time.Hour),
+			tag:       "lifetime=2h",
+			ok:        true,
+		},
+		{
+			msg:  "invalid expire",
+			tags: types.Tags{"@expire=tomorrow"},
+			tag:  "@expire=tomorrow",
+			err:  true,
+		},
+		{
+			msg:  "invalid ttl",
+			tags: types.Tags{"ttl=-1h"},
+			tag:  "ttl=-1h",
+			err:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		expiresAt, tag, ok, err := tc.policy.ExpiresAt(tc.tags, createdAt)
+		require.Equal(t, tc.err, err != nil, tc.msg)
+		require.Equal(t, tc.ok, ok, tc.msg)
+		require.Equal(t, tc.tag, tag, tc.msg)
+		require.True(t, tc.expiresAt.Equal(expiresAt), tc.msg)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"context"
+
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/bulk"
+)
+
+// ReapResult 有効期限切れリソースの削除結果
+type ReapResult struct {
+	*Report
+	Results []*bulk.Result // リソースごとの削除結果
+}
+
+// Reap 有効期限が切れたリソースを削除する
+//
+// サーバ、ディスク/アーカイブ、スイッチの順に削除する。
+// 検索に失敗したゾーンがある場合は削除を行わずエラーを返す。
+func (s *Service) Reap(req *ReapRequest) (*ReapResult, error) {
+	return s.ReapWithContext(context.Background(), req)
+}
+
+func (s *Service) ReapWithContext(ctx context.Context, req *ReapRequest) (_ *ReapResult, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Reap", Resource: "Reaper", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Reap", Resource: "Reaper", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	report, err := s.FindWithContext(ctx, &req.FindRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReapResult{Report: report}
+	if len(report.Expired) == 0 {
+		return result, nil
+	}
+
+	var targets []*bulk.Target
+	for _, e := range report.Expired {
+		targets = append(targets, e.Target)
+	}
+	result.Results, err = bulk.New(s.caller).DeleteWithContext(ctx, &bulk.DeleteRequest{
+		Selector:    bulk.Selector{Targets: targets},
+		Force:       req.Force,
+		WithDisks:   req.WithDisks,
+		Parallelism: req.Parallelism,
+	})
+	return result, err
+}
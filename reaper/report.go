@@ -0,0 +1,44 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"time"
+
+	"github.com/sacloud/iaas-service-go/bulk"
+)
+
+// DefaultKinds Kinds省略時に対象となるリソース種別
+var DefaultKinds = []string{bulk.KindServer, bulk.KindDisk, bulk.KindArchive, bulk.KindSwitch}
+
+// Expired 有効期限が切れたリソース
+type Expired struct {
+	Target    *bulk.Target
+	ExpiresAt time.Time
+	Tag       string // 有効期限の算出に用いたタグ
+}
+
+// Invalid 有効期限を示すタグの値を解釈できなかったリソース
+type Invalid struct {
+	Target *bulk.Target
+	Tag    string
+	Err    error
+}
+
+// Report 有効期限の確認結果
+type Report struct {
+	Expired []*Expired
+	Invalid []*Invalid
+}
@@ -0,0 +1,84 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package reaper
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Reap(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("reaper")
+	zone := testutil.TestZone()
+	now := time.Now()
+
+	switchOp := iaas.NewSwitchOp(caller)
+	create := func(tags ...string) *iaas.Switch {
+		sw, err := switchOp.Create(ctx, zone, &iaas.SwitchCreateRequest{Name: name, Tags: tags})
+		require.NoError(t, err)
+		return sw
+	}
+
+	expired := create("@expire=" + now.Add(-time.Minute).UTC().Format(time.RFC3339))
+	ttlExpired := create("ttl=1h")
+	alive := create("@expire=" + now.Add(24*time.Hour).UTC().Format(time.RFC3339))
+	invalid := create("ttl=forever")
+	untagged := create()
+	for _, sw := range []*iaas.Switch{alive, invalid, untagged} {
+		defer switchOp.Delete(ctx, zone, sw.ID) //nolint:errcheck
+	}
+
+	svc := New(caller)
+	req := FindRequest{Zones: []string{zone}, Now: now.Add(2 * time.Hour)}
+
+	report, err := svc.Find(&req)
+	require.NoError(t, err)
+	require.Len(t, report.Expired, 2)
+	require.Equal(t, expired.ID, report.Expired[0].Target.ID)
+	require.Equal(t, ttlExpired.ID, report.Expired[1].Target.ID)
+	require.Equal(t, "ttl=1h", report.Expired[1].Tag)
+	require.Len(t, report.Invalid, 1)
+	require.Equal(t, invalid.ID, report.Invalid[0].Target.ID)
+
+	_, err = svc.Reap(&ReapRequest{FindRequest: FindRequest{Zones: []string{zone}, Parallelism: -1}})
+	require.True(t, service.IsValidationError(err))
+
+	result, err := svc.Reap(&ReapRequest{FindRequest: req})
+	require.NoError(t, err)
+	require.Len(t, result.Results, 2)
+
+	for _, sw := range []*iaas.Switch{expired, ttlExpired} {
+		_, err := switchOp.Read(ctx, zone, sw.ID)
+		require.True(t, iaas.IsNotFoundError(err))
+	}
+	for _, sw := range []*iaas.Switch{alive, invalid, untagged} {
+		_, err := switchOp.Read(ctx, zone, sw.ID)
+		require.NoError(t, err)
+	}
+
+	// 削除済みのため対象なし
+	result, err = svc.Reap(&ReapRequest{FindRequest: req})
+	require.NoError(t, err)
+	require.Empty(t, result.Expired)
+	require.Empty(t, result.Results)
+}
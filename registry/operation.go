@@ -0,0 +1,123 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Operation リソースに対する操作のメタデータ
+type Operation struct {
+	Name        string       // 操作名(例: Create)
+	RequestType reflect.Type // リクエストの型(構造体)、リクエストを受け取らない場合はnil
+	ResultType  reflect.Type // 戻り値の型、error以外の戻り値を持たない場合はnil
+	Fields      []*Field     // リクエストのフィールド
+
+	method string
+}
+
+// Field リクエストのフィールドのメタデータ
+type Field struct {
+	Name     string
+	Type     reflect.Type
+	Required bool   // validateタグでrequiredが指定されているか
+	Validate string // validateタグの値
+	Service  string // serviceタグの値
+}
+
+// newOperation XxxWithContext(ctx, *XxxRequest)の形式のメソッドから操作のメタデータを組み立てる
+//
+// 形式が異なるメソッドの場合はnilを返す。
+func newOperation(m reflect.Method) *Operation {
+	name := strings.TrimSuffix(m.Name, "WithContext")
+	if name == m.Name {
+		return nil
+	}
+
+	t := m.Type // 0番目はレシーバ
+	if t.NumIn() < 2 || t.NumIn() > 3 || t.In(1) != contextType {
+		return nil
+	}
+	op := &Operation{Name: name, method: m.Name}
+	if t.NumIn() == 3 {
+		in := t.In(2)
+		if in.Kind() != reflect.Ptr || in.Elem().Kind() != reflect.Struct {
+			return nil
+		}
+		op.RequestType = in.Elem()
+		op.Fields = fields(op.RequestType)
+	}
+
+	switch t.NumOut() {
+	case 1:
+		if t.Out(0) != errorType {
+			return nil
+		}
+	case 2:
+		if t.Out(1) != errorType {
+			return nil
+		}
+		op.ResultType = t.Out(0)
+	default:
+		return nil
+	}
+	return op
+}
+
+func fields(t reflect.Type) []*Field {
+	var results []*Field
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		validate := f.Tag.Get("validate")
+		results = append(results, &Field{
+			Name:     f.Name,
+			Type:     f.Type,
+			Required: hasRule(validate, "required"),
+			Validate: validate,
+			Service:  f.Tag.Get("service"),
+		})
+	}
+	return results
+}
+
+func hasRule(tag, rule string) bool {
+	for _, v := range strings.Split(tag, ",") {
+		if v == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *Operation) field(name string) *Field {
+	for _, f := range o.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// NewRequest 空のリクエストを返す、リクエストを受け取らない操作の場合はnilを返す
+func (o *Operation) NewRequest() interface{} {
+	if o.RequestType == nil {
+		return nil
+	}
+	return reflect.New(o.RequestType).Interface()
+}
@@ -0,0 +1,123 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sacloud/iaas-api-go"
+)
+
+// 各リソースに共通するCRUD+L操作の名前
+const (
+	OperationCreate = "Create"
+	OperationRead   = "Read"
+	OperationUpdate = "Update"
+	OperationDelete = "Delete"
+	OperationFind   = "Find"
+	OperationList   = "List"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// Resource リソース種別ごとのメタデータ
+type Resource struct {
+	Kind    string // リソース種別(例: Server)
+	Package string // サービスを提供するパッケージ名(例: server)
+	Zoned   bool   // 操作時にゾーンの指定が必要か
+
+	newService func(caller iaas.APICaller) interface{}
+	operations []*Operation
+}
+
+func newResource(kind, pkg string, newService func(caller iaas.APICaller) interface{}) *Resource {
+	r := &Resource{
+		Kind:       kind,
+		Package:    pkg,
+		newService: newService,
+	}
+
+	t := reflect.TypeOf(newService(nil))
+	for i := 0; i < t.NumMethod(); i++ {
+		op := newOperation(t.Method(i))
+		if op == nil {
+			continue
+		}
+		r.operations = append(r.operations, op)
+		if op.field("Zone") != nil {
+			r.Zoned = true
+		}
+	}
+	return r
+}
+
+// Resources 登録されている全てのリソースをKindの昇順で返す
+func Resources() []*Resource {
+	results := make([]*Resource, len(resources))
+	copy(results, resources)
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Kind < results[j].Kind
+	})
+	return results
+}
+
+// Lookup Kindまたはパッケージ名からリソースを返す
+//
+// 大文字小文字は区別しない。
+func Lookup(name string) (*Resource, bool) {
+	for _, r := range resources {
+		if strings.EqualFold(r.Kind, name) || strings.EqualFold(r.Package, name) {
+			return r, true
+		}
+	}
+	return nil, false
+}
+
+// Operations サポートしている操作を名前の昇順で返す
+func (r *Resource) Operations() []*Operation {
+	results := make([]*Operation, len(r.operations))
+	copy(results, r.operations)
+	return results
+}
+
+// Operation 名前に対応する操作を返す
+func (r *Resource) Operation(name string) (*Operation, bool) {
+	for _, op := range r.operations {
+		if op.Name == name {
+			return op, true
+		}
+	}
+	return nil, false
+}
+
+// Supports 指定の操作をサポートしているか
+func (r *Resource) Supports(name string) bool {
+	_, ok := r.Operation(name)
+	return ok
+}
+
+// NewService 統一的なインターフェースで操作を行うためのサービスを返す
+func (r *Resource) NewService(caller iaas.APICaller) *Service {
+	return &Service{
+		resource: r,
+		service:  reflect.ValueOf(r.newService(caller)),
+	}
+}
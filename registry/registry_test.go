@@ -0,0 +1,175 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/dns"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResources(t *testing.T) {
+	// リソースを扱うパッケージが全て登録されているか
+	entries, err := os.ReadDir("..")
+	require.NoError(t, err)
+
+	for _, e := range entries {
+		if !e.IsDir() || !isResourcePackage(t, filepath.Join("..", e.Name())) {
+			continue
+		}
+		_, ok := Lookup(e.Name())
+		require.True(t, ok, "package %q is not registered", e.Name())
+	}
+
+	for _, r := range Resources() {
+		require.NotEmpty(t, r.Operations(), r.Kind)
+	}
+}
+
+// isResourcePackage Serviceがiaas-api-goの型を返す操作を持つパッケージか
+//
+// bulkやstackなど複数のリソースを組み合わせるパッケージは独自の型を返すため対象外となる
+func isResourcePackage(t *testing.T, dir string) bool {
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi fs.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	require.NoError(t, err)
+
+	for _, pkg := range pkgs {
+		for _, f := range pkg.Files {
+			for _, decl := range f.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || !isServiceOperation(fn) {
+					continue
+				}
+				if returnsIaaSType(fn.Type.Results.List[0].Type) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func isServiceOperation(fn *ast.FuncDecl) bool {
+	if fn.Recv == nil || len(fn.Recv.List) != 1 || !strings.HasSuffix(fn.Name.Name, "WithContext") {
+		return false
+	}
+	star, ok := fn.Recv.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	if ident, ok := star.X.(*ast.Ident); !ok || ident.Name != "Service" {
+		return false
+	}
+	return fn.Type.Results != nil && len(fn.Type.Results.List) > 1
+}
+
+func returnsIaaSType(expr ast.Expr) bool {
+	for {
+		switch e := expr.(type) {
+		case *ast.StarExpr:
+			expr = e.X
+		case *ast.ArrayType:
+			expr = e.Elt
+		case *ast.SelectorExpr:
+			ident, ok := e.X.(*ast.Ident)
+			return ok && ident.Name == "iaas"
+		default:
+			return false
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	server, ok := Lookup("Server")
+	require.True(t, ok)
+	require.True(t, server.Zoned)
+	for _, op := range []string{OperationCreate, OperationRead, OperationUpdate, OperationDelete, OperationFind, "Boot", "Apply"} {
+		require.True(t, server.Supports(op), op)
+	}
+	require.False(t, server.Supports("FindAll")) // コールバックを受け取る操作は対象外
+
+	sw, ok := Lookup("swytch")
+	require.True(t, ok)
+	require.Equal(t, "Switch", sw.Kind)
+
+	d, ok := Lookup("dns")
+	require.True(t, ok)
+	require.False(t, d.Zoned)
+
+	op, ok := d.Operation(OperationCreate)
+	require.True(t, ok)
+	require.IsType(t, &dns.CreateRequest{}, op.NewRequest())
+	require.Equal(t, "Name", op.Fields[0].Name)
+	require.True(t, op.Fields[0].Required)
+	require.Equal(t, "*iaas.DNS", op.ResultType.String())
+
+	_, ok = Lookup("unknown")
+	require.False(t, ok)
+}
+
+func TestService_CRUDL(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("registry") + ".example.com"
+
+	r, ok := Lookup("DNS")
+	require.True(t, ok)
+	svc := r.NewService(caller)
+
+	op, _ := r.Operation(OperationCreate)
+	req := op.NewRequest().(*dns.CreateRequest)
+	req.Name = name
+	created, err := svc.Create(ctx, req)
+	require.NoError(t, err)
+	id := created.(*iaas.DNS).ID
+
+	read, err := svc.Read(ctx, &dns.ReadRequest{ID: id})
+	require.NoError(t, err)
+	require.Equal(t, name, read.(*iaas.DNS).Name)
+
+	desc := "updated"
+	updated, err := svc.Update(ctx, &dns.UpdateRequest{ID: id, Description: &desc})
+	require.NoError(t, err)
+	require.Equal(t, desc, updated.(*iaas.DNS).Description)
+
+	list, err := svc.List(ctx, &dns.FindRequest{Names: []string{name}})
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+
+	require.NoError(t, svc.Delete(ctx, &dns.DeleteRequest{ID: id}))
+	_, err = svc.Read(ctx, &dns.ReadRequest{ID: id})
+	require.True(t, service.IsNotFoundError(err))
+
+	// 不正なリクエスト
+	_, err = svc.Read(ctx, &dns.FindRequest{})
+	require.True(t, service.IsValidationError(err))
+	_, err = svc.Do(ctx, "Boot", &dns.ReadRequest{ID: id})
+	require.True(t, errors.Is(err, ErrUnsupportedOperation))
+}
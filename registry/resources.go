@@ -0,0 +1,115 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-service-go/archive"
+	"github.com/sacloud/iaas-service-go/authstatus"
+	"github.com/sacloud/iaas-service-go/autobackup"
+	"github.com/sacloud/iaas-service-go/autoscale"
+	"github.com/sacloud/iaas-service-go/bill"
+	"github.com/sacloud/iaas-service-go/bridge"
+	"github.com/sacloud/iaas-service-go/cdrom"
+	"github.com/sacloud/iaas-service-go/certificateauthority"
+	"github.com/sacloud/iaas-service-go/containerregistry"
+	"github.com/sacloud/iaas-service-go/coupon"
+	"github.com/sacloud/iaas-service-go/database"
+	"github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/diskplan"
+	"github.com/sacloud/iaas-service-go/dns"
+	"github.com/sacloud/iaas-service-go/enhanceddb"
+	"github.com/sacloud/iaas-service-go/esme"
+	"github.com/sacloud/iaas-service-go/gslb"
+	"github.com/sacloud/iaas-service-go/icon"
+	"github.com/sacloud/iaas-service-go/iface"
+	"github.com/sacloud/iaas-service-go/internet"
+	"github.com/sacloud/iaas-service-go/internetplan"
+	"github.com/sacloud/iaas-service-go/ipaddress"
+	"github.com/sacloud/iaas-service-go/ipv6addr"
+	"github.com/sacloud/iaas-service-go/ipv6net"
+	"github.com/sacloud/iaas-service-go/license"
+	"github.com/sacloud/iaas-service-go/licenseinfo"
+	"github.com/sacloud/iaas-service-go/loadbalancer"
+	"github.com/sacloud/iaas-service-go/localrouter"
+	"github.com/sacloud/iaas-service-go/mobilegateway"
+	"github.com/sacloud/iaas-service-go/nfs"
+	"github.com/sacloud/iaas-service-go/note"
+	"github.com/sacloud/iaas-service-go/packetfilter"
+	"github.com/sacloud/iaas-service-go/privatehost"
+	"github.com/sacloud/iaas-service-go/privatehostplan"
+	"github.com/sacloud/iaas-service-go/proxylb"
+	"github.com/sacloud/iaas-service-go/region"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/sacloud/iaas-service-go/serverplan"
+	"github.com/sacloud/iaas-service-go/serviceclass"
+	"github.com/sacloud/iaas-service-go/sim"
+	"github.com/sacloud/iaas-service-go/simplemonitor"
+	"github.com/sacloud/iaas-service-go/sshkey"
+	"github.com/sacloud/iaas-service-go/subnet"
+	"github.com/sacloud/iaas-service-go/swytch"
+	"github.com/sacloud/iaas-service-go/vpcrouter"
+	"github.com/sacloud/iaas-service-go/zone"
+)
+
+// resources 登録されているリソースの一覧
+var resources = []*Resource{
+	newResource("Archive", "archive", func(caller iaas.APICaller) interface{} { return archive.New(caller) }),
+	newResource("AuthStatus", "authstatus", func(caller iaas.APICaller) interface{} { return authstatus.New(caller) }),
+	newResource("AutoBackup", "autobackup", func(caller iaas.APICaller) interface{} { return autobackup.New(caller) }),
+	newResource("AutoScale", "autoscale", func(caller iaas.APICaller) interface{} { return autoscale.New(caller) }),
+	newResource("Bill", "bill", func(caller iaas.APICaller) interface{} { return bill.New(caller) }),
+	newResource("Bridge", "bridge", func(caller iaas.APICaller) interface{} { return bridge.New(caller) }),
+	newResource("CDROM", "cdrom", func(caller iaas.APICaller) interface{} { return cdrom.New(caller) }),
+	newResource("CertificateAuthority", "certificateauthority", func(caller iaas.APICaller) interface{} { return certificateauthority.New(caller) }),
+	newResource("ContainerRegistry", "containerregistry", func(caller iaas.APICaller) interface{} { return containerregistry.New(caller) }),
+	newResource("Coupon", "coupon", func(caller iaas.APICaller) interface{} { return coupon.New(caller) }),
+	newResource("Database", "database", func(caller iaas.APICaller) interface{} { return database.New(caller) }),
+	newResource("Disk", "disk", func(caller iaas.APICaller) interface{} { return disk.New(caller) }),
+	newResource("DiskPlan", "diskplan", func(caller iaas.APICaller) interface{} { return diskplan.New(caller) }),
+	newResource("DNS", "dns", func(caller iaas.APICaller) interface{} { return dns.New(caller) }),
+	newResource("EnhancedDB", "enhanceddb", func(caller iaas.APICaller) interface{} { return enhanceddb.New(caller) }),
+	newResource("ESME", "esme", func(caller iaas.APICaller) interface{} { return esme.New(caller) }),
+	newResource("GSLB", "gslb", func(caller iaas.APICaller) interface{} { return gslb.New(caller) }),
+	newResource("Icon", "icon", func(caller iaas.APICaller) interface{} { return icon.New(caller) }),
+	newResource("Interface", "iface", func(caller iaas.APICaller) interface{} { return iface.New(caller) }),
+	newResource("Internet", "internet", func(caller iaas.APICaller) interface{} { return internet.New(caller) }),
+	newResource("InternetPlan", "internetplan", func(caller iaas.APICaller) interface{} { return internetplan.New(caller) }),
+	newResource("IPAddress", "ipaddress", func(caller iaas.APICaller) interface{} { return ipaddress.New(caller) }),
+	newResource("IPv6Addr", "ipv6addr", func(caller iaas.APICaller) interface{} { return ipv6addr.New(caller) }),
+	newResource("IPv6Net", "ipv6net", func(caller iaas.APICaller) interface{} { return ipv6net.New(caller) }),
+	newResource("License", "license", func(caller iaas.APICaller) interface{} { return license.New(caller) }),
+	newResource("LicenseInfo", "licenseinfo", func(caller iaas.APICaller) interface{} { return licenseinfo.New(caller) }),
+	newResource("LoadBalancer", "loadbalancer", func(caller iaas.APICaller) interface{} { return loadbalancer.New(caller) }),
+	newResource("LocalRouter", "localrouter", func(caller iaas.APICaller) interface{} { return localrouter.New(caller) }),
+	newResource("MobileGateway", "mobilegateway", func(caller iaas.APICaller) interface{} { return mobilegateway.New(caller) }),
+	newResource("NFS", "nfs", func(caller iaas.APICaller) interface{} { return nfs.New(caller) }),
+	newResource("Note", "note", func(caller iaas.APICaller) interface{} { return note.New(caller) }),
+	newResource("PacketFilter", "packetfilter", func(caller iaas.APICaller) interface{} { return packetfilter.New(caller) }),
+	newResource("PrivateHost", "privatehost", func(caller iaas.APICaller) interface{} { return privatehost.New(caller) }),
+	newResource("PrivateHostPlan", "privatehostplan", func(caller iaas.APICaller) interface{} { return privatehostplan.New(caller) }),
+	newResource("ProxyLB", "proxylb", func(caller iaas.APICaller) interface{} { return proxylb.New(caller) }),
+	newResource("Region", "region", func(caller iaas.APICaller) interface{} { return region.New(caller) }),
+	newResource("Server", "server", func(caller iaas.APICaller) interface{} { return server.New(caller) }),
+	newResource("ServerPlan", "serverplan", func(caller iaas.APICaller) interface{} { return serverplan.New(caller) }),
+	newResource("ServiceClass", "serviceclass", func(caller iaas.APICaller) interface{} { return serviceclass.New(caller) }),
+	newResource("SIM", "sim", func(caller iaas.APICaller) interface{} { return sim.New(caller) }),
+	newResource("SimpleMonitor", "simplemonitor", func(caller iaas.APICaller) interface{} { return simplemonitor.New(caller) }),
+	newResource("SSHKey", "sshkey", func(caller iaas.APICaller) interface{} { return sshkey.New(caller) }),
+	newResource("Subnet", "subnet", func(caller iaas.APICaller) interface{} { return subnet.New(caller) }),
+	newResource("Switch", "swytch", func(caller iaas.APICaller) interface{} { return swytch.New(caller) }),
+	newResource("VPCRouter", "vpcrouter", func(caller iaas.APICaller) interface{} { return vpcrouter.New(caller) }),
+	newResource("Zone", "zone", func(caller iaas.APICaller) interface{} { return zone.New(caller) }),
+}
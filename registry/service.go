@@ -0,0 +1,117 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package registry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ErrUnsupportedOperation リソースが操作をサポートしていないことを示すエラー
+var ErrUnsupportedOperation = errors.New("unsupported operation")
+
+// Service リソースの種別によらず統一的なインターフェースで操作を行うためのサービス
+type Service struct {
+	resource *Resource
+	service  reflect.Value
+}
+
+// Resource 操作対象のリソースのメタデータを返す
+func (s *Service) Resource() *Resource {
+	return s.resource
+}
+
+// Create リソースを作成する
+func (s *Service) Create(ctx context.Context, req interface{}) (interface{}, error) {
+	return s.Do(ctx, OperationCreate, req)
+}
+
+// Read リソースを参照する
+func (s *Service) Read(ctx context.Context, req interface{}) (interface{}, error) {
+	return s.Do(ctx, OperationRead, req)
+}
+
+// Update リソースを更新する
+func (s *Service) Update(ctx context.Context, req interface{}) (interface{}, error) {
+	return s.Do(ctx, OperationUpdate, req)
+}
+
+// Delete リソースを削除する
+func (s *Service) Delete(ctx context.Context, req interface{}) error {
+	_, err := s.Do(ctx, OperationDelete, req)
+	return err
+}
+
+// List リソースの一覧を返す
+//
+// FindまたはList操作を呼び出し、結果を[]interface{}へ変換して返す。
+func (s *Service) List(ctx context.Context, req interface{}) ([]interface{}, error) {
+	op := OperationFind
+	if !s.resource.Supports(op) {
+		op = OperationList
+	}
+	result, err := s.Do(ctx, op, req)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(result)
+	if v.Kind() != reflect.Slice {
+		return nil, &service.Error{Op: op, Resource: s.resource.Kind, Err: fmt.Errorf("unexpected result type: %T", result)}
+	}
+	results := make([]interface{}, v.Len())
+	for i := range results {
+		results[i] = v.Index(i).Interface()
+	}
+	return results, nil
+}
+
+// Do 名前を指定して操作を行う
+//
+// reqには操作に対応する*XxxRequestを指定する。Operation.NewRequestで空のリクエストを生成できる。
+// 戻り値を持たない操作の場合、nilを返す。
+func (s *Service) Do(ctx context.Context, operation string, req interface{}) (interface{}, error) {
+	op, ok := s.resource.Operation(operation)
+	if !ok {
+		return nil, &service.Error{Op: operation, Resource: s.resource.Kind, Kind: service.ErrorKindValidation, Err: ErrUnsupportedOperation}
+	}
+
+	args := []reflect.Value{reflect.ValueOf(ctx)}
+	if op.RequestType != nil {
+		v := reflect.ValueOf(req)
+		if req == nil || v.Type() != reflect.PointerTo(op.RequestType) || v.IsNil() {
+			return nil, &service.Error{
+				Op:       operation,
+				Resource: s.resource.Kind,
+				Kind:     service.ErrorKindValidation,
+				Err:      fmt.Errorf("request must be *%s: got %T", op.RequestType, req),
+			}
+		}
+		args = append(args, v)
+	}
+
+	out := s.service.MethodByName(op.method).Call(args)
+	if err, _ := out[len(out)-1].Interface().(error); err != nil {
+		return nil, err
+	}
+	if len(out) == 1 {
+		return nil, nil
+	}
+	return out[0].Interface(), nil
+}
@@ -22,6 +22,7 @@ import (
 	diskService "github.com/sacloud/iaas-service-go/disk"
 	diskBuilder "github.com/sacloud/iaas-service-go/disk/builder"
 	server "github.com/sacloud/iaas-service-go/server/builder"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
 	"github.com/sacloud/packages-go/validate"
 )
 
@@ -48,7 +49,10 @@ type ApplyRequest struct {
 	Disks             []*diskService.ApplyRequest
 	NoWait            bool
 
-	ForceShutdown bool
+	ForceShutdown    bool
+	ShutdownStrategy *shutdown.Strategy // 更新時にシャットダウンが必要な場合のシャットダウン方法、ForceShutdownとは同時に指定できない
+
+	RollbackOnFailure bool // 新規作成に失敗した場合に作成済みのリソースを削除するか
 }
 
 func (req *ApplyRequest) Validate() error {
@@ -64,7 +68,7 @@ func (req *ApplyRequest) Validate() error {
 			return errors.New("upstream=shared is not supported for additional NICs")
 		}
 	}
-	return nil
+	return validateShutdownStrategy(req.ShutdownStrategy, req.ForceShutdown)
 }
 
 func (req *ApplyRequest) nicSetting() server.NICSettingHolder {
@@ -116,5 +120,9 @@ func (req *ApplyRequest) Builder(caller iaas.APICaller) (*server.Builder, error)
 		ServerID:        req.ID,
 		ForceShutdown:   req.ForceShutdown,
 		NoWait:          req.NoWait,
+
+		ShutdownStrategy: req.ShutdownStrategy,
+
+		RollbackOnFailure: req.RollbackOnFailure,
 	}, nil
 }
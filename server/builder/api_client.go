@@ -73,6 +73,18 @@ type CreateServerHandler interface {
 	ChangePlan(ctx context.Context, zone string, id types.ID, plan *iaas.ServerChangePlanRequest) (*iaas.Server, error)
 }
 
+// ResourceDeleter リソース削除のためのインターフェース
+//
+// RollbackOnFailureがtrueの場合、DiskHandler/CreateServerHandlerはResourceDeleterを実装している必要がある
+type ResourceDeleter interface {
+	Delete(ctx context.Context, zone string, id types.ID) error
+}
+
+var (
+	_ ResourceDeleter = (*iaas.DiskOp)(nil)
+	_ ResourceDeleter = (*iaas.ServerOp)(nil)
+)
+
 // NewBuildersAPIClient APIクライアントの作成
 func NewBuildersAPIClient(caller iaas.APICaller) *APIClient {
 	return &APIClient{
@@ -27,6 +27,8 @@ import (
 	"github.com/sacloud/iaas-api-go/types"
 	service "github.com/sacloud/iaas-service-go"
 	disk "github.com/sacloud/iaas-service-go/disk/builder"
+	"github.com/sacloud/iaas-service-go/progress"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
 	"github.com/sacloud/packages-go/size"
 )
 
@@ -57,6 +59,10 @@ type Builder struct {
 
 	ServerID      types.ID
 	ForceShutdown bool
+	// ShutdownStrategy 更新時にシャットダウンが必要な場合のシャットダウン方法、ForceShutdownとは同時に指定できない
+	ShutdownStrategy *shutdown.Strategy
+
+	RollbackOnFailure bool // Build失敗時に作成済みのリソースを削除するか
 }
 
 func BuilderFromResource(ctx context.Context, caller iaas.APICaller, zone string, id types.ID) (*Builder, error) {
@@ -125,7 +131,7 @@ func BuilderFromResource(ctx context.Context, caller iaas.APICaller, zone string
 	return &Builder{
 		Name:            current.Name,
 		CPU:             current.CPU,
-		MemoryGB:        current.MemoryMB * size.GiB,
+		MemoryGB:        current.GetMemoryGB(),
 		GPU:             current.GPU,
 		Commitment:      current.ServerPlanCommitment,
 		Generation:      current.ServerPlanGeneration,
@@ -149,6 +155,7 @@ type BuildResult struct {
 	ServerID               types.ID
 	DiskIDs                []types.ID
 	GeneratedSSHPrivateKey string
+	ShutdownStage          shutdown.Stage // Update時にShutdownStrategyによりシャットダウンした場合のシャットダウンが完了した段階
 }
 
 var (
@@ -183,6 +190,15 @@ func (b *Builder) Validate(ctx context.Context, zone string) error {
 		return fmt.Errorf("invalid InterfaceDriver: %s", b.InterfaceDriver)
 	}
 
+	if b.ShutdownStrategy != nil {
+		if b.ForceShutdown {
+			return errors.New("ShutdownStrategy cannot be used with ForceShutdown")
+		}
+		if err := b.ShutdownStrategy.Validate(); err != nil {
+			return fmt.Errorf("invalid ShutdownStrategy: %w", err)
+		}
+	}
+
 	// NICs
 	if b.NIC != nil {
 		if err := b.NIC.Validate(ctx, b.Client, zone); err != nil {
@@ -224,31 +240,43 @@ func (b *Builder) Validate(ctx context.Context, zone string) error {
 }
 
 // Build サーバ構築を行う
+//
+// RollbackOnFailureがtrueの場合、構築に失敗した際は作成済みのリソースを逆順に削除した上で*RollbackErrorを返す
 func (b *Builder) Build(ctx context.Context, zone string) (*BuildResult, error) {
 	// validate
-	if err := b.Validate(ctx, zone); err != nil {
+	if err := b.validate(ctx, zone); err != nil {
 		return nil, err
 	}
 
+	rollback := &rollbackStack{}
+	result, err := b.build(ctx, zone, rollback)
+	if err != nil {
+		if b.RollbackOnFailure && !rollback.empty() {
+			return nil, rollback.rollback(ctx, err)
+		}
+		return result, err
+	}
+
+	b.ServerID = result.ServerID
+	return result, nil
+}
+
+func (b *Builder) build(ctx context.Context, zone string, rollback *rollbackStack) (*BuildResult, error) {
 	// create server
 	server, err := b.createServer(ctx, zone)
 	if err != nil {
 		return nil, err
 	}
+	progress.Emit(ctx, &progress.ResourceCreated{Resource: progress.Resource{Kind: "Server", Zone: zone, ID: server.ID}})
+	rollback.push(b.rollbackServer(zone, server.ID))
 	result := &BuildResult{
 		ServerID: server.ID,
 	}
+	resource := progress.Resource{Kind: "Server", Zone: zone, ID: server.ID}
 
 	// create&connect disk(s)
-	for _, diskReq := range b.DiskBuilders {
-		builtDisk, err := diskReq.Build(ctx, zone, server.ID)
-		if err != nil {
-			return result, err
-		}
-		result.DiskIDs = append(result.DiskIDs, builtDisk.DiskID)
-		if builtDisk.GeneratedSSHKey != nil {
-			result.GeneratedSSHPrivateKey = builtDisk.GeneratedSSHKey.PrivateKey
-		}
+	if err := b.buildDisks(ctx, zone, server.ID, rollback, result); err != nil {
+		return result, err
 	}
 
 	// connect packet filter
@@ -266,15 +294,60 @@ func (b *Builder) Build(ctx context.Context, zone string) (*BuildResult, error)
 
 	// bool
 	if !b.NoWait && b.BootAfterCreate {
-		if err := power.BootServer(ctx, b.Client.Server, zone, server.ID, b.userData()...); err != nil {
+		rollback.push(b.rollbackShutdown(zone, server.ID))
+		done := progress.StartPhase(ctx, resource, progress.PhaseBoot)
+		err := power.BootServer(ctx, b.Client.Server, zone, server.ID, b.userData()...)
+		done(err)
+		if err != nil {
 			return result, err
 		}
 	}
 
-	b.ServerID = result.ServerID
 	return result, nil
 }
 
+// validate Validateの処理をPhaseValidateとして通知する
+func (b *Builder) validate(ctx context.Context, zone string) (err error) {
+	done := progress.StartPhase(ctx, progress.Resource{Kind: "Server", Zone: zone, ID: b.ServerID}, progress.PhaseValidate)
+	defer func() { done(err) }()
+	return b.Validate(ctx, zone)
+}
+
+// shutdown ShutdownStrategyが指定されていれば段階的に、そうでなければForceShutdownに従ってシャットダウンする
+//
+// 段階的にシャットダウンした場合はシャットダウンが完了した(エラーの場合は失敗した)段階を返す
+func (b *Builder) shutdown(ctx context.Context, zone string, id types.ID) (shutdown.Stage, error) {
+	if b.ShutdownStrategy != nil {
+		return b.ShutdownStrategy.Shutdown(ctx, b.Client.Server, zone, id)
+	}
+	return "", power.ShutdownServer(ctx, b.Client.Server, zone, id, b.ForceShutdown)
+}
+
+func (b *Builder) buildDisks(ctx context.Context, zone string, serverID types.ID, rollback *rollbackStack, result *BuildResult) (err error) {
+	done := progress.StartPhase(ctx, progress.Resource{Kind: "Server", Zone: zone, ID: serverID}, progress.PhaseBuildDisks)
+	defer func() { done(err) }()
+
+	for _, diskReq := range b.DiskBuilders {
+		if cleaner, ok := diskReq.(disk.EphemeralResourceCleaner); ok {
+			rollback.pushFinally(cleaner.CleanupEphemeralResources)
+		}
+		isNewDisk := diskReq.DiskID().IsEmpty()
+
+		builtDisk, err := diskReq.Build(ctx, zone, serverID)
+		if builtDisk != nil && isNewDisk && !builtDisk.DiskID.IsEmpty() {
+			rollback.push(b.rollbackDisk(zone, builtDisk.DiskID))
+		}
+		if err != nil {
+			return err
+		}
+		result.DiskIDs = append(result.DiskIDs, builtDisk.DiskID)
+		if builtDisk.GeneratedSSHKey != nil {
+			result.GeneratedSSHPrivateKey = builtDisk.GeneratedSSHKey.PrivateKey
+		}
+	}
+	return nil
+}
+
 // IsNeedShutdown Update時にシャットダウンが必要か
 func (b *Builder) IsNeedShutdown(ctx context.Context, zone string) (bool, error) {
 	if b.ServerID.IsEmpty() {
@@ -338,7 +411,7 @@ func (b *Builder) fillDummyValueToState(state ...*nicState) {
 // Update サーバの更新
 func (b *Builder) Update(ctx context.Context, zone string) (*BuildResult, error) {
 	// validate
-	if err := b.Validate(ctx, zone); err != nil {
+	if err := b.validate(ctx, zone); err != nil {
 		return nil, err
 	}
 	if b.ServerID.IsEmpty() {
@@ -357,30 +430,43 @@ func (b *Builder) Update(ctx context.Context, zone string) (*BuildResult, error)
 		return result, err
 	}
 
+	resource := progress.Resource{Kind: "Server", Zone: zone, ID: server.ID}
+
 	// shutdown
 	running := server.InstanceStatus.IsUp()
 	if isNeedShutdown && running {
 		if b.NoWait {
 			return nil, errors.New("NoWait option is not available due to the need to shut down")
 		}
-		if err := power.ShutdownServer(ctx, b.Client.Server, zone, server.ID, b.ForceShutdown); err != nil {
+		done := progress.StartPhase(ctx, resource, progress.PhaseShutdown)
+		stage, err := b.shutdown(ctx, zone, server.ID)
+		result.ShutdownStage = stage
+		done(err)
+		if err != nil {
 			return result, err
 		}
 	}
 
 	// reconcile disks
-	if err := b.reconcileDisks(ctx, zone, server, result); err != nil {
+	done := progress.StartPhase(ctx, resource, progress.PhaseReconcileDisks)
+	err = b.reconcileDisks(ctx, zone, server, result)
+	done(err)
+	if err != nil {
 		return result, err
 	}
 
 	// reconcile interface
-	if err := b.reconcileInterfaces(ctx, zone, server); err != nil {
+	done = progress.StartPhase(ctx, resource, progress.PhaseReconcileInterfaces)
+	err = b.reconcileInterfaces(ctx, zone, server)
+	done(err)
+	if err != nil {
 		return result, err
 	}
 
 	// plan
 	if b.isPlanChanged(server) {
 		b.Tags = plans.AppendPreviousIDTagIfAbsent(b.Tags, server.ID)
+		done := progress.StartPhase(ctx, resource, progress.PhaseChangePlan)
 		updated, err := b.Client.Server.ChangePlan(ctx, zone, server.ID, &iaas.ServerChangePlanRequest{
 			CPU:                  b.CPU,
 			MemoryMB:             b.MemoryGB * size.GiB,
@@ -388,6 +474,7 @@ func (b *Builder) Update(ctx context.Context, zone string) (*BuildResult, error)
 			ServerPlanGeneration: b.Generation,
 			ServerPlanCommitment: b.Commitment,
 		})
+		done(err)
 		if err != nil {
 			return result, err
 		}
@@ -423,7 +510,10 @@ func (b *Builder) Update(ctx context.Context, zone string) (*BuildResult, error)
 
 	// boot
 	if isNeedShutdown && running && server.InstanceStatus.IsDown() {
-		if err := power.BootServer(ctx, b.Client.Server, zone, server.ID, b.userData()...); err != nil {
+		done := progress.StartPhase(ctx, resource, progress.PhaseBoot)
+		err := power.BootServer(ctx, b.Client.Server, zone, server.ID, b.userData()...)
+		done(err)
+		if err != nil {
 			return result, err
 		}
 	}
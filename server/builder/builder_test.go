@@ -30,6 +30,7 @@ import (
 	"github.com/sacloud/iaas-api-go/types"
 	service "github.com/sacloud/iaas-service-go"
 	disk "github.com/sacloud/iaas-service-go/disk/builder"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/crypto/ssh"
 )
@@ -372,6 +373,16 @@ func (d *dummyDiskBuilder) NoWaitFlag() bool {
 	return d.noWait
 }
 
+func (d *dummyDiskBuilder) ChangeSet(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	if d.err != nil {
+		return nil, d.err
+	}
+	if d.diskID.IsEmpty() {
+		return service.NewCreateChangeSet(), nil
+	}
+	return service.NewUpdateChangeSet(d.diskID), nil
+}
+
 func TestBuilder_Build_BlackBox(t *testing.T) {
 	var switchID types.ID
 	var diskIDs []types.ID
@@ -815,6 +826,42 @@ func TestBuilder_UpdateWithPreviousID(t *testing.T) {
 	}
 }
 
+func TestBuilder_UpdateWithShutdownStrategy(t *testing.T) {
+	ctx := context.Background()
+	builder := &Builder{
+		Name:            testutil.ResourceName("server-builder"),
+		CPU:             1,
+		MemoryGB:        1,
+		Commitment:      types.Commitments.Standard,
+		Generation:      types.PlanGenerations.Default,
+		BootAfterCreate: true,
+		Client:          NewBuildersAPIClient(testutil.SingletonAPICaller()),
+	}
+	createResult, err := builder.Build(ctx, testutil.TestZone())
+	require.NoError(t, err)
+
+	// プラン変更のためシャットダウンが必要
+	builder.ServerID = createResult.ServerID
+	builder.CPU = 2
+	builder.MemoryGB = 4
+	builder.ShutdownStrategy = &shutdown.Strategy{Timeout: time.Minute}
+
+	// ForceShutdownとは同時に指定できない
+	builder.ForceShutdown = true
+	_, err = builder.Update(ctx, testutil.TestZone())
+	require.EqualError(t, err, "ShutdownStrategy cannot be used with ForceShutdown")
+	builder.ForceShutdown = false
+
+	updateResult, err := builder.Update(ctx, testutil.TestZone())
+	require.NoError(t, err)
+	require.Equal(t, shutdown.StageACPI, updateResult.ShutdownStage)
+
+	// cleanup
+	serverOp := iaas.NewServerOp(testutil.SingletonAPICaller())
+	require.NoError(t, power.ShutdownServer(ctx, serverOp, testutil.TestZone(), updateResult.ServerID, true))
+	require.NoError(t, serverOp.Delete(ctx, testutil.TestZone(), updateResult.ServerID))
+}
+
 func TestBuilder_GPUPlan(t *testing.T) {
 	if !testutil.IsAccTest() {
 		t.Skip("TestBuilder_GPUPlan only exec when running an Acceptance Test")
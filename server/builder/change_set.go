@@ -0,0 +1,207 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/plans"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	disk "github.com/sacloud/iaas-service-go/disk/builder"
+)
+
+// ChangeSet Build/Updateを行った場合の変更内容を返す
+//
+// 入力値の検証や現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	if err := b.Validate(ctx, zone); err != nil {
+		return nil, err
+	}
+	if b.ServerID.IsEmpty() {
+		return b.planForCreate(ctx, zone)
+	}
+	return b.planForUpdate(ctx, zone)
+}
+
+func (b *Builder) planForCreate(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	cs := service.NewCreateChangeSet()
+	cs.Create("Name", b.Name)
+	cs.Create("CPU", b.CPU)
+	cs.Create("MemoryGB", b.MemoryGB)
+	cs.Create("GPU", b.GPU)
+	cs.Create("Commitment", b.Commitment)
+	cs.Create("Generation", b.Generation)
+	cs.Create("InterfaceDriver", b.InterfaceDriver)
+	cs.Create("Description", b.Description)
+	cs.Create("IconID", b.IconID)
+	cs.Create("Tags", b.Tags)
+	cs.Create("CDROMID", b.CDROMID)
+	cs.Create("PrivateHostID", b.PrivateHostID)
+
+	desired := b.desiredState()
+	if desired.nic != nil {
+		cs.Create("NIC", desired.nic.String())
+	}
+	for i, nic := range desired.additionalNICs {
+		cs.Create(fmt.Sprintf("AdditionalNICs[%d]", i), nic.String())
+	}
+
+	for i, diskBuilder := range b.DiskBuilders {
+		diskChanges, err := disk.ChangeSet(ctx, diskBuilder, zone)
+		if err != nil {
+			return nil, err
+		}
+		field := fmt.Sprintf("Disks[%d]", i)
+		if diskChanges.Action == service.ChangeActionCreate {
+			cs.Create(field, service.UnknownValue)
+		} else {
+			cs.Create(field, diskBuilder.DiskID())
+		}
+		cs.Merge(field, diskChanges)
+	}
+	return cs, nil
+}
+
+func (b *Builder) planForUpdate(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	server, err := b.Client.Server.Read(ctx, zone, b.ServerID)
+	if err != nil {
+		return nil, err
+	}
+
+	isNeedShutdown, err := b.IsNeedShutdown(ctx, zone)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := service.NewUpdateChangeSet(server.ID)
+
+	// plan: プラン変更時はサーバのIDが変わる
+	tags := b.Tags
+	if b.isPlanChanged(server) {
+		cs.Replace("CPU", server.CPU, b.CPU)
+		cs.Replace("MemoryGB", server.GetMemoryGB(), b.MemoryGB)
+		cs.Replace("GPU", server.GPU, b.GPU)
+		cs.Replace("Commitment", server.ServerPlanCommitment, b.Commitment)
+		if b.Generation != types.PlanGenerations.Default {
+			cs.Replace("Generation", server.ServerPlanGeneration, b.Generation)
+		}
+		tags = plans.AppendPreviousIDTagIfAbsent(append(types.Tags{}, b.Tags...), server.ID)
+	}
+
+	cs.Update("Name", server.Name, b.Name)
+	cs.Update("Description", server.Description, b.Description)
+	cs.Update("Tags", server.Tags, tags)
+	cs.Update("IconID", server.IconID, b.IconID)
+	cs.Update("PrivateHostID", server.PrivateHostID, b.PrivateHostID)
+	cs.Update("InterfaceDriver", server.InterfaceDriver, b.InterfaceDriver)
+	if !b.CDROMID.IsEmpty() {
+		cs.Update("CDROMID", server.CDROMID, b.CDROMID)
+	}
+
+	b.planInterfaces(cs, server)
+	if err := b.planDisks(ctx, zone, cs, server); err != nil {
+		return nil, err
+	}
+
+	if isNeedShutdown {
+		cs.NeedShutdown()
+	}
+	return cs, nil
+}
+
+func (b *Builder) planInterfaces(cs *service.ChangeSet, server *iaas.Server) {
+	current := b.currentState(server)
+	desired := b.desiredState()
+
+	currentNICs := []*nicState{current.nic}
+	currentNICs = append(currentNICs, current.additionalNICs...)
+	desiredNICs := []*nicState{desired.nic}
+	desiredNICs = append(desiredNICs, desired.additionalNICs...)
+
+	count := len(currentNICs)
+	if len(desiredNICs) > count {
+		count = len(desiredNICs)
+	}
+	for i := 0; i < count; i++ {
+		field := "NIC"
+		if i > 0 {
+			field = fmt.Sprintf("AdditionalNICs[%d]", i-1)
+		}
+
+		var c, d *nicState
+		if i < len(currentNICs) {
+			c = currentNICs[i]
+		}
+		if i < len(desiredNICs) {
+			d = desiredNICs[i]
+		}
+
+		switch {
+		case c == nil && d == nil:
+			continue
+		case c == nil:
+			cs.Create(field, d.String())
+		case d == nil:
+			cs.Delete(field, c.String())
+		default:
+			cs.Update(field, c.String(), d.String())
+		}
+	}
+}
+
+func (b *Builder) planDisks(ctx context.Context, zone string, cs *service.ChangeSet, server *iaas.Server) error {
+	for i, diskBuilder := range b.DiskBuilders {
+		field := fmt.Sprintf("Disks[%d]", i)
+
+		var current *iaas.ServerConnectedDisk
+		if i < len(server.Disks) {
+			current = server.Disks[i]
+		}
+		desiredID := diskBuilder.DiskID()
+
+		diskChanges, err := disk.ChangeSet(ctx, diskBuilder, zone)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case current == nil:
+			// 新規作成 or 既存ディスクの接続
+			if desiredID.IsEmpty() {
+				cs.Create(field, service.UnknownValue)
+			} else {
+				cs.Create(field, desiredID)
+			}
+		case current.ID != desiredID:
+			// 別のディスクと入れ替え(現在のディスクは切断される)
+			cs.Delete(field, current.ID)
+			if desiredID.IsEmpty() {
+				cs.Create(field, service.UnknownValue)
+			} else {
+				cs.Create(field, desiredID)
+			}
+		}
+		cs.Merge(field, diskChanges)
+	}
+
+	// 指定されなかったディスクは切断される
+	for i := len(b.DiskBuilders); i < len(server.Disks); i++ {
+		cs.Delete(fmt.Sprintf("Disks[%d]", i), server.Disks[i].ID)
+	}
+	return nil
+}
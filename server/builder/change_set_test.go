@@ -0,0 +1,113 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	disk "github.com/sacloud/iaas-service-go/disk/builder"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_ChangeSet(t *testing.T) {
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	builder := &Builder{
+		Name:            testutil.ResourceName("server-builder"),
+		CPU:             1,
+		MemoryGB:        1,
+		Commitment:      types.Commitments.Standard,
+		Generation:      types.PlanGenerations.Default,
+		Tags:            types.Tags{"tag1", "tag2"},
+		BootAfterCreate: false,
+		Client:          NewBuildersAPIClient(testutil.SingletonAPICaller()),
+		ForceShutdown:   true,
+	}
+
+	t.Run("create", func(t *testing.T) {
+		cs, err := builder.ChangeSet(ctx, zone)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionCreate, cs.Action)
+		require.True(t, cs.HasChanges())
+	})
+
+	created, err := builder.Build(ctx, zone)
+	require.NoError(t, err)
+
+	serverOp := iaas.NewServerOp(testutil.SingletonAPICaller())
+	defer serverOp.Delete(ctx, zone, created.ServerID) //nolint:errcheck
+
+	builder.ServerID = created.ServerID
+
+	t.Run("no changes", func(t *testing.T) {
+		cs, err := builder.ChangeSet(ctx, zone)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionNone, cs.Action)
+		require.Equal(t, service.UpdateLevelNone, cs.UpdateLevel)
+		require.False(t, cs.HasChanges())
+	})
+
+	t.Run("simple update", func(t *testing.T) {
+		name := builder.Name
+		builder.Name = name + "-upd"
+		defer func() { builder.Name = name }()
+
+		cs, err := builder.ChangeSet(ctx, zone)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionUpdate, cs.Action)
+		require.Equal(t, service.UpdateLevelSimple, cs.UpdateLevel)
+		require.Len(t, cs.Changes, 1)
+		require.Equal(t, "Name", cs.Changes[0].Field)
+	})
+
+	t.Run("plan change", func(t *testing.T) {
+		builder.CPU = 2
+		builder.MemoryGB = 4
+		defer func() {
+			builder.CPU = 1
+			builder.MemoryGB = 1
+		}()
+
+		cs, err := builder.ChangeSet(ctx, zone)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionReplace, cs.Action)
+		require.Equal(t, service.UpdateLevelNeedShutdown, cs.UpdateLevel)
+
+		// 変更内容の算出のみで更新されていないはず
+		server, err := serverOp.Read(ctx, zone, created.ServerID)
+		require.NoError(t, err)
+		require.Equal(t, 1, server.CPU)
+	})
+}
+
+func TestBuilder_ChangeSet_withoutDiskChangeSetter(t *testing.T) {
+	// disk.Builderのみを実装したビルダー
+	diskBuilder := struct{ disk.Builder }{Builder: &dummyDiskBuilder{}}
+
+	builder := &Builder{
+		Name:         testutil.ResourceName("server-builder"),
+		CPU:          1,
+		MemoryGB:     1,
+		DiskBuilders: []disk.Builder{diskBuilder},
+		Client:       NewBuildersAPIClient(testutil.SingletonAPICaller()),
+	}
+	_, err := builder.ChangeSet(context.Background(), testutil.TestZone())
+	require.Error(t, err)
+}
@@ -186,3 +186,18 @@ func (d *DisconnectedNICSetting) state() *nicState {
 		displayIP:      "",
 	}
 }
+
+// String 変更内容の表示用の文字列を返す
+func (s *nicState) String() string {
+	if s == nil {
+		return ""
+	}
+	switch s.upstreamType {
+	case types.UpstreamNetworkTypes.None:
+		return "upstream=none"
+	case types.UpstreamNetworkTypes.Shared:
+		return fmt.Sprintf("upstream=shared packetfilter=%s", s.packetFilterID)
+	default:
+		return fmt.Sprintf("upstream=%s packetfilter=%s displayip=%s", s.switchID, s.packetFilterID, s.displayIP)
+	}
+}
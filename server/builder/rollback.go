@@ -0,0 +1,143 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/power"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/progress"
+)
+
+// RollbackError RollbackOnFailure=trueの場合にBuildが失敗した際のエラー
+//
+// 構築時のエラーに加え、作成済みリソースの削除時に発生したエラーを保持する
+type RollbackError struct {
+	Err           error   // 構築時のエラー
+	CleanupErrors []error // ロールバック時のエラー
+}
+
+func (e *RollbackError) Error() string {
+	if len(e.CleanupErrors) == 0 {
+		return fmt.Sprintf("build failed and rolled back: %s", e.Err)
+	}
+	var messages []string
+	for _, err := range e.CleanupErrors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("build failed: %s, and rollback failed: [%s]", e.Err, strings.Join(messages, ", "))
+}
+
+// Unwrap 構築時のエラーとロールバック時のエラーを返す
+func (e *RollbackError) Unwrap() []error {
+	return append([]error{e.Err}, e.CleanupErrors...)
+}
+
+// rollbackStack Build時に作成したリソースの削除処理を記録し、失敗時に逆順で実行する
+//
+// pushFinallyで登録した処理(一時的なSSHキー/スタートアップスクリプトの削除)は、
+// ディスク/サーバの削除が全て終わった後に実行される
+type rollbackStack struct {
+	actions []func(ctx context.Context) error
+	finally []func(ctx context.Context) error
+}
+
+func (r *rollbackStack) push(action func(ctx context.Context) error) {
+	r.actions = append(r.actions, action)
+}
+
+func (r *rollbackStack) pushFinally(action func(ctx context.Context) error) {
+	r.finally = append(r.finally, action)
+}
+
+func (r *rollbackStack) empty() bool {
+	return len(r.actions) == 0 && len(r.finally) == 0
+}
+
+func (r *rollbackStack) rollback(ctx context.Context, cause error) *RollbackError {
+	// 構築失敗の原因がキャンセルの場合でも後始末は行う
+	if ctx.Err() != nil {
+		ctx = context.Background()
+	}
+
+	rollbackErr := &RollbackError{Err: cause}
+	for i := len(r.actions) - 1; i >= 0; i-- {
+		if err := r.actions[i](ctx); err != nil {
+			rollbackErr.CleanupErrors = append(rollbackErr.CleanupErrors, err)
+		}
+	}
+	for i := len(r.finally) - 1; i >= 0; i-- {
+		if err := r.finally[i](ctx); err != nil {
+			rollbackErr.CleanupErrors = append(rollbackErr.CleanupErrors, err)
+		}
+	}
+	return rollbackErr
+}
+
+func (b *Builder) rollbackShutdown(zone string, id types.ID) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		server, err := b.Client.Server.Read(ctx, zone, id)
+		if err != nil {
+			if iaas.IsNotFoundError(err) {
+				return nil
+			}
+			return fmt.Errorf("reading server[%s] failed: %w", id, err)
+		}
+		if server.InstanceStatus.IsUp() {
+			if err := power.ShutdownServer(ctx, b.Client.Server, zone, id, true); err != nil {
+				return fmt.Errorf("shutting down server[%s] failed: %w", id, err)
+			}
+		}
+		return nil
+	}
+}
+
+func (b *Builder) rollbackServer(zone string, id types.ID) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if err := b.rollbackShutdown(zone, id)(ctx); err != nil {
+			return err
+		}
+		deleter, ok := b.Client.Server.(ResourceDeleter)
+		if !ok {
+			return fmt.Errorf("deleting server[%s] failed: %T does not implement ResourceDeleter", id, b.Client.Server)
+		}
+		if err := deleter.Delete(ctx, zone, id); err != nil && !iaas.IsNotFoundError(err) {
+			return fmt.Errorf("deleting server[%s] failed: %w", id, err)
+		}
+		progress.Emit(ctx, &progress.ResourceDeleted{Resource: progress.Resource{Kind: "Server", Zone: zone, ID: id}})
+		return nil
+	}
+}
+
+func (b *Builder) rollbackDisk(zone string, id types.ID) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		if err := b.Client.Disk.DisconnectFromServer(ctx, zone, id); err != nil && !iaas.IsNotFoundError(err) {
+			return fmt.Errorf("disconnecting disk[%s] failed: %w", id, err)
+		}
+		deleter, ok := b.Client.Disk.(ResourceDeleter)
+		if !ok {
+			return fmt.Errorf("deleting disk[%s] failed: %T does not implement ResourceDeleter", id, b.Client.Disk)
+		}
+		if err := deleter.Delete(ctx, zone, id); err != nil && !iaas.IsNotFoundError(err) {
+			return fmt.Errorf("deleting disk[%s] failed: %w", id, err)
+		}
+		progress.Emit(ctx, &progress.ResourceDeleted{Resource: progress.Resource{Kind: "Disk", Zone: zone, ID: id}})
+		return nil
+	}
+}
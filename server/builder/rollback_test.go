@@ -0,0 +1,225 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	disk "github.com/sacloud/iaas-service-go/disk/builder"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyDiskHandler struct {
+	deleted []types.ID
+	err     error
+}
+
+func (d *dummyDiskHandler) ConnectToServer(ctx context.Context, zone string, id types.ID, serverID types.ID) error {
+	return d.err
+}
+
+func (d *dummyDiskHandler) DisconnectFromServer(ctx context.Context, zone string, id types.ID) error {
+	return d.err
+}
+
+func (d *dummyDiskHandler) Delete(ctx context.Context, zone string, id types.ID) error {
+	if d.err != nil {
+		return d.err
+	}
+	d.deleted = append(d.deleted, id)
+	return nil
+}
+
+type deletableServerHandler struct {
+	*dummyCreateServerHandler
+	deleted   bool
+	deleteErr error
+}
+
+func (d *deletableServerHandler) Delete(ctx context.Context, zone string, id types.ID) error {
+	if d.deleteErr != nil {
+		return d.deleteErr
+	}
+	d.deleted = true
+	return nil
+}
+
+// waitFailingDiskBuilder ディスク作成後のコピー待ちで失敗するビルダー
+type waitFailingDiskBuilder struct {
+	*dummyDiskBuilder
+	err error
+}
+
+func (d *waitFailingDiskBuilder) Build(ctx context.Context, zone string, serverID types.ID) (*disk.BuildResult, error) {
+	return d.result, d.err
+}
+
+func TestBuilder_Build_Rollback(t *testing.T) {
+	buildErr := errors.New("dummy")
+
+	newBuilder := func(serverHandler CreateServerHandler, diskHandler DiskHandler) *Builder {
+		return &Builder{
+			CDROMID: 1,
+			DiskBuilders: []disk.Builder{
+				&dummyDiskBuilder{result: &disk.BuildResult{DiskID: 2}},
+				&dummyDiskBuilder{result: &disk.BuildResult{DiskID: 3}, diskID: 3},
+			},
+			Client: &APIClient{
+				Disk:         diskHandler,
+				Switch:       &dummySwitchReader{},
+				PacketFilter: &dummyPackerFilterReader{},
+				ServerPlan: &dummyPlanFinder{
+					plans: []*iaas.ServerPlan{{ID: 1}},
+				},
+				Server: serverHandler,
+			},
+			RollbackOnFailure: true,
+		}
+	}
+
+	t.Run("rollback created resources", func(t *testing.T) {
+		serverHandler := &deletableServerHandler{dummyCreateServerHandler: &dummyCreateServerHandler{server: &iaas.Server{ID: 1}, cdromErr: buildErr}}
+		diskHandler := &dummyDiskHandler{}
+
+		res, err := newBuilder(serverHandler, diskHandler).Build(context.Background(), "tk1v")
+		require.Nil(t, res)
+
+		var rollbackErr *RollbackError
+		require.True(t, errors.As(err, &rollbackErr))
+		require.True(t, errors.Is(err, buildErr))
+		require.Empty(t, rollbackErr.CleanupErrors)
+
+		require.True(t, serverHandler.deleted)
+		// 既存ディスク(ID:3)は削除されないはず
+		require.Equal(t, []types.ID{2}, diskHandler.deleted)
+	})
+
+	t.Run("rollback failed", func(t *testing.T) {
+		deleteErr := errors.New("delete failed")
+		serverHandler := &deletableServerHandler{dummyCreateServerHandler: &dummyCreateServerHandler{server: &iaas.Server{ID: 1}, cdromErr: buildErr}, deleteErr: deleteErr}
+		diskHandler := &dummyDiskHandler{}
+
+		_, err := newBuilder(serverHandler, diskHandler).Build(context.Background(), "tk1v")
+
+		var rollbackErr *RollbackError
+		require.True(t, errors.As(err, &rollbackErr))
+		require.Equal(t, buildErr, rollbackErr.Err)
+		require.Len(t, rollbackErr.CleanupErrors, 1)
+		require.True(t, errors.Is(err, deleteErr))
+	})
+
+	t.Run("disk copy failed", func(t *testing.T) {
+		serverHandler := &deletableServerHandler{dummyCreateServerHandler: &dummyCreateServerHandler{server: &iaas.Server{ID: 1}}}
+		diskHandler := &dummyDiskHandler{}
+		builder := newBuilder(serverHandler, diskHandler)
+		builder.DiskBuilders = []disk.Builder{
+			&dummyDiskBuilder{result: &disk.BuildResult{DiskID: 2}},
+			&waitFailingDiskBuilder{dummyDiskBuilder: &dummyDiskBuilder{result: &disk.BuildResult{DiskID: 3}}, err: buildErr},
+		}
+
+		_, err := builder.Build(context.Background(), "tk1v")
+		require.True(t, errors.Is(err, buildErr))
+		require.True(t, serverHandler.deleted)
+		// コピー待ちで失敗したディスクも削除されるはず
+		require.Equal(t, []types.ID{3, 2}, diskHandler.deleted)
+	})
+
+	t.Run("without deleter", func(t *testing.T) {
+		serverHandler := &dummyCreateServerHandler{server: &iaas.Server{ID: 1}, cdromErr: buildErr}
+		diskHandler := &dummyDiskHandler{}
+
+		_, err := newBuilder(serverHandler, diskHandler).Build(context.Background(), "tk1v")
+
+		var rollbackErr *RollbackError
+		require.True(t, errors.As(err, &rollbackErr))
+		require.Len(t, rollbackErr.CleanupErrors, 1)
+		require.Equal(t, []types.ID{2}, diskHandler.deleted)
+	})
+
+	t.Run("without rollback", func(t *testing.T) {
+		serverHandler := &deletableServerHandler{dummyCreateServerHandler: &dummyCreateServerHandler{server: &iaas.Server{ID: 1}, cdromErr: buildErr}}
+		diskHandler := &dummyDiskHandler{}
+		builder := newBuilder(serverHandler, diskHandler)
+		builder.RollbackOnFailure = false
+
+		res, err := builder.Build(context.Background(), "tk1v")
+		require.Equal(t, buildErr, err)
+		require.Equal(t, &BuildResult{ServerID: 1, DiskIDs: []types.ID{2, 3}}, res)
+		require.False(t, serverHandler.deleted)
+		require.Empty(t, diskHandler.deleted)
+	})
+}
+
+type recordingServerHandler struct {
+	*dummyCreateServerHandler
+	log *[]string
+}
+
+func (r *recordingServerHandler) Delete(ctx context.Context, zone string, id types.ID) error {
+	*r.log = append(*r.log, "server:"+id.String())
+	return nil
+}
+
+type recordingDiskHandler struct {
+	*dummyDiskHandler
+	log *[]string
+}
+
+func (r *recordingDiskHandler) Delete(ctx context.Context, zone string, id types.ID) error {
+	*r.log = append(*r.log, "disk:"+id.String())
+	return r.dummyDiskHandler.Delete(ctx, zone, id)
+}
+
+type ephemeralDiskBuilder struct {
+	*dummyDiskBuilder
+	log *[]string
+}
+
+func (e *ephemeralDiskBuilder) CleanupEphemeralResources(ctx context.Context) error {
+	*e.log = append(*e.log, "ephemeral:"+e.result.DiskID.String())
+	return nil
+}
+
+func TestBuilder_Build_RollbackOrder(t *testing.T) {
+	var log []string
+	builder := &Builder{
+		CDROMID: 1,
+		DiskBuilders: []disk.Builder{
+			&ephemeralDiskBuilder{dummyDiskBuilder: &dummyDiskBuilder{result: &disk.BuildResult{DiskID: 2}}, log: &log},
+			&ephemeralDiskBuilder{dummyDiskBuilder: &dummyDiskBuilder{result: &disk.BuildResult{DiskID: 3}}, log: &log},
+		},
+		Client: &APIClient{
+			Disk:         &recordingDiskHandler{dummyDiskHandler: &dummyDiskHandler{}, log: &log},
+			Switch:       &dummySwitchReader{},
+			PacketFilter: &dummyPackerFilterReader{},
+			ServerPlan: &dummyPlanFinder{
+				plans: []*iaas.ServerPlan{{ID: 1}},
+			},
+			Server: &recordingServerHandler{
+				dummyCreateServerHandler: &dummyCreateServerHandler{server: &iaas.Server{ID: 1}, cdromErr: errors.New("dummy")},
+				log:                      &log,
+			},
+		},
+		RollbackOnFailure: true,
+	}
+
+	_, err := builder.Build(context.Background(), "tk1v")
+	require.Error(t, err)
+	require.Equal(t, []string{"disk:3", "disk:2", "server:1", "ephemeral:3", "ephemeral:2"}, log)
+}
@@ -16,20 +16,24 @@ package server
 
 import (
 	"context"
-	"fmt"
+	"errors"
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/plans"
 	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) ChangePlan(req *ChangePlanRequest) (*iaas.Server, error) {
 	return s.ChangePlanWithContext(context.Background(), req)
 }
 
-func (s *Service) ChangePlanWithContext(ctx context.Context, req *ChangePlanRequest) (*iaas.Server, error) {
+func (s *Service) ChangePlanWithContext(ctx context.Context, req *ChangePlanRequest) (_ *iaas.Server, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ChangePlan", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "ChangePlan", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewServerOp(s.caller)
@@ -38,7 +42,7 @@ func (s *Service) ChangePlanWithContext(ctx context.Context, req *ChangePlanRequ
 		return nil, err
 	}
 	if !current.InstanceStatus.IsDown() {
-		return nil, fmt.Errorf("server[%s] is still running", req.ID)
+		return nil, &service.Error{Op: "ChangePlan", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindConflict, Err: errors.New("server is still running")}
 	}
 
 	changeReq := &iaas.ServerChangePlanRequest{
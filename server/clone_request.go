@@ -0,0 +1,68 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/sacloud/iaas-api-go/types"
+	diskService "github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
+	"github.com/sacloud/packages-go/validate"
+)
+
+// CloneRequest 既存サーバと同じ構成のサーバを作成するリクエスト
+//
+// 省略した項目はコピー元サーバの値を引き継ぐ
+type CloneRequest struct {
+	Zone string   `service:"-" validate:"required"`
+	ID   types.ID `service:"-" validate:"required"` // コピー元サーバのID
+
+	Name        string `validate:"omitempty,min=1"` // 省略時はコピー元サーバの名前
+	Description *string
+	Tags        *types.Tags
+	IconID      types.ID
+
+	// プラン、省略(0または空)の場合はコピー元サーバのプラン
+	CPU        int `validate:"min=0"`
+	MemoryGB   int `validate:"min=0"`
+	GPU        int `validate:"min=0"`
+	Commitment types.ECommitment
+	Generation types.EPlanGeneration
+
+	// Disks コピー元サーバのディスクと同じ順でディスクごとのパラメータを指定する、省略した場合はコピー元ディスクと同じ設定
+	Disks []*CloneDiskRequest `validate:"omitempty,dive"`
+
+	// ShutdownSource trueの場合、一貫性のあるコピーを行うためにコピー元サーバをシャットダウンし、ディスクのコピー後に起動する
+	ShutdownSource   bool
+	ForceShutdown    bool
+	ShutdownStrategy *shutdown.Strategy // シャットダウンが必要な場合のシャットダウン方法、ForceShutdownとは同時に指定できない
+
+	BootAfterCreate bool
+}
+
+// CloneDiskRequest コピーするディスクごとのパラメータ
+type CloneDiskRequest struct {
+	Name          string `validate:"omitempty,min=1"` // 省略時はコピー元ディスクの名前
+	DiskPlanID    types.ID
+	SizeGB        int `validate:"min=0"`
+	Connection    types.EDiskConnection
+	EditParameter *diskService.EditParameter
+}
+
+func (req *CloneRequest) Validate() error {
+	if err := validate.New().Struct(req); err != nil {
+		return err
+	}
+	return validateShutdownStrategy(req.ShutdownStrategy, req.ForceShutdown)
+}
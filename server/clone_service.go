@@ -0,0 +1,173 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/power"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	diskBuilder "github.com/sacloud/iaas-service-go/disk/builder"
+	serverBuilder "github.com/sacloud/iaas-service-go/server/builder"
+	"github.com/sacloud/iaas-service-go/serviceutil"
+)
+
+// Clone 既存サーバと同じ構成のサーバを作成する
+//
+// コピー元サーバのディスクは全てコピーされ、NIC(接続先スイッチ/パケットフィルタ)とCD-ROMも同じ構成となる。
+// 作成に失敗した場合は作成済みのサーバ/ディスクを削除する
+func (s *Service) Clone(req *CloneRequest) (*iaas.Server, error) {
+	return s.CloneWithContext(context.Background(), req)
+}
+
+func (s *Service) CloneWithContext(ctx context.Context, req *CloneRequest) (_ *iaas.Server, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Clone", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Clone", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	serverOp := iaas.NewServerOp(s.caller)
+	source, err := serverOp.Read(ctx, req.Zone, req.ID)
+	if err != nil {
+		return nil, &service.Error{Op: "Clone", Resource: "Server", Zone: req.Zone, ID: req.ID, Err: err}
+	}
+	if len(req.Disks) > len(source.Disks) {
+		return nil, &service.Error{
+			Op: "Clone", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation,
+			Err: fmt.Errorf("too many Disks: source server has %d disk(s)", len(source.Disks)),
+		}
+	}
+
+	builder, err := s.cloneBuilder(ctx, req, source)
+	if err != nil {
+		return nil, &service.Error{Op: "Clone", Resource: "Server", Zone: req.Zone, ID: req.ID, Err: err}
+	}
+
+	if req.ShutdownSource && source.InstanceStatus.IsUp() {
+		if err := shutdownServer(ctx, serverOp, req.Zone, req.ID, req.ForceShutdown, req.ShutdownStrategy); err != nil {
+			return nil, err
+		}
+		defer func() {
+			// 複製の失敗原因がキャンセルの場合でも複製元は起動する
+			bootCtx := ctx
+			if bootCtx.Err() != nil {
+				bootCtx = context.Background()
+			}
+			if bootErr := power.BootServer(bootCtx, serverOp, req.Zone, req.ID); bootErr != nil {
+				err = errors.Join(err, bootErr)
+			}
+		}()
+	}
+
+	result, err := builder.Build(ctx, req.Zone)
+	if err != nil {
+		return nil, err
+	}
+	return serverOp.Read(ctx, req.Zone, result.ServerID)
+}
+
+func (s *Service) cloneBuilder(ctx context.Context, req *CloneRequest, source *iaas.Server) (*serverBuilder.Builder, error) {
+	builder, err := serverBuilder.BuilderFromResource(ctx, s.caller, req.Zone, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	builder.ServerID = types.ID(0)
+	builder.BootAfterCreate = req.BootAfterCreate
+	builder.RollbackOnFailure = true
+
+	if req.Name != "" {
+		builder.Name = req.Name
+	}
+	if req.Description != nil {
+		builder.Description = *req.Description
+	}
+	if req.Tags != nil {
+		builder.Tags = *req.Tags
+	}
+	if !req.IconID.IsEmpty() {
+		builder.IconID = req.IconID
+	}
+	if req.CPU > 0 {
+		builder.CPU = req.CPU
+	}
+	if req.MemoryGB > 0 {
+		builder.MemoryGB = req.MemoryGB
+	}
+	if req.GPU > 0 {
+		builder.GPU = req.GPU
+	}
+	if req.Commitment != types.ECommitment("") {
+		builder.Commitment = req.Commitment
+	}
+	if req.Generation != types.EPlanGeneration(0) {
+		builder.Generation = req.Generation
+	}
+
+	diskOp := iaas.NewDiskOp(s.caller)
+	builder.DiskBuilders = nil
+	for i, d := range source.Disks {
+		current, err := diskOp.Read(ctx, req.Zone, d.ID)
+		if err != nil {
+			return nil, err
+		}
+		db := &diskBuilder.FromDiskOrArchiveBuilder{
+			SourceDiskID: current.ID,
+			Name:         current.Name,
+			SizeGB:       current.GetSizeGB(),
+			PlanID:       current.DiskPlanID,
+			Connection:   current.Connection,
+			Description:  current.Description,
+			Tags:         current.Tags,
+			IconID:       current.IconID,
+			Client:       diskBuilder.NewBuildersAPIClient(s.caller),
+		}
+		if i < len(req.Disks) && req.Disks[i] != nil {
+			if err := applyCloneDiskRequest(db, req.Disks[i]); err != nil {
+				return nil, err
+			}
+		}
+		builder.DiskBuilders = append(builder.DiskBuilders, db)
+	}
+	return builder, nil
+}
+
+func applyCloneDiskRequest(db *diskBuilder.FromDiskOrArchiveBuilder, req *CloneDiskRequest) error {
+	if req.Name != "" {
+		db.Name = req.Name
+	}
+	if !req.DiskPlanID.IsEmpty() {
+		db.PlanID = req.DiskPlanID
+	}
+	if req.SizeGB > 0 {
+		db.SizeGB = req.SizeGB
+	}
+	if req.Connection != types.EDiskConnection("") {
+		db.Connection = req.Connection
+	}
+	if req.EditParameter != nil {
+		editParameter := &diskBuilder.EditRequest{}
+		if err := serviceutil.RequestConvertTo(req.EditParameter, editParameter); err != nil {
+			return err
+		}
+		db.EditParameter = editParameter.ToUnixDiskEditRequest()
+	}
+	return nil
+}
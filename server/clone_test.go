@@ -0,0 +1,84 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	diskService "github.com/sacloud/iaas-service-go/disk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Clone(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	zone := testutil.TestZone()
+	name := testutil.ResourceName("service-server-clone")
+
+	source, err := svc.Create(&CreateRequest{
+		Zone:     zone,
+		Name:     name,
+		Tags:     types.Tags{"tag1"},
+		CPU:      1,
+		MemoryGB: 2,
+		NetworkInterfaces: []*NetworkInterface{
+			{Upstream: "shared"},
+		},
+		Disks: []*diskService.ApplyRequest{
+			{Zone: zone, Name: name, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+		},
+		BootAfterCreate: true,
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: source.ID, Force: true, WithDisks: true}) // nolint
+
+	cloned, err := svc.Clone(&CloneRequest{
+		Zone:           zone,
+		ID:             source.ID,
+		Name:           name + "-clone",
+		MemoryGB:       4,
+		Disks:          []*CloneDiskRequest{{SizeGB: 40}},
+		ShutdownSource: true,
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: cloned.ID, Force: true, WithDisks: true}) // nolint
+
+	require.NotEqual(t, source.ID, cloned.ID)
+	require.Equal(t, name+"-clone", cloned.Name)
+	require.Equal(t, source.Tags, cloned.Tags)
+	require.Equal(t, 1, cloned.CPU)
+	require.Equal(t, 4, cloned.GetMemoryGB())
+	require.Len(t, cloned.Interfaces, 1)
+	require.Len(t, cloned.Disks, 1)
+	require.NotEqual(t, source.Disks[0].ID, cloned.Disks[0].ID)
+
+	clonedDisk, err := iaas.NewDiskOp(caller).Read(context.Background(), zone, cloned.Disks[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, 40, clonedDisk.GetSizeGB())
+	require.Equal(t, source.Disks[0].ID, clonedDisk.SourceDiskID)
+
+	// source server is booted again
+	read, err := svc.Read(&ReadRequest{Zone: zone, ID: source.ID})
+	require.NoError(t, err)
+	require.True(t, read.InstanceStatus.IsUp())
+
+	_, err = svc.Clone(&CloneRequest{Zone: zone, ID: source.ID, Disks: []*CloneDiskRequest{{}, {}}})
+	require.True(t, service.IsValidationError(err))
+}
@@ -0,0 +1,58 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// DeleteSnapshot スナップショットセットに属する全てのアーカイブを削除する
+func (s *Service) DeleteSnapshot(req *DeleteSnapshotRequest) error {
+	return s.DeleteSnapshotWithContext(context.Background(), req)
+}
+
+func (s *Service) DeleteSnapshotWithContext(ctx context.Context, req *DeleteSnapshotRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "DeleteSnapshot", Resource: "Server", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return &service.Error{Op: "DeleteSnapshot", Resource: "Server", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	archives, err := findSnapshotArchives(ctx, s.caller, req.Zone, req.SetID)
+	if err != nil {
+		return &service.Error{Op: "DeleteSnapshot", Resource: "Server", Zone: req.Zone, Err: err}
+	}
+	if len(archives) == 0 {
+		return &service.Error{
+			Op: "DeleteSnapshot", Resource: "Server", Zone: req.Zone, Kind: service.ErrorKindNotFound,
+			Err: fmt.Errorf("snapshot set %q not found", req.SetID),
+		}
+	}
+
+	archiveOp := iaas.NewArchiveOp(s.caller)
+	var errs []error
+	for _, archive := range archives {
+		if err := archiveOp.Delete(ctx, req.Zone, archive.ID); err != nil {
+			errs = append(errs, &service.Error{Op: "Delete", Resource: "Archive", Zone: req.Zone, ID: archive.ID, Err: err})
+		}
+	}
+	return errors.Join(errs...)
+}
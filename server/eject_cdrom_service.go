@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) EjectCDROM(req *EjectCDROMRequest) error {
 	return s.EjectCDROMWithContext(context.Background(), req)
 }
 
-func (s *Service) EjectCDROMWithContext(ctx context.Context, req *EjectCDROMRequest) error {
+func (s *Service) EjectCDROMWithContext(ctx context.Context, req *EjectCDROMRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "EjectCDROM", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "EjectCDROM", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewServerOp(s.caller)
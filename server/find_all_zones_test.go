@@ -0,0 +1,35 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_FindAllZones(t *testing.T) {
+	svc := New(testutil.SingletonAPICaller())
+
+	_, err := svc.FindAllZones(&FindAllZonesRequest{
+		Zones:       []string{testutil.TestZone()},
+		FindRequest: FindRequest{Names: []string{"not-exists"}},
+	})
+	require.NoError(t, err)
+
+	_, err = svc.FindAllZones(&FindAllZonesRequest{Parallelism: -1})
+	require.Error(t, err)
+}
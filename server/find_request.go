@@ -17,6 +17,8 @@ package server
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/objutil"
 	"github.com/sacloud/packages-go/validate"
@@ -28,6 +30,15 @@ type FindRequest struct {
 	Names []string `service:"-"`
 	Tags  []string `service:"-"`
 
+	Filter filter.Filter `service:"-"`
+
+	// SwitchID 指定のスイッチに接続されたNICを持つ(クライアント側で適用)
+	SwitchID types.ID `service:"-"`
+	// IPAddress 指定のIPアドレスを持つNICを持つ(クライアント側で適用)
+	//
+	// 共有セグメントのIPアドレスとスイッチ接続時のユーザーIPアドレスの両方が対象
+	IPAddress string `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -51,5 +62,38 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if !objutil.IsEmpty(req.Tags) {
 		condition.Filter[search.Key("Tags.Name")] = search.TagsAndEqual(req.Tags...)
 	}
+	filter.SetCondition[*iaas.Server](&req.Filter, condition)
 	return condition, nil
 }
+
+// matchInterfaces SwitchID/IPAddressの条件に一致するNICを持つか
+//
+// 両方指定した場合は同じNICが両方の条件に一致する必要がある
+func (req *FindRequest) matchInterfaces(server *iaas.Server) bool {
+	if req.SwitchID.IsEmpty() && req.IPAddress == "" {
+		return true
+	}
+	for _, nic := range server.Interfaces {
+		if !req.SwitchID.IsEmpty() && nic.SwitchID != req.SwitchID {
+			continue
+		}
+		if req.IPAddress != "" && nic.IPAddress != req.IPAddress && nic.UserIPAddress != req.IPAddress {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func (req *FindRequest) filterByInterfaces(servers []*iaas.Server) []*iaas.Server {
+	if req.SwitchID.IsEmpty() && req.IPAddress == "" {
+		return servers
+	}
+	var results []*iaas.Server
+	for _, server := range servers {
+		if req.matchInterfaces(server) {
+			results = append(results, server)
+		}
+	}
+	return results
+}
@@ -0,0 +1,82 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Find_interfaces(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	name := testutil.ResourceName("service-server-find")
+
+	switchOp := iaas.NewSwitchOp(caller)
+	sw, err := switchOp.Create(ctx, zone, &iaas.SwitchCreateRequest{Name: name})
+	require.NoError(t, err)
+	defer switchOp.Delete(ctx, zone, sw.ID) // nolint
+
+	var servers []*iaas.Server
+	for _, nics := range [][]*NetworkInterface{
+		{{Upstream: "shared"}, {Upstream: sw.ID.String(), UserIPAddress: "192.168.0.11"}},
+		{{Upstream: sw.ID.String(), UserIPAddress: "192.168.0.12"}},
+		{{Upstream: "shared"}},
+	} {
+		server, err := svc.Create(&CreateRequest{
+			Zone:              zone,
+			Name:              name,
+			CPU:               1,
+			MemoryGB:          1,
+			NetworkInterfaces: nics,
+		})
+		require.NoError(t, err)
+		defer svc.Delete(&DeleteRequest{Zone: zone, ID: server.ID, Force: true}) // nolint
+		servers = append(servers, server)
+	}
+
+	ids := func(req *FindRequest) []types.ID {
+		req.Zone = zone
+		req.Names = []string{name}
+
+		found, err := svc.Find(req)
+		require.NoError(t, err)
+		var results []types.ID
+		for _, v := range found {
+			results = append(results, v.ID)
+		}
+
+		var all []types.ID
+		err = svc.FindAll(req, func(v *iaas.Server) error {
+			all = append(all, v.ID)
+			return nil
+		})
+		require.NoError(t, err)
+		require.ElementsMatch(t, results, all)
+		return results
+	}
+
+	require.ElementsMatch(t, []types.ID{servers[0].ID, servers[1].ID}, ids(&FindRequest{SwitchID: sw.ID}))
+	require.ElementsMatch(t, []types.ID{servers[1].ID}, ids(&FindRequest{IPAddress: "192.168.0.12"}))
+	require.ElementsMatch(t, []types.ID{servers[0].ID}, ids(&FindRequest{SwitchID: sw.ID, IPAddress: "192.168.0.11"}))
+	require.Empty(t, ids(&FindRequest{SwitchID: sw.ID, IPAddress: "192.168.0.13"}))
+}
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) InsertCDROM(req *InsertCDROMRequest) error {
 	return s.InsertCDROMWithContext(context.Background(), req)
 }
 
-func (s *Service) InsertCDROMWithContext(ctx context.Context, req *InsertCDROMRequest) error {
+func (s *Service) InsertCDROMWithContext(ctx context.Context, req *InsertCDROMRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "InsertCDROM", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "InsertCDROM", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewServerOp(s.caller)
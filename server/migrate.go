@@ -0,0 +1,52 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"fmt"
+)
+
+// MigrationStage サーバのゾーン間移行の段階
+type MigrationStage string
+
+const (
+	// MigrationStageSnapshot 移行元ゾーンでのスナップショット作成
+	MigrationStageSnapshot = MigrationStage("snapshot")
+	// MigrationStageTransfer 移行先ゾーンへのアーカイブの転送
+	MigrationStageTransfer = MigrationStage("transfer")
+	// MigrationStageRestore 移行先ゾーンでのサーバの作成
+	MigrationStageRestore = MigrationStage("restore")
+	// MigrationStageBoot 移行先サーバの起動確認
+	MigrationStageBoot = MigrationStage("boot")
+	// MigrationStageCleanup 移行元サーバ/中間アーカイブの削除
+	MigrationStageCleanup = MigrationStage("cleanup")
+)
+
+// MigrationError 移行が途中で失敗した場合のエラー
+//
+// SetIDをMigrateRequest.SetIDに指定して再実行することで、完了済みの段階を飛ばして移行を再開できる
+type MigrationError struct {
+	SetID string
+	Stage MigrationStage
+	Err   error
+}
+
+func (e *MigrationError) Error() string {
+	return fmt.Sprintf("migration failed at %s stage (snapshot set: %q): %s", e.Stage, e.SetID, e.Err)
+}
+
+func (e *MigrationError) Unwrap() error {
+	return e.Err
+}
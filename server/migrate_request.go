@@ -0,0 +1,56 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
+	"github.com/sacloud/packages-go/validate"
+)
+
+// MigrateRequest サーバのゾーン間移行リクエスト
+type MigrateRequest struct {
+	Zone       string   `service:"-" validate:"required"`
+	ID         types.ID `service:"-" validate:"required"`
+	TargetZone string   `service:"-" validate:"required,nefield=Zone"`
+	// SetID 中断した移行を再開する場合に指定する、省略時は新たにスナップショットを作成する
+	SetID string `service:"-"`
+
+	// 以下は省略時は移行元サーバの値となる
+	Name        string `validate:"omitempty,min=1"`
+	Description string `validate:"min=0,max=512"`
+	Tags        types.Tags
+	IconID      types.ID
+
+	// SwitchIDs 移行元ゾーンのスイッチIDから移行先ゾーンのスイッチIDへの対応
+	SwitchIDs map[types.ID]types.ID
+	// PacketFilterIDs 移行元ゾーンのパケットフィルタIDから移行先ゾーンのパケットフィルタIDへの対応
+	PacketFilterIDs map[types.ID]types.ID
+	CDROMID         types.ID // 移行先ゾーンで挿入するISOイメージ
+	PrivateHostID   types.ID
+
+	ShutdownSource   bool // trueの場合、スナップショット作成前に移行元サーバを停止する(移行後も停止したままとなる)
+	ForceShutdown    bool
+	ShutdownStrategy *shutdown.Strategy // シャットダウンが必要な場合のシャットダウン方法、ForceShutdownとは同時に指定できない
+	DeleteSource     bool               // trueの場合、移行完了後に移行元サーバをディスクごと削除する
+	DeleteArchives   bool               // trueの場合、移行完了後に移行元/移行先ゾーンのアーカイブを削除する
+}
+
+func (req *MigrateRequest) Validate() error {
+	if err := validate.New().Struct(req); err != nil {
+		return err
+	}
+	return validateShutdownStrategy(req.ShutdownStrategy, req.ForceShutdown)
+}
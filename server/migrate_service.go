@@ -0,0 +1,362 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/power"
+	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	archiveBuilder "github.com/sacloud/iaas-service-go/archive/builder"
+)
+
+// Migrate サーバを別ゾーンへ移行する
+//
+// 移行元サーバの全ディスクのスナップショットを作成して移行先ゾーンへ転送し、
+// スイッチ/パケットフィルタのIDを対応付けたうえで移行先ゾーンにサーバを作成、起動を確認する。
+// 途中で失敗した場合は*MigrationErrorを含むエラーを返す、そのSetIDを指定して再実行すると完了済みの段階は飛ばされる
+func (s *Service) Migrate(req *MigrateRequest) (*iaas.Server, error) {
+	return s.MigrateWithContext(context.Background(), req)
+}
+
+func (s *Service) MigrateWithContext(ctx context.Context, req *MigrateRequest) (_ *iaas.Server, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Migrate", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Migrate", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	setID := req.SetID
+	stage := MigrationStageSnapshot
+	defer func() {
+		if err != nil {
+			err = &MigrationError{SetID: setID, Stage: stage, Err: err}
+		}
+	}()
+
+	// 再開時は移行元サーバが削除済みの場合がある
+	serverOp := iaas.NewServerOp(s.caller)
+	source, err := serverOp.Read(ctx, req.Zone, req.ID)
+	if err != nil && (setID == "" || !iaas.IsNotFoundError(err)) {
+		return nil, &service.Error{Op: "Migrate", Resource: "Server", Zone: req.Zone, ID: req.ID, Err: err}
+	}
+	if source != nil {
+		if _, err := migrateNetworkInterfaces(req, newSnapshotServer(source).NetworkInterfaces); err != nil {
+			return nil, &service.Error{Op: "Migrate", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+		}
+	}
+
+	if setID == "" {
+		if req.ShutdownSource && source.InstanceStatus.IsUp() {
+			if err := shutdownServer(ctx, serverOp, req.Zone, req.ID, req.ForceShutdown, req.ShutdownStrategy); err != nil {
+				return nil, err
+			}
+		}
+		snapshot, err := s.SnapshotWithContext(ctx, &SnapshotRequest{Zone: req.Zone, ID: req.ID})
+		if err != nil {
+			return nil, err
+		}
+		setID = snapshot.SetID
+	}
+
+	// クリーンアップの途中で中断された場合は、起動確認まで完了した移行先サーバが残っている
+	var target *iaas.Server
+	if req.SetID != "" {
+		target, err = s.findMigratedTarget(ctx, req.TargetZone, setID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if target == nil {
+		stage = MigrationStageTransfer
+		snapshot, err := s.transferSnapshot(ctx, req, setID)
+		if err != nil {
+			return nil, err
+		}
+
+		stage = MigrationStageRestore
+		target, err = s.restoreMigration(ctx, req, source, snapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		stage = MigrationStageBoot
+		if !target.InstanceStatus.IsUp() {
+			if err := power.BootServer(ctx, serverOp, req.TargetZone, target.ID); err != nil {
+				return nil, err
+			}
+			target, err = serverOp.Read(ctx, req.TargetZone, target.ID)
+			if err != nil {
+				return nil, err
+			}
+			if !target.InstanceStatus.IsUp() {
+				return nil, &service.Error{
+					Op: "Migrate", Resource: "Server", Zone: req.TargetZone, ID: target.ID,
+					Err: fmt.Errorf("server has not booted: instance status: %s", target.InstanceStatus),
+				}
+			}
+		}
+	}
+
+	stage = MigrationStageCleanup
+	if err := s.cleanupMigration(ctx, req, setID, target); err != nil {
+		return nil, err
+	}
+	return serverOp.Read(ctx, req.TargetZone, target.ID)
+}
+
+// transferSnapshot スナップショットセットのアーカイブのうち、移行先ゾーンに未転送のものを転送する
+//
+// 転送に失敗したアーカイブは削除して転送し直し、転送中のアーカイブは完了を待つ
+func (s *Service) transferSnapshot(ctx context.Context, req *MigrateRequest, setID string) (*Snapshot, error) {
+	archiveOp := iaas.NewArchiveOp(s.caller)
+	transferred, err := findSnapshotArchives(ctx, s.caller, req.TargetZone, setID)
+	if err != nil {
+		return nil, err
+	}
+	done := make(map[int]bool)
+	for _, archive := range transferred {
+		metadata, err := parseSnapshotMetadata(setID, archive)
+		if err != nil {
+			return nil, err
+		}
+		if !archive.Availability.IsAvailable() && !archive.Availability.IsFailed() {
+			id := archive.ID
+			state, err := iaas.WaiterForReady(func() (interface{}, error) {
+				return archiveOp.Read(ctx, req.TargetZone, id)
+			}).WaitForState(ctx)
+			if err == nil {
+				archive = state.(*iaas.Archive)
+			}
+		}
+		if archive.Availability.IsAvailable() {
+			done[metadata.Disk.Index] = true
+			continue
+		}
+		if err := archiveOp.Delete(ctx, req.TargetZone, archive.ID); err != nil {
+			return nil, fmt.Errorf("deleting archive[%s] failed: %w", archive.ID, err)
+		}
+	}
+
+	sources, err := findSnapshotArchives(ctx, s.caller, req.Zone, setID)
+	if err != nil {
+		return nil, err
+	}
+	var builders []*archiveBuilder.TransferArchiveBuilder
+	for _, archive := range sources {
+		metadata, err := parseSnapshotMetadata(setID, archive)
+		if err != nil {
+			return nil, err
+		}
+		if done[metadata.Disk.Index] {
+			continue
+		}
+		builders = append(builders, &archiveBuilder.TransferArchiveBuilder{
+			Name:              archive.Name,
+			Description:       archive.Description,
+			Tags:              archive.Tags,
+			IconID:            archive.IconID,
+			SourceArchiveID:   archive.ID,
+			SourceArchiveZone: req.Zone,
+			Client:            archiveBuilder.NewAPIClient(s.caller),
+		})
+	}
+
+	errs := make([]error, len(builders))
+	var wg sync.WaitGroup
+	for i, b := range builders {
+		wg.Add(1)
+		go func(i int, b *archiveBuilder.TransferArchiveBuilder) {
+			defer wg.Done()
+			_, errs[i] = b.Build(ctx, req.TargetZone)
+		}(i, b)
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return readSnapshot(ctx, s.caller, req.TargetZone, setID)
+}
+
+// restoreMigration 移行先ゾーンにサーバを作成する
+//
+// 作成したサーバには移行完了までスナップショットセットのタグが付与され、再開時にはそのサーバが利用される。
+// タグの付与されたサーバが全てのディスクを持たない場合は、作成途中で中断されたものとして削除し作成し直す
+func (s *Service) restoreMigration(ctx context.Context, req *MigrateRequest, source *iaas.Server, snapshot *Snapshot) (*iaas.Server, error) {
+	servers, err := iaas.NewServerOp(s.caller).Find(ctx, req.TargetZone, &iaas.FindCondition{
+		Filter: map[search.FilterKey]interface{}{
+			search.Key("Tags.Name"): search.TagsAndEqual(snapshot.Tag()),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, target := range servers.Servers {
+		restored, err := s.isRestoredFromSnapshot(ctx, req.TargetZone, target, snapshot)
+		if err != nil {
+			return nil, err
+		}
+		if restored {
+			return target, nil
+		}
+		// ディスクの作成途中で中断されたサーバは削除して作成し直す
+		err = s.DeleteWithContext(ctx, &DeleteRequest{Zone: req.TargetZone, ID: target.ID, Force: true, WithDisks: true})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	interfaces, err := migrateNetworkInterfaces(req, snapshot.Server.NetworkInterfaces)
+	if err != nil {
+		return nil, &service.Error{Op: "Migrate", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+	restoreReq := &RestoreRequest{
+		Zone:              req.TargetZone,
+		SetID:             snapshot.SetID,
+		Name:              req.Name,
+		Description:       req.Description,
+		Tags:              req.Tags,
+		IconID:            req.IconID,
+		PrivateHostID:     req.PrivateHostID,
+		NetworkInterfaces: interfaces,
+		CDROMID:           &req.CDROMID,
+	}
+	if source != nil {
+		if restoreReq.Name == "" {
+			restoreReq.Name = source.Name
+		}
+		if restoreReq.Description == "" {
+			restoreReq.Description = source.Description
+		}
+		if restoreReq.Tags == nil {
+			restoreReq.Tags = source.Tags
+		}
+		if restoreReq.IconID.IsEmpty() {
+			restoreReq.IconID = source.IconID
+		}
+	}
+	restoreReq.Tags = append(append(types.Tags{}, restoreReq.Tags...), snapshot.Tag())
+	return s.RestoreWithContext(ctx, restoreReq)
+}
+
+// isRestoredFromSnapshot serverがスナップショットセットの全アーカイブから作成したディスクを接続順に持つか
+func (s *Service) isRestoredFromSnapshot(ctx context.Context, zone string, server *iaas.Server, snapshot *Snapshot) (bool, error) {
+	if len(server.Disks) != len(snapshot.Disks) {
+		return false, nil
+	}
+	diskOp := iaas.NewDiskOp(s.caller)
+	for i, d := range server.Disks {
+		disk, err := diskOp.Read(ctx, zone, d.ID)
+		if err != nil {
+			return false, err
+		}
+		if disk.SourceArchiveID != snapshot.Disks[i].ArchiveID {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// findMigratedTarget 起動確認まで完了した移行先サーバを返す、存在しない場合はnilを返す
+//
+// 移行先サーバは起動確認の後にクリーンアップが完了するまでスナップショットセットのタグを持つため、
+// タグを持ち起動しているサーバを移行済みとみなす
+func (s *Service) findMigratedTarget(ctx context.Context, zone, setID string) (*iaas.Server, error) {
+	servers, err := iaas.NewServerOp(s.caller).Find(ctx, zone, &iaas.FindCondition{
+		Filter: map[search.FilterKey]interface{}{
+			search.Key("Tags.Name"): search.TagsAndEqual(snapshotTag(setID)),
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, server := range servers.Servers {
+		if server.InstanceStatus.IsUp() {
+			return server, nil
+		}
+	}
+	return nil, nil
+}
+
+// cleanupMigration 移行元サーバ/アーカイブを削除し、移行先サーバからスナップショットセットのタグを取り除く
+//
+// 再開時に移行先サーバを特定できるよう、タグは最後に取り除く。各段階は再実行されても失敗しない
+func (s *Service) cleanupMigration(ctx context.Context, req *MigrateRequest, setID string, target *iaas.Server) error {
+	if req.DeleteSource {
+		err := s.DeleteWithContext(ctx, &DeleteRequest{Zone: req.Zone, ID: req.ID, Force: true, WithDisks: true})
+		if err != nil && !service.IsNotFoundError(err) {
+			return err
+		}
+	}
+	if req.DeleteArchives {
+		for _, zone := range []string{req.Zone, req.TargetZone} {
+			err := s.DeleteSnapshotWithContext(ctx, &DeleteSnapshotRequest{Zone: zone, SetID: setID})
+			if err != nil && !service.IsNotFoundError(err) {
+				return err
+			}
+		}
+	}
+
+	var tags types.Tags
+	for _, tag := range target.Tags {
+		if tag != snapshotTag(setID) {
+			tags = append(tags, tag)
+		}
+	}
+	_, err := iaas.NewServerOp(s.caller).Update(ctx, req.TargetZone, target.ID, &iaas.ServerUpdateRequest{
+		Name:            target.Name,
+		Description:     target.Description,
+		Tags:            tags,
+		IconID:          target.IconID,
+		PrivateHostID:   target.PrivateHostID,
+		InterfaceDriver: target.InterfaceDriver,
+	})
+	return err
+}
+
+// migrateNetworkInterfaces NICの接続先スイッチ/パケットフィルタを移行先ゾーンのものに置き換える
+func migrateNetworkInterfaces(req *MigrateRequest, nics []*NetworkInterface) ([]*NetworkInterface, error) {
+	var results []*NetworkInterface
+	for _, nic := range nics {
+		migrated := &NetworkInterface{Upstream: nic.Upstream, UserIPAddress: nic.UserIPAddress}
+		switch nic.Upstream {
+		case "", "shared", "disconnected":
+		default:
+			switchID, ok := req.SwitchIDs[types.StringID(nic.Upstream)]
+			if !ok {
+				return nil, fmt.Errorf("switch[%s] is not mapped to the target zone", nic.Upstream)
+			}
+			migrated.Upstream = switchID.String()
+		}
+		if !nic.PacketFilterID.IsEmpty() {
+			packetFilterID, ok := req.PacketFilterIDs[nic.PacketFilterID]
+			if !ok {
+				return nil, fmt.Errorf("packet filter[%s] is not mapped to the target zone", nic.PacketFilterID)
+			}
+			migrated.PacketFilterID = packetFilterID
+		}
+		results = append(results, migrated)
+	}
+	return results, nil
+}
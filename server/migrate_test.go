@@ -0,0 +1,237 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	diskService "github.com/sacloud/iaas-service-go/disk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Migrate(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	targetZone := "is1a"
+	name := testutil.ResourceName("service-server-migrate")
+
+	switchOp := iaas.NewSwitchOp(caller)
+	sourceSwitch, err := switchOp.Create(ctx, zone, &iaas.SwitchCreateRequest{Name: name})
+	require.NoError(t, err)
+	defer switchOp.Delete(ctx, zone, sourceSwitch.ID) // nolint
+	targetSwitch, err := switchOp.Create(ctx, targetZone, &iaas.SwitchCreateRequest{Name: name})
+	require.NoError(t, err)
+	defer switchOp.Delete(ctx, targetZone, targetSwitch.ID) // nolint
+
+	source, err := svc.Create(&CreateRequest{
+		Zone:     zone,
+		Name:     name,
+		Tags:     types.Tags{"tag1"},
+		CPU:      1,
+		MemoryGB: 2,
+		NetworkInterfaces: []*NetworkInterface{
+			{Upstream: "shared"},
+			{Upstream: sourceSwitch.ID.String(), UserIPAddress: "192.168.0.11"},
+		},
+		Disks: []*diskService.ApplyRequest{
+			{Zone: zone, Name: name, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+		},
+		BootAfterCreate: true,
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: source.ID, Force: true, WithDisks: true}) // nolint
+
+	// switch mapping is required
+	_, err = svc.Migrate(&MigrateRequest{Zone: zone, ID: source.ID, TargetZone: targetZone})
+	require.True(t, service.IsValidationError(err))
+
+	// resume from the transfer stage
+	snapshot, err := svc.Snapshot(&SnapshotRequest{Zone: zone, ID: source.ID})
+	require.NoError(t, err)
+
+	migrated, err := svc.Migrate(&MigrateRequest{
+		Zone:           zone,
+		ID:             source.ID,
+		TargetZone:     targetZone,
+		SetID:          snapshot.SetID,
+		SwitchIDs:      map[types.ID]types.ID{sourceSwitch.ID: targetSwitch.ID},
+		ShutdownSource: true,
+		DeleteSource:   true,
+		DeleteArchives: true,
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: targetZone, ID: migrated.ID, Force: true, WithDisks: true}) // nolint
+
+	require.Equal(t, name, migrated.Name)
+	require.Equal(t, types.Tags{"tag1"}, migrated.Tags)
+	require.True(t, migrated.InstanceStatus.IsUp())
+	require.Len(t, migrated.Interfaces, 2)
+	require.Equal(t, targetSwitch.ID, migrated.Interfaces[1].SwitchID)
+	require.Len(t, migrated.Disks, 1)
+
+	_, err = svc.Read(&ReadRequest{Zone: zone, ID: source.ID})
+	require.True(t, service.IsNotFoundError(err))
+	for _, z := range []string{zone, targetZone} {
+		_, err = svc.ReadSnapshot(&ReadSnapshotRequest{Zone: z, SetID: snapshot.SetID})
+		require.True(t, service.IsNotFoundError(err))
+	}
+}
+
+func TestService_Migrate_resumePartialTarget(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	targetZone := "is1a"
+	name := testutil.ResourceName("service-server-migrate-resume")
+
+	source, err := svc.Create(&CreateRequest{
+		Zone:              zone,
+		Name:              name,
+		CPU:               1,
+		MemoryGB:          1,
+		NetworkInterfaces: []*NetworkInterface{{Upstream: "shared"}},
+		Disks: []*diskService.ApplyRequest{
+			{Zone: zone, Name: name, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+		},
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: source.ID, Force: true, WithDisks: true}) // nolint
+
+	snapshot, err := svc.Snapshot(&SnapshotRequest{Zone: zone, ID: source.ID})
+	require.NoError(t, err)
+
+	// ディスクの作成前に中断された移行先サーバ
+	partial, err := svc.Create(&CreateRequest{
+		Zone:     targetZone,
+		Name:     name,
+		CPU:      1,
+		MemoryGB: 1,
+		Tags:     types.Tags{snapshot.Tag()},
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: targetZone, ID: partial.ID, Force: true, WithDisks: true}) // nolint
+
+	migrated, err := svc.Migrate(&MigrateRequest{
+		Zone:           zone,
+		ID:             source.ID,
+		TargetZone:     targetZone,
+		SetID:          snapshot.SetID,
+		DeleteArchives: true,
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: targetZone, ID: migrated.ID, Force: true, WithDisks: true}) // nolint
+
+	require.NotEqual(t, partial.ID, migrated.ID)
+	require.Len(t, migrated.Disks, 1)
+	require.NotContains(t, migrated.Tags, snapshot.Tag())
+
+	_, err = iaas.NewServerOp(caller).Read(ctx, targetZone, partial.ID)
+	require.True(t, iaas.IsNotFoundError(err))
+}
+
+func TestService_Migrate_resumeCleanup(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	targetZone := "is1a"
+	name := testutil.ResourceName("service-server-migrate-cleanup")
+
+	source, err := svc.Create(&CreateRequest{
+		Zone:              zone,
+		Name:              name,
+		CPU:               1,
+		MemoryGB:          1,
+		NetworkInterfaces: []*NetworkInterface{{Upstream: "shared"}},
+		Disks: []*diskService.ApplyRequest{
+			{Zone: zone, Name: name, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+		},
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: source.ID, Force: true, WithDisks: true}) // nolint
+
+	snapshot, err := svc.Snapshot(&SnapshotRequest{Zone: zone, ID: source.ID})
+	require.NoError(t, err)
+	migrated, err := svc.Migrate(&MigrateRequest{Zone: zone, ID: source.ID, TargetZone: targetZone, SetID: snapshot.SetID})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: targetZone, ID: migrated.ID, Force: true, WithDisks: true}) // nolint
+
+	// 移行元ゾーンのアーカイブを削除した後、タグを取り除く前に中断された状態
+	_, err = iaas.NewServerOp(caller).Update(ctx, targetZone, migrated.ID, &iaas.ServerUpdateRequest{
+		Name:            migrated.Name,
+		Tags:            append(types.Tags{snapshot.Tag()}, migrated.Tags...),
+		InterfaceDriver: migrated.InterfaceDriver,
+	})
+	require.NoError(t, err)
+	require.NoError(t, svc.DeleteSnapshot(&DeleteSnapshotRequest{Zone: zone, SetID: snapshot.SetID}))
+
+	resumed, err := svc.Migrate(&MigrateRequest{
+		Zone:           zone,
+		ID:             source.ID,
+		TargetZone:     targetZone,
+		SetID:          snapshot.SetID,
+		DeleteSource:   true,
+		DeleteArchives: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, migrated.ID, resumed.ID)
+	require.NotContains(t, resumed.Tags, snapshot.Tag())
+
+	_, err = svc.Read(&ReadRequest{Zone: zone, ID: source.ID})
+	require.True(t, service.IsNotFoundError(err))
+	_, err = svc.ReadSnapshot(&ReadSnapshotRequest{Zone: targetZone, SetID: snapshot.SetID})
+	require.True(t, service.IsNotFoundError(err))
+
+	// 移行元サーバが削除済みでも再実行できる
+	_, err = iaas.NewServerOp(caller).Update(ctx, targetZone, migrated.ID, &iaas.ServerUpdateRequest{
+		Name:            resumed.Name,
+		Tags:            append(types.Tags{snapshot.Tag()}, resumed.Tags...),
+		InterfaceDriver: resumed.InterfaceDriver,
+	})
+	require.NoError(t, err)
+	resumed, err = svc.Migrate(&MigrateRequest{
+		Zone:           zone,
+		ID:             source.ID,
+		TargetZone:     targetZone,
+		SetID:          snapshot.SetID,
+		DeleteSource:   true,
+		DeleteArchives: true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, migrated.ID, resumed.ID)
+	require.NotContains(t, resumed.Tags, snapshot.Tag())
+}
+
+func TestMigrationError(t *testing.T) {
+	err := &service.Error{Op: "Migrate", Resource: "Server", Err: &MigrationError{
+		SetID: "123456789012-20230101000000",
+		Stage: MigrationStageTransfer,
+		Err:   &service.Error{Op: "Migrate", Resource: "Server", Kind: service.ErrorKindNotFound, Err: errors.New("not found")},
+	}}
+
+	var migrationErr *MigrationError
+	require.True(t, errors.As(err, &migrationErr))
+	require.Equal(t, MigrationStageTransfer, migrationErr.Stage)
+	require.True(t, service.IsNotFoundError(err))
+}
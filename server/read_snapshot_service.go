@@ -0,0 +1,84 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/archive"
+)
+
+// ReadSnapshot スナップショットセットに属するアーカイブを検索し、Snapshotを返す
+func (s *Service) ReadSnapshot(req *ReadSnapshotRequest) (*Snapshot, error) {
+	return s.ReadSnapshotWithContext(context.Background(), req)
+}
+
+func (s *Service) ReadSnapshotWithContext(ctx context.Context, req *ReadSnapshotRequest) (_ *Snapshot, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ReadSnapshot", Resource: "Server", Zone: req.Zone, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "ReadSnapshot", Resource: "Server", Zone: req.Zone, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	snapshot, err := readSnapshot(ctx, s.caller, req.Zone, req.SetID)
+	if err != nil {
+		return nil, &service.Error{Op: "ReadSnapshot", Resource: "Server", Zone: req.Zone, Err: err}
+	}
+	return snapshot, nil
+}
+
+// snapshotNotFoundError スナップショットセットに属するアーカイブが存在しないことを示すエラー
+type snapshotNotFoundError struct {
+	setID string
+}
+
+// Error errorインターフェースの実装
+func (e *snapshotNotFoundError) Error() string {
+	return fmt.Sprintf("snapshot set %q not found", e.setID)
+}
+
+// ErrorKind service.ErrorKinderの実装
+func (e *snapshotNotFoundError) ErrorKind() service.ErrorKind {
+	return service.ErrorKindNotFound
+}
+
+// readSnapshot スナップショットセットを読み込む
+//
+// 呼び出し元の操作としてラップできるよう、エラーはservice.Errorでラップせずに返す
+func readSnapshot(ctx context.Context, caller iaas.APICaller, zone, setID string) (*Snapshot, error) {
+	archives, err := findSnapshotArchives(ctx, caller, zone, setID)
+	if err != nil {
+		return nil, err
+	}
+	if len(archives) == 0 {
+		return nil, &snapshotNotFoundError{setID: setID}
+	}
+	return snapshotFromArchives(zone, setID, archives)
+}
+
+// findSnapshotArchives スナップショットセットに属するアーカイブを全て返す
+func findSnapshotArchives(ctx context.Context, caller iaas.APICaller, zone, setID string) ([]*iaas.Archive, error) {
+	var archives []*iaas.Archive
+	findReq := &archive.FindRequest{Zone: zone, Tags: []string{snapshotTag(setID)}}
+	err := archive.New(caller).FindAllWithContext(ctx, findReq, func(v *iaas.Archive) error {
+		archives = append(archives, v)
+		return nil
+	})
+	return archives, err
+}
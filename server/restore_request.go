@@ -0,0 +1,63 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"errors"
+
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
+	"github.com/sacloud/packages-go/validate"
+)
+
+// RestoreRequest スナップショットセットからのサーバのリストアリクエスト
+type RestoreRequest struct {
+	Zone  string `service:"-" validate:"required"`
+	SetID string `service:"-" validate:"required"`
+	// ID 指定した場合は既存サーバのディスクをスナップショットから作成したディスクに置き換える、省略時はサーバを新規作成する
+	ID types.ID `service:"-"`
+
+	// 以下はサーバを新規作成する場合のみ有効、Name/NetworkInterfaces/CDROMIDを省略した場合はスナップショット取得時の値となる
+	Name              string `validate:"omitempty,min=1"`
+	Description       string `validate:"min=0,max=512"`
+	Tags              types.Tags
+	IconID            types.ID
+	PrivateHostID     types.ID
+	NetworkInterfaces []*NetworkInterface `validate:"omitempty,dive"`
+	CDROMID           *types.ID           // 空のIDを指定した場合はCD-ROMを挿入しない
+
+	// 以下は既存サーバのディスクを置き換える場合のみ有効
+	ForceShutdown       bool
+	ShutdownStrategy    *shutdown.Strategy // シャットダウンが必要な場合のシャットダウン方法、ForceShutdownとは同時に指定できない
+	DeleteReplacedDisks bool               // trueの場合、置き換えられたディスクを削除する
+
+	// BootAfterRestore trueの場合はリストア後にサーバを起動する、既存サーバの場合は元々起動していた場合も起動する
+	BootAfterRestore bool
+}
+
+func (req *RestoreRequest) Validate() error {
+	if err := validate.New().Struct(req); err != nil {
+		return err
+	}
+	for i, nic := range req.NetworkInterfaces {
+		if err := nic.Validate(); err != nil {
+			return err
+		}
+		if i != 0 && nic.Upstream == "shared" {
+			return errors.New("upstream=shared is not supported for additional NICs")
+		}
+	}
+	return validateShutdownStrategy(req.ShutdownStrategy, req.ForceShutdown)
+}
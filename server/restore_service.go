@@ -0,0 +1,189 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/power"
+	"github.com/sacloud/iaas-api-go/ostype"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	diskService "github.com/sacloud/iaas-service-go/disk"
+	diskBuilder "github.com/sacloud/iaas-service-go/disk/builder"
+)
+
+// Restore スナップショットセットからサーバを復元する
+//
+// IDを指定した場合は既存サーバのディスクをスナップショットから作成したディスクに置き換え、
+// 省略した場合はスナップショット取得時のプラン/NICでサーバを新規作成する。
+// いずれの場合もディスクはスナップショット取得時の接続順/接続方式で作成される
+func (s *Service) Restore(req *RestoreRequest) (*iaas.Server, error) {
+	return s.RestoreWithContext(context.Background(), req)
+}
+
+func (s *Service) RestoreWithContext(ctx context.Context, req *RestoreRequest) (_ *iaas.Server, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Restore", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Restore", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	snapshot, err := readSnapshot(ctx, s.caller, req.Zone, req.SetID)
+	if err != nil {
+		return nil, &service.Error{Op: "Restore", Resource: "Server", Zone: req.Zone, ID: req.ID, Err: err}
+	}
+	if req.ID.IsEmpty() {
+		return s.restoreNew(ctx, req, snapshot)
+	}
+	return s.restoreInPlace(ctx, req, snapshot)
+}
+
+func (s *Service) restoreNew(ctx context.Context, req *RestoreRequest, snapshot *Snapshot) (*iaas.Server, error) {
+	applyReq := &ApplyRequest{
+		Zone:              req.Zone,
+		Name:              snapshot.Server.Name,
+		Description:       req.Description,
+		Tags:              req.Tags,
+		IconID:            req.IconID,
+		CPU:               snapshot.Server.CPU,
+		MemoryGB:          snapshot.Server.MemoryGB,
+		GPU:               snapshot.Server.GPU,
+		Commitment:        snapshot.Server.Commitment,
+		Generation:        snapshot.Server.Generation,
+		InterfaceDriver:   snapshot.Server.InterfaceDriver,
+		BootAfterCreate:   req.BootAfterRestore,
+		CDROMID:           snapshot.Server.CDROMID,
+		PrivateHostID:     req.PrivateHostID,
+		NetworkInterfaces: snapshot.Server.NetworkInterfaces,
+		RollbackOnFailure: true,
+	}
+	if req.Name != "" {
+		applyReq.Name = req.Name
+	}
+	if req.NetworkInterfaces != nil {
+		applyReq.NetworkInterfaces = req.NetworkInterfaces
+	}
+	if req.CDROMID != nil {
+		applyReq.CDROMID = *req.CDROMID
+	}
+	for _, d := range snapshot.Disks {
+		applyReq.Disks = append(applyReq.Disks, &diskService.ApplyRequest{
+			Zone:            req.Zone,
+			Name:            d.Name,
+			DiskPlanID:      d.DiskPlanID,
+			Connection:      d.Connection,
+			SourceArchiveID: d.ArchiveID,
+			SizeGB:          d.SizeGB,
+			OSType:          ostype.Custom,
+		})
+	}
+	return s.ApplyWithContext(ctx, applyReq)
+}
+
+func (s *Service) restoreInPlace(ctx context.Context, req *RestoreRequest, snapshot *Snapshot) (*iaas.Server, error) {
+	serverOp := iaas.NewServerOp(s.caller)
+	server, err := serverOp.Read(ctx, req.Zone, req.ID)
+	if err != nil {
+		return nil, &service.Error{Op: "Restore", Resource: "Server", Zone: req.Zone, ID: req.ID, Err: err}
+	}
+
+	running := server.InstanceStatus.IsUp()
+	if running {
+		if err := shutdownServer(ctx, serverOp, req.Zone, req.ID, req.ForceShutdown, req.ShutdownStrategy); err != nil {
+			return nil, err
+		}
+	}
+
+	diskOp := iaas.NewDiskOp(s.caller)
+	var replaced []types.ID
+	for _, d := range server.Disks {
+		if err := diskOp.DisconnectFromServer(ctx, req.Zone, d.ID); err != nil {
+			return nil, errors.Join(err, s.rollbackRestore(ctx, req.Zone, req.ID, nil, replaced, running))
+		}
+		replaced = append(replaced, d.ID)
+	}
+
+	var created []types.ID
+	for _, d := range snapshot.Disks {
+		builder := &diskBuilder.FromDiskOrArchiveBuilder{
+			SourceArchiveID: d.ArchiveID,
+			Name:            d.Name,
+			SizeGB:          d.SizeGB,
+			PlanID:          d.DiskPlanID,
+			Connection:      d.Connection,
+			Client:          diskBuilder.NewBuildersAPIClient(s.caller),
+		}
+		result, err := builder.Build(ctx, req.Zone, req.ID)
+		if result != nil && !result.DiskID.IsEmpty() {
+			created = append(created, result.DiskID)
+		}
+		if err != nil {
+			return nil, errors.Join(err, s.rollbackRestore(ctx, req.Zone, req.ID, created, replaced, running))
+		}
+	}
+
+	var errs []error
+	if req.DeleteReplacedDisks {
+		for _, id := range replaced {
+			if err := diskOp.Delete(ctx, req.Zone, id); err != nil {
+				errs = append(errs, &service.Error{Op: "Delete", Resource: "Disk", Zone: req.Zone, ID: id, Err: err})
+			}
+		}
+	}
+	if running || req.BootAfterRestore {
+		if err := power.BootServer(ctx, serverOp, req.Zone, req.ID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return serverOp.Read(ctx, req.Zone, req.ID)
+}
+
+// rollbackRestore 作成済みのディスクを削除し、置き換え前のディスクを元の順で再接続する
+func (s *Service) rollbackRestore(ctx context.Context, zone string, serverID types.ID, created, replaced []types.ID, boot bool) error {
+	// リストアの失敗原因がキャンセルの場合でも後始末は行う
+	if ctx.Err() != nil {
+		ctx = context.Background()
+	}
+	diskOp := iaas.NewDiskOp(s.caller)
+	var errs []error
+	for _, id := range created {
+		if err := diskOp.DisconnectFromServer(ctx, zone, id); err != nil {
+			errs = append(errs, fmt.Errorf("disconnecting disk[%s] failed: %w", id, err))
+			continue
+		}
+		if err := diskOp.Delete(ctx, zone, id); err != nil {
+			errs = append(errs, fmt.Errorf("deleting disk[%s] failed: %w", id, err))
+		}
+	}
+	for _, id := range replaced {
+		if err := diskOp.ConnectToServer(ctx, zone, id, serverID); err != nil {
+			errs = append(errs, fmt.Errorf("reconnecting disk[%s] failed: %w", id, err))
+		}
+	}
+	if boot && len(errs) == 0 {
+		if err := power.BootServer(ctx, iaas.NewServerOp(s.caller), zone, serverID); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,188 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"math"
+	"sort"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// RightsizeAction 推奨されるプラン変更の種類
+type RightsizeAction string
+
+const (
+	// RightsizeActionKeep 現在のプランのままとする
+	RightsizeActionKeep = RightsizeAction("keep")
+	// RightsizeActionDownsize より小さいプランへ変更する
+	RightsizeActionDownsize = RightsizeAction("downsize")
+	// RightsizeActionUpsize より大きいプランへ変更する
+	RightsizeActionUpsize = RightsizeAction("upsize")
+	// RightsizeActionResize コア数とメモリサイズの一方を減らし、もう一方を増やしたプランへ変更する
+	RightsizeActionResize = RightsizeAction("resize")
+)
+
+// RightsizeResult モニタリング値の統計と推奨プラン
+type RightsizeResult struct {
+	Zone string
+	ID   types.ID
+
+	CurrentPlan     *iaas.ServerPlan
+	RecommendedPlan *iaas.ServerPlan // 条件を満たすプランが存在しない場合はnil
+	Action          RightsizeAction
+
+	// CPU 使用しているコア数換算のCPU時間(モニタリングのCPU時間(ミリ秒)を1000で割った値)
+	CPU *UsageStats
+	// CPUUtilization 現在のコア数に対するCPU使用率(0-1)
+	CPUUtilization *UsageStats
+
+	Interfaces []*InterfaceUsage
+	Disks      []*DiskUsage
+
+	// ChangePlanRequest RightsizeRequest.WithChangePlanRequestがtrue、かつプラン変更が推奨される場合のみ設定される
+	ChangePlanRequest *ChangePlanRequest
+}
+
+// InterfaceUsage NICのトラフィック(bps)の統計
+type InterfaceUsage struct {
+	InterfaceID types.ID
+	Receive     *UsageStats
+	Send        *UsageStats
+}
+
+// DiskUsage ディスクのI/O(Bps)の統計
+type DiskUsage struct {
+	DiskID types.ID
+	Read   *UsageStats
+	Write  *UsageStats
+}
+
+// UsageStats モニタリング値の統計
+type UsageStats struct {
+	Samples    int
+	Average    float64
+	Max        float64
+	Percentile float64 // RightsizeRequest.Percentileで指定したパーセンタイル値
+}
+
+func newUsageStats(values []float64, p float64) *UsageStats {
+	stats := &UsageStats{Samples: len(values)}
+	if len(values) == 0 {
+		return stats
+	}
+
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	stats.Average = sum / float64(len(sorted))
+	stats.Max = sorted[len(sorted)-1]
+	stats.Percentile = percentile(sorted, p)
+	return stats
+}
+
+func (s *UsageStats) scale(v float64) *UsageStats {
+	return &UsageStats{
+		Samples:    s.Samples,
+		Average:    s.Average * v,
+		Max:        s.Max * v,
+		Percentile: s.Percentile * v,
+	}
+}
+
+// cpuTimeMillisPerCore 1コアを常に使用している場合のCPU時間(ミリ秒/秒)
+const cpuTimeMillisPerCore = 1000
+
+// cpuCores モニタリングのCPU時間(ミリ秒)をコア数換算の値に変換する
+func cpuCores(values []*iaas.MonitorCPUTimeValue) []float64 {
+	var cores []float64
+	for _, v := range values {
+		cores = append(cores, v.CPUTime/cpuTimeMillisPerCore)
+	}
+	return cores
+}
+
+// requiredCPU コア数換算の使用量のパーセンタイル値が目標使用率に収まるコア数を返す
+func requiredCPU(cores *UsageStats, targetUtilization float64) int {
+	return int(math.Max(1, math.Ceil(cores.Percentile/targetUtilization)))
+}
+
+// percentile ソート済みの値からpパーセンタイル値を線形補間で求める
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p / 100 * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(rank-float64(lower))
+}
+
+// recommendServerPlan 現在のプランと同じ世代/コミットメント/GPU数のプランのうち、
+// 指定のコア数とメモリサイズを満たす最小のプランを返す
+//
+// 満たすプランが存在しない場合はメモリサイズを満たす最大のプランを返す
+func recommendServerPlan(plans []*iaas.ServerPlan, current *iaas.ServerPlan, cpu, memoryGB int) *iaas.ServerPlan {
+	var candidates []*iaas.ServerPlan
+	for _, plan := range plans {
+		if plan.Generation != current.Generation || plan.Commitment != current.Commitment || plan.GPU != current.GPU {
+			continue
+		}
+		if plan.Availability != types.Availabilities.Unknown && !plan.Availability.IsAvailable() {
+			continue
+		}
+		if plan.GetMemoryGB() < memoryGB {
+			continue
+		}
+		candidates = append(candidates, plan)
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].CPU != candidates[j].CPU {
+			return candidates[i].CPU < candidates[j].CPU
+		}
+		return candidates[i].MemoryMB < candidates[j].MemoryMB
+	})
+	for _, plan := range candidates {
+		if plan.CPU >= cpu {
+			return plan
+		}
+	}
+	return candidates[len(candidates)-1]
+}
+
+func rightsizeAction(current, recommended *iaas.ServerPlan) RightsizeAction {
+	switch {
+	case recommended.CPU == current.CPU && recommended.MemoryMB == current.MemoryMB:
+		return RightsizeActionKeep
+	case recommended.CPU <= current.CPU && recommended.MemoryMB <= current.MemoryMB:
+		return RightsizeActionDownsize
+	case recommended.CPU >= current.CPU && recommended.MemoryMB >= current.MemoryMB:
+		return RightsizeActionUpsize
+	default:
+		return RightsizeActionResize
+	}
+}
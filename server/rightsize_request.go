@@ -0,0 +1,41 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"time"
+
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/packages-go/validate"
+)
+
+// RightsizeRequest モニタリング値からの推奨プラン算出リクエスト
+type RightsizeRequest struct {
+	Zone string   `service:"-" validate:"required"`
+	ID   types.ID `service:"-" validate:"required"`
+
+	Start time.Time // 省略時はEndの7日前
+	End   time.Time // 省略時は現在時刻
+
+	Percentile        float64 `validate:"omitempty,gt=0,lte=100"` // 推奨プランの算出に用いるCPU時間のパーセンタイル、省略時は95
+	TargetUtilization float64 `validate:"omitempty,gt=0,lte=1"`   // パーセンタイル値がこの使用率に収まるコア数を推奨する、省略時は0.7
+	MemoryGB          int     `validate:"omitempty,min=1"`        // 推奨プランに必要なメモリサイズ、省略時は現在のメモリサイズ
+
+	WithChangePlanRequest bool // trueの場合、プラン変更が推奨される場合にChangePlanRequestを組み立てる
+}
+
+func (req *RightsizeRequest) Validate() error {
+	return validate.New().Struct(req)
+}
@@ -0,0 +1,187 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+	diskService "github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/serverplan"
+	"github.com/sacloud/iaas-service-go/serviceutil"
+)
+
+const (
+	defaultRightsizePeriod            = 7 * 24 * time.Hour
+	defaultRightsizePercentile        = 95
+	defaultRightsizeTargetUtilization = 0.7
+)
+
+// Rightsize 指定期間のモニタリング値から、同じ世代/コミットメントのサーバプランのうち適切なものを推奨する
+//
+// モニタリングのCPU時間はミリ秒単位(1コアを常に使用している場合に約1000)のため、1000で割ってコア数換算の使用量とし、そのパーセンタイル値が目標使用率に収まるコア数のプランを選ぶ。
+// NIC/ディスクのモニタリング値は判断材料として統計のみを返す
+func (s *Service) Rightsize(req *RightsizeRequest) (*RightsizeResult, error) {
+	return s.RightsizeWithContext(context.Background(), req)
+}
+
+func (s *Service) RightsizeWithContext(ctx context.Context, req *RightsizeRequest) (_ *RightsizeResult, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Rightsize", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Rightsize", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	server, err := iaas.NewServerOp(s.caller).Read(ctx, req.Zone, req.ID)
+	if err != nil {
+		return nil, &service.Error{Op: "Rightsize", Resource: "Server", Zone: req.Zone, ID: req.ID, Err: err}
+	}
+
+	end := req.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	start := req.Start
+	if start.IsZero() {
+		start = end.Add(-defaultRightsizePeriod)
+	}
+	cond, err := serviceutil.MonitorCondition(start, end)
+	if err != nil {
+		return nil, &service.Error{Op: "Rightsize", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+	p := req.Percentile
+	if p == 0 {
+		p = defaultRightsizePercentile
+	}
+
+	result := &RightsizeResult{
+		Zone: req.Zone,
+		ID:   req.ID,
+		CurrentPlan: &iaas.ServerPlan{
+			ID:         server.ServerPlanID,
+			Name:       server.ServerPlanName,
+			CPU:        server.CPU,
+			MemoryMB:   server.MemoryMB,
+			GPU:        server.GPU,
+			Commitment: server.ServerPlanCommitment,
+			Generation: server.ServerPlanGeneration,
+		},
+	}
+
+	cpuValues, err := s.MonitorCPUWithContext(ctx, &MonitorCPURequest{Zone: req.Zone, ID: req.ID, Start: cond.Start, End: cond.End})
+	if err != nil {
+		return nil, err
+	}
+	cores := cpuCores(cpuValues)
+	if len(cores) == 0 {
+		return nil, &service.Error{
+			Op: "Rightsize", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindNotFound,
+			Err: errors.New("no CPU monitoring values in the period"),
+		}
+	}
+	result.CPU = newUsageStats(cores, p)
+	result.CPUUtilization = result.CPU.scale(1 / float64(server.CPU))
+
+	if err := s.collectInterfaceUsages(ctx, req, server, cond, p, result); err != nil {
+		return nil, err
+	}
+	if err := s.collectDiskUsages(ctx, req, server, cond, p, result); err != nil {
+		return nil, err
+	}
+
+	var plans []*iaas.ServerPlan
+	err = serverplan.New(s.caller).FindAllWithContext(ctx, &serverplan.FindRequest{Zone: req.Zone}, func(v *iaas.ServerPlan) error {
+		plans = append(plans, v)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	targetUtilization := req.TargetUtilization
+	if targetUtilization == 0 {
+		targetUtilization = defaultRightsizeTargetUtilization
+	}
+	cpu := requiredCPU(result.CPU, targetUtilization)
+	memoryGB := req.MemoryGB
+	if memoryGB == 0 {
+		memoryGB = server.GetMemoryGB()
+	}
+
+	result.RecommendedPlan = recommendServerPlan(plans, result.CurrentPlan, cpu, memoryGB)
+	if result.RecommendedPlan == nil {
+		result.Action = RightsizeActionKeep
+		return result, nil
+	}
+	result.Action = rightsizeAction(result.CurrentPlan, result.RecommendedPlan)
+
+	if req.WithChangePlanRequest && result.Action != RightsizeActionKeep {
+		result.ChangePlanRequest = &ChangePlanRequest{
+			Zone:                 req.Zone,
+			ID:                   req.ID,
+			CPU:                  result.RecommendedPlan.CPU,
+			MemoryMB:             result.RecommendedPlan.MemoryMB,
+			ServerPlanCommitment: result.RecommendedPlan.Commitment,
+			ServerPlanGeneration: result.RecommendedPlan.Generation,
+		}
+	}
+	return result, nil
+}
+
+func (s *Service) collectInterfaceUsages(ctx context.Context, req *RightsizeRequest, server *iaas.Server, cond *iaas.MonitorCondition, p float64, result *RightsizeResult) error {
+	interfaceOp := iaas.NewInterfaceOp(s.caller)
+	for _, iface := range server.Interfaces {
+		activity, err := interfaceOp.Monitor(ctx, req.Zone, iface.ID, cond)
+		if err != nil {
+			return &service.Error{Op: "Monitor", Resource: "Interface", Zone: req.Zone, ID: iface.ID, Err: err}
+		}
+		var receive, send []float64
+		for _, v := range activity.Values {
+			receive = append(receive, v.Receive)
+			send = append(send, v.Send)
+		}
+		result.Interfaces = append(result.Interfaces, &InterfaceUsage{
+			InterfaceID: iface.ID,
+			Receive:     newUsageStats(receive, p),
+			Send:        newUsageStats(send, p),
+		})
+	}
+	return nil
+}
+
+func (s *Service) collectDiskUsages(ctx context.Context, req *RightsizeRequest, server *iaas.Server, cond *iaas.MonitorCondition, p float64, result *RightsizeResult) error {
+	diskSvc := diskService.New(s.caller)
+	for _, disk := range server.Disks {
+		values, err := diskSvc.MonitorDiskWithContext(ctx, &diskService.MonitorDiskRequest{Zone: req.Zone, ID: disk.ID, Start: cond.Start, End: cond.End})
+		if err != nil {
+			return err
+		}
+		var read, write []float64
+		for _, v := range values {
+			read = append(read, v.Read)
+			write = append(write, v.Write)
+		}
+		result.Disks = append(result.Disks, &DiskUsage{
+			DiskID: disk.ID,
+			Read:   newUsageStats(read, p),
+			Write:  newUsageStats(write, p),
+		})
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	diskService "github.com/sacloud/iaas-service-go/disk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Rightsize(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	zone := testutil.TestZone()
+	name := testutil.ResourceName("service-server-rightsize")
+
+	server, err := svc.Create(&CreateRequest{
+		Zone:     zone,
+		Name:     name,
+		CPU:      2,
+		MemoryGB: 4,
+		NetworkInterfaces: []*NetworkInterface{
+			{Upstream: "shared"},
+		},
+		Disks: []*diskService.ApplyRequest{
+			{Zone: zone, Name: name, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+		},
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: server.ID, Force: true, WithDisks: true}) // nolint
+
+	result, err := svc.Rightsize(&RightsizeRequest{Zone: zone, ID: server.ID, WithChangePlanRequest: true})
+	require.NoError(t, err)
+
+	require.Equal(t, 2, result.CurrentPlan.CPU)
+	require.NotZero(t, result.CPU.Samples)
+	require.Len(t, result.Interfaces, 1)
+	require.Len(t, result.Disks, 1)
+	require.InDelta(t, result.CPU.Percentile/2, result.CPUUtilization.Percentile, 1e-9)
+
+	// fake server plans have no plan with 4GB memory
+	require.Nil(t, result.RecommendedPlan)
+	require.Equal(t, RightsizeActionKeep, result.Action)
+	require.Nil(t, result.ChangePlanRequest)
+}
+
+func TestPercentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5}
+	require.Equal(t, 1.0, percentile(values, 0))
+	require.Equal(t, 3.0, percentile(values, 50))
+	require.Equal(t, 4.8, percentile(values, 95))
+	require.Equal(t, 5.0, percentile(values, 100))
+
+	stats := newUsageStats([]float64{4, 1, 3, 2}, 50)
+	require.Equal(t, &UsageStats{Samples: 4, Average: 2.5, Max: 4, Percentile: 2.5}, stats)
+}
+
+func TestRecommendServerPlan(t *testing.T) {
+	plan := func(id int64, cpu, memoryGB int, commitment types.ECommitment, generation types.EPlanGeneration) *iaas.ServerPlan {
+		return &iaas.ServerPlan{
+			ID:           types.ID(id),
+			CPU:          cpu,
+			MemoryMB:     memoryGB * 1024,
+			Commitment:   commitment,
+			Generation:   generation,
+			Availability: types.Availabilities.Available,
+		}
+	}
+	plans := []*iaas.ServerPlan{
+		plan(1, 1, 2, types.Commitments.Standard, types.PlanGenerations.G200),
+		plan(2, 2, 4, types.Commitments.Standard, types.PlanGenerations.G200),
+		plan(3, 4, 4, types.Commitments.Standard, types.PlanGenerations.G200),
+		plan(4, 4, 8, types.Commitments.Standard, types.PlanGenerations.G200),
+		plan(5, 2, 4, types.Commitments.DedicatedCPU, types.PlanGenerations.G200),
+		plan(6, 2, 4, types.Commitments.Standard, types.PlanGenerations.G100),
+	}
+	current := plan(4, 4, 8, types.Commitments.Standard, types.PlanGenerations.G200)
+
+	cases := []struct {
+		cpu, memoryGB int
+		expectID      types.ID
+		expectAction  RightsizeAction
+	}{
+		{cpu: 1, memoryGB: 2, expectID: 1, expectAction: RightsizeActionDownsize},
+		{cpu: 2, memoryGB: 4, expectID: 2, expectAction: RightsizeActionDownsize},
+		{cpu: 3, memoryGB: 4, expectID: 3, expectAction: RightsizeActionDownsize},
+		{cpu: 4, memoryGB: 8, expectID: 4, expectAction: RightsizeActionKeep},
+		{cpu: 16, memoryGB: 4, expectID: 4, expectAction: RightsizeActionKeep}, // largest plan
+	}
+	for _, tc := range cases {
+		recommended := recommendServerPlan(plans, current, tc.cpu, tc.memoryGB)
+		require.Equal(t, tc.expectID, recommended.ID)
+		require.Equal(t, tc.expectAction, rightsizeAction(current, recommended))
+	}
+
+	require.Nil(t, recommendServerPlan(plans, current, 1, 16))
+	require.Equal(t, RightsizeActionUpsize, rightsizeAction(plans[0], current))
+	// fewer cores but more memory
+	require.Equal(t, RightsizeActionResize, rightsizeAction(plans[2], plan(7, 2, 8, types.Commitments.Standard, types.PlanGenerations.G200)))
+	require.Equal(t, RightsizeActionResize, rightsizeAction(plans[3], plan(8, 8, 4, types.Commitments.Standard, types.PlanGenerations.G200)))
+}
+
+func TestRightsize_CPUTime(t *testing.T) {
+	plans := []*iaas.ServerPlan{
+		{ID: 1, CPU: 1, MemoryMB: 4 * 1024, Commitment: types.Commitments.Standard, Generation: types.PlanGenerations.G200},
+		{ID: 2, CPU: 2, MemoryMB: 4 * 1024, Commitment: types.Commitments.Standard, Generation: types.PlanGenerations.G200},
+		{ID: 3, CPU: 4, MemoryMB: 4 * 1024, Commitment: types.Commitments.Standard, Generation: types.PlanGenerations.G200},
+		{ID: 4, CPU: 8, MemoryMB: 4 * 1024, Commitment: types.Commitments.Standard, Generation: types.PlanGenerations.G200},
+	}
+	current := plans[3]
+
+	cases := []struct {
+		name     string
+		cpuTimes []float64 // ミリ秒
+		expectID types.ID
+	}{
+		{name: "idle", cpuTimes: []float64{50, 100, 150, 200}, expectID: 1},
+		{name: "one core", cpuTimes: []float64{500, 800, 1000, 1200}, expectID: 2},
+		{name: "two cores", cpuTimes: []float64{1500, 2000, 2500, 2500}, expectID: 3},
+		{name: "saturated", cpuTimes: []float64{8000, 8000, 8000, 8000}, expectID: 4},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var values []*iaas.MonitorCPUTimeValue
+			for _, v := range tc.cpuTimes {
+				values = append(values, &iaas.MonitorCPUTimeValue{CPUTime: v})
+			}
+			stats := newUsageStats(cpuCores(values), 100)
+			require.Equal(t, tc.cpuTimes[len(tc.cpuTimes)-1]/1000, stats.Max)
+
+			recommended := recommendServerPlan(plans, current, requiredCPU(stats, defaultRightsizeTargetUtilization), 4)
+			require.Equal(t, tc.expectID, recommended.ID)
+		})
+	}
+}
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) SendNMI(req *SendNMIRequest) error {
 	return s.SendNMIWithContext(context.Background(), req)
 }
 
-func (s *Service) SendNMIWithContext(ctx context.Context, req *SendNMIRequest) error {
+func (s *Service) SendNMIWithContext(ctx context.Context, req *SendNMIRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "SendNMI", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "SendNMI", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewServerOp(s.caller)
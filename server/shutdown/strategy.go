@@ -0,0 +1,158 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/power"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/progress"
+)
+
+// DefaultNMITimeout NMI送信後に停止を待つデフォルトの時間
+const DefaultNMITimeout = time.Minute
+
+// ServerAPI シャットダウンで利用するAPI
+type ServerAPI interface {
+	power.ServerAPI
+}
+
+// NMISender NMI送信のためのインターフェース
+//
+// Strategy.SendNMIがtrueでもServerAPIがNMISenderを実装していない場合はNMI送信を行わずに強制停止する
+type NMISender interface {
+	SendNMI(ctx context.Context, zone string, id types.ID) error
+}
+
+// Stage シャットダウンが完了した段階
+type Stage string
+
+const (
+	// StageAlreadyDown 開始時点で停止済み
+	StageAlreadyDown = Stage("already-down")
+	// StageACPI ACPIによるシャットダウン
+	StageACPI = Stage("acpi")
+	// StageNMI NMI送信
+	StageNMI = Stage("nmi")
+	// StageForce 強制停止
+	StageForce = Stage("force")
+)
+
+// Strategy 段階的なシャットダウンの設定
+//
+// ACPIによるシャットダウンを行いTimeoutまで停止を待つ。停止しない場合はSendNMIがtrueであればNMIを送信してNMITimeoutまで待ち、
+// それでも停止しない場合は強制停止する
+type Strategy struct {
+	Timeout    time.Duration // ACPIシャットダウン後に停止を待つ時間
+	SendNMI    bool
+	NMITimeout time.Duration // NMI送信後に停止を待つ時間、省略時はDefaultNMITimeout
+}
+
+// Validate 設定値の検証
+func (s *Strategy) Validate() error {
+	if s.Timeout <= 0 {
+		return errors.New("Timeout must be greater than 0")
+	}
+	if s.NMITimeout < 0 {
+		return errors.New("NMITimeout must not be negative")
+	}
+	return nil
+}
+
+// Shutdown サーバをシャットダウンし、停止した段階を返す、エラーの場合は失敗した段階を返す
+func (s *Strategy) Shutdown(ctx context.Context, client ServerAPI, zone string, id types.ID) (Stage, error) {
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	server, err := client.Read(ctx, zone, id)
+	if err != nil {
+		return "", err
+	}
+	if server.InstanceStatus.IsDown() {
+		return StageAlreadyDown, nil
+	}
+
+	resource := progress.Resource{Kind: "Server", Zone: zone, ID: id}
+
+	err = withTimeout(ctx, s.Timeout, func(ctx context.Context) error {
+		err := power.ShutdownServer(ctx, client, zone, id, false)
+		if err != nil && ctx.Err() == nil && errors.Is(err, context.DeadlineExceeded) {
+			// power.ShutdownServerはDefaultStatePollingTimeoutで待機を打ち切るため、Timeoutまで停止を待つ
+			return waitForDown(ctx, client, zone, id, s.Timeout)
+		}
+		return err
+	})
+	if !errors.Is(err, errTimedOut) {
+		return StageACPI, err
+	}
+
+	if sender, ok := client.(NMISender); ok && s.SendNMI {
+		timeout := s.NMITimeout
+		if timeout == 0 {
+			timeout = DefaultNMITimeout
+		}
+		done := progress.StartPhase(ctx, resource, progress.PhaseSendNMI)
+		err = withTimeout(ctx, timeout, func(ctx context.Context) error {
+			if err := sender.SendNMI(ctx, zone, id); err != nil {
+				return err
+			}
+			return waitForDown(ctx, client, zone, id, timeout)
+		})
+		done(err)
+		if !errors.Is(err, errTimedOut) {
+			return StageNMI, err
+		}
+	}
+
+	done := progress.StartPhase(ctx, resource, progress.PhaseForceShutdown)
+	err = power.ShutdownServer(ctx, client, zone, id, true)
+	done(err)
+	return StageForce, err
+}
+
+// waitForDown サーバの停止をtimeoutまで待つ
+//
+// WaiterForDownはタイムアウトを省略するとDefaultStatePollingTimeoutで待機を打ち切るため、timeoutを指定する
+func waitForDown(ctx context.Context, client ServerAPI, zone string, id types.ID, timeout time.Duration) error {
+	waiter := iaas.WaiterForDown(func() (interface{}, error) {
+		return client.Read(ctx, zone, id)
+	})
+	if w, ok := waiter.(*iaas.StatePollingWaiter); ok {
+		w.Timeout = timeout
+	}
+	_, err := waiter.WaitForState(ctx)
+	return err
+}
+
+var errTimedOut = errors.New("timed out")
+
+// withTimeout タイムアウト付きでfnを実行する
+//
+// 呼び出し元のctxがキャンセルされておらずタイムアウトした場合はerrTimedOutを返す
+func withTimeout(ctx context.Context, timeout time.Duration, fn func(ctx context.Context) error) error {
+	stepCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(stepCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(stepCtx.Err(), context.DeadlineExceeded) {
+		return errTimedOut
+	}
+	return err
+}
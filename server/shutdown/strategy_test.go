@@ -0,0 +1,226 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package shutdown
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/defaults"
+	"github.com/sacloud/iaas-api-go/helper/power"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/progress"
+	"github.com/stretchr/testify/require"
+)
+
+type dummyServerAPI struct {
+	mu       sync.Mutex
+	status   types.EServerInstanceStatus
+	acpiHang bool
+	nmiHang  bool
+	calls    map[Stage]int
+}
+
+func newDummyServerAPI(status types.EServerInstanceStatus, acpiHang, nmiHang bool) *dummyServerAPI {
+	return &dummyServerAPI{status: status, acpiHang: acpiHang, nmiHang: nmiHang, calls: make(map[Stage]int)}
+}
+
+func (d *dummyServerAPI) Read(ctx context.Context, zone string, id types.ID) (*iaas.Server, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return &iaas.Server{ID: id, Availability: types.Availabilities.Available, InstanceStatus: d.status}, nil
+}
+
+func (d *dummyServerAPI) Boot(ctx context.Context, zone string, id types.ID) error {
+	return nil
+}
+
+func (d *dummyServerAPI) BootWithVariables(ctx context.Context, zone string, id types.ID, param *iaas.ServerBootVariables) error {
+	return nil
+}
+
+func (d *dummyServerAPI) Shutdown(ctx context.Context, zone string, id types.ID, shutdownOption *iaas.ShutdownOption) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if shutdownOption.Force {
+		d.calls[StageForce]++
+		d.status = types.ServerInstanceStatuses.Down
+		return nil
+	}
+	d.calls[StageACPI]++
+	if !d.acpiHang {
+		d.status = types.ServerInstanceStatuses.Down
+	}
+	return nil
+}
+
+func (d *dummyServerAPI) SendNMI(ctx context.Context, zone string, id types.ID) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls[StageNMI]++
+	if !d.nmiHang {
+		d.status = types.ServerInstanceStatuses.Down
+	}
+	return nil
+}
+
+// noNMIServerAPI NMISenderを実装しないServerAPI
+type noNMIServerAPI struct {
+	power.ServerAPI
+}
+
+func TestStrategy_Shutdown(t *testing.T) {
+	initialRequestRetrySpan, shutdownRetrySpan, pollingInterval := power.InitialRequestRetrySpan, power.ShutdownRetrySpan, defaults.DefaultStatePollingInterval
+	power.InitialRequestRetrySpan = time.Millisecond
+	power.ShutdownRetrySpan = 10 * time.Millisecond
+	defaults.DefaultStatePollingInterval = 10 * time.Millisecond
+	defer func() {
+		power.InitialRequestRetrySpan, power.ShutdownRetrySpan, defaults.DefaultStatePollingInterval = initialRequestRetrySpan, shutdownRetrySpan, pollingInterval
+	}()
+
+	cases := []struct {
+		name          string
+		client        *dummyServerAPI
+		withoutNMI    bool
+		strategy      *Strategy
+		expectStage   Stage
+		expectCalls   []Stage
+		expectPhases  []progress.Phase
+		expectFailure bool
+	}{
+		{
+			name:        "already down",
+			client:      newDummyServerAPI(types.ServerInstanceStatuses.Down, false, false),
+			strategy:    &Strategy{Timeout: time.Second},
+			expectStage: StageAlreadyDown,
+		},
+		{
+			name:        "acpi",
+			client:      newDummyServerAPI(types.ServerInstanceStatuses.Up, false, false),
+			strategy:    &Strategy{Timeout: time.Second, SendNMI: true},
+			expectStage: StageACPI,
+			expectCalls: []Stage{StageACPI},
+		},
+		{
+			name:         "nmi",
+			client:       newDummyServerAPI(types.ServerInstanceStatuses.Up, true, false),
+			strategy:     &Strategy{Timeout: 100 * time.Millisecond, SendNMI: true, NMITimeout: time.Second},
+			expectStage:  StageNMI,
+			expectCalls:  []Stage{StageACPI, StageNMI},
+			expectPhases: []progress.Phase{progress.PhaseSendNMI},
+		},
+		{
+			name:         "force without nmi",
+			client:       newDummyServerAPI(types.ServerInstanceStatuses.Up, true, false),
+			strategy:     &Strategy{Timeout: 100 * time.Millisecond},
+			expectStage:  StageForce,
+			expectCalls:  []Stage{StageACPI, StageForce},
+			expectPhases: []progress.Phase{progress.PhaseForceShutdown},
+		},
+		{
+			name:         "force without nmi sender",
+			client:       newDummyServerAPI(types.ServerInstanceStatuses.Up, true, false),
+			withoutNMI:   true,
+			strategy:     &Strategy{Timeout: 100 * time.Millisecond, SendNMI: true},
+			expectStage:  StageForce,
+			expectCalls:  []Stage{StageACPI, StageForce},
+			expectPhases: []progress.Phase{progress.PhaseForceShutdown},
+		},
+		{
+			name:         "force after nmi",
+			client:       newDummyServerAPI(types.ServerInstanceStatuses.Up, true, true),
+			strategy:     &Strategy{Timeout: 100 * time.Millisecond, SendNMI: true, NMITimeout: 100 * time.Millisecond},
+			expectStage:  StageForce,
+			expectCalls:  []Stage{StageACPI, StageNMI, StageForce},
+			expectPhases: []progress.Phase{progress.PhaseSendNMI, progress.PhaseForceShutdown},
+		},
+		{
+			name:          "invalid strategy",
+			client:        newDummyServerAPI(types.ServerInstanceStatuses.Up, false, false),
+			strategy:      &Strategy{},
+			expectFailure: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var phases []progress.Phase
+			ctx := progress.WithObserver(context.Background(), progress.ObserverFunc(func(ctx context.Context, event progress.Event) {
+				if e, ok := event.(*progress.PhaseStarted); ok {
+					phases = append(phases, e.Phase)
+				}
+			}))
+
+			var client ServerAPI = tc.client
+			if tc.withoutNMI {
+				client = &noNMIServerAPI{ServerAPI: tc.client}
+			}
+			stage, err := tc.strategy.Shutdown(ctx, client, "is1a", types.ID(1))
+			if tc.expectFailure {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectStage, stage)
+			require.Equal(t, tc.expectPhases, phases)
+
+			var calls []Stage
+			for _, s := range []Stage{StageACPI, StageNMI, StageForce} {
+				if tc.client.calls[s] > 0 {
+					calls = append(calls, s)
+				}
+			}
+			require.Equal(t, tc.expectCalls, calls)
+		})
+	}
+}
+
+func TestStrategy_Shutdown_pollingTimeout(t *testing.T) {
+	initialRequestRetrySpan, shutdownRetrySpan, pollingInterval, pollingTimeout := power.InitialRequestRetrySpan, power.ShutdownRetrySpan, defaults.DefaultStatePollingInterval, defaults.DefaultStatePollingTimeout
+	power.InitialRequestRetrySpan = time.Millisecond
+	power.ShutdownRetrySpan = 10 * time.Millisecond
+	defaults.DefaultStatePollingInterval = 10 * time.Millisecond
+	defaults.DefaultStatePollingTimeout = 50 * time.Millisecond
+	defer func() {
+		power.InitialRequestRetrySpan, power.ShutdownRetrySpan, defaults.DefaultStatePollingInterval, defaults.DefaultStatePollingTimeout = initialRequestRetrySpan, shutdownRetrySpan, pollingInterval, pollingTimeout
+	}()
+
+	t.Run("waits until Timeout", func(t *testing.T) {
+		client := newDummyServerAPI(types.ServerInstanceStatuses.Up, true, false)
+		go func() {
+			time.Sleep(200 * time.Millisecond)
+			client.mu.Lock()
+			defer client.mu.Unlock()
+			client.status = types.ServerInstanceStatuses.Down
+		}()
+
+		stage, err := (&Strategy{Timeout: time.Second}).Shutdown(context.Background(), client, "is1a", types.ID(1))
+		require.NoError(t, err)
+		require.Equal(t, StageACPI, stage)
+		require.Zero(t, client.calls[StageForce])
+	})
+
+	t.Run("escalates after Timeout", func(t *testing.T) {
+		client := newDummyServerAPI(types.ServerInstanceStatuses.Up, true, true)
+		started := time.Now()
+		stage, err := (&Strategy{Timeout: 200 * time.Millisecond, SendNMI: true, NMITimeout: 200 * time.Millisecond}).Shutdown(context.Background(), client, "is1a", types.ID(1))
+		require.NoError(t, err)
+		require.Equal(t, StageForce, stage)
+		require.GreaterOrEqual(t, time.Since(started), 400*time.Millisecond)
+	})
+}
@@ -16,18 +16,26 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/helper/power"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
 )
 
 func (s *Service) Shutdown(req *ShutdownRequest) error {
 	return s.ShutdownWithContext(context.Background(), req)
 }
 
-func (s *Service) ShutdownWithContext(ctx context.Context, req *ShutdownRequest) error {
+func (s *Service) ShutdownWithContext(ctx context.Context, req *ShutdownRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Shutdown", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "Shutdown", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewServerOp(s.caller)
@@ -36,3 +44,28 @@ func (s *Service) ShutdownWithContext(ctx context.Context, req *ShutdownRequest)
 	}
 	return power.ShutdownServer(ctx, client, req.Zone, req.ID, req.ForceShutdown)
 }
+
+// shutdownServer ShutdownStrategyが指定されていれば段階的に、そうでなければForceShutdownに従ってシャットダウンする
+//
+// ShutdownStrategyとForceShutdownは同時に指定されない(validateShutdownStrategyで検証済み)
+func shutdownServer(ctx context.Context, client shutdown.ServerAPI, zone string, id types.ID, force bool, strategy *shutdown.Strategy) error {
+	if strategy != nil {
+		_, err := strategy.Shutdown(ctx, client, zone, id)
+		return err
+	}
+	return power.ShutdownServer(ctx, client, zone, id, force)
+}
+
+// validateShutdownStrategy ShutdownStrategyを検証する、ForceShutdownとの同時指定はエラーとする
+func validateShutdownStrategy(strategy *shutdown.Strategy, forceShutdown bool) error {
+	if strategy == nil {
+		return nil
+	}
+	if forceShutdown {
+		return errors.New("ShutdownStrategy cannot be used with ForceShutdown")
+	}
+	if err := strategy.Validate(); err != nil {
+		return fmt.Errorf("invalid ShutdownStrategy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	diskService "github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Shutdown_strategy(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	zone := testutil.TestZone()
+	name := testutil.ResourceName("service-server-shutdown")
+
+	server, err := svc.Create(&CreateRequest{
+		Zone:     zone,
+		Name:     name,
+		CPU:      1,
+		MemoryGB: 1,
+		NetworkInterfaces: []*NetworkInterface{
+			{Upstream: "shared"},
+		},
+		Disks: []*diskService.ApplyRequest{
+			{Zone: zone, Name: name, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+		},
+		BootAfterCreate: true,
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: server.ID, Force: true, WithDisks: true}) // nolint
+
+	strategy := &shutdown.Strategy{Timeout: time.Minute}
+
+	_, err = svc.Snapshot(&SnapshotRequest{Zone: zone, ID: server.ID, Shutdown: true, ShutdownStrategy: &shutdown.Strategy{}})
+	require.Error(t, err)
+	require.Equal(t, service.ErrorKindValidation, service.KindOf(err))
+	_, err = svc.Snapshot(&SnapshotRequest{Zone: zone, ID: server.ID, Shutdown: true, ForceShutdown: true, ShutdownStrategy: strategy})
+	require.True(t, service.IsValidationError(err))
+	_, err = svc.ShutdownWithStrategy(&ShutdownWithStrategyRequest{Zone: zone, ID: server.ID})
+	require.True(t, service.IsValidationError(err))
+
+	snapshot, err := svc.Snapshot(&SnapshotRequest{Zone: zone, ID: server.ID, Shutdown: true, ShutdownStrategy: strategy})
+	require.NoError(t, err)
+	defer svc.DeleteSnapshot(&DeleteSnapshotRequest{Zone: zone, SetID: snapshot.SetID}) // nolint
+
+	result, err := svc.ShutdownWithStrategy(&ShutdownWithStrategyRequest{Zone: zone, ID: server.ID, Strategy: strategy})
+	require.NoError(t, err)
+	require.Equal(t, shutdown.StageACPI, result.Stage)
+
+	result, err = svc.ShutdownWithStrategy(&ShutdownWithStrategyRequest{Zone: zone, ID: server.ID, Strategy: strategy})
+	require.NoError(t, err)
+	require.Equal(t, shutdown.StageAlreadyDown, result.Stage)
+}
@@ -0,0 +1,36 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
+	"github.com/sacloud/packages-go/validate"
+)
+
+type ShutdownWithStrategyRequest struct {
+	Zone string   `service:"-" validate:"required"`
+	ID   types.ID `service:"-" validate:"required"`
+
+	// Strategy ACPIシャットダウン、NMI送信、強制停止の順に段階的にシャットダウンする際の設定
+	Strategy *shutdown.Strategy `service:"-" validate:"required"`
+}
+
+func (req *ShutdownWithStrategyRequest) Validate() error {
+	if err := validate.New().Struct(req); err != nil {
+		return err
+	}
+	return validateShutdownStrategy(req.Strategy, false)
+}
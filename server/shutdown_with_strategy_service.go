@@ -0,0 +1,48 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
+)
+
+// ShutdownResult ShutdownWithStrategyの結果
+type ShutdownResult struct {
+	Stage shutdown.Stage // シャットダウンが完了した段階
+}
+
+// ShutdownWithStrategy Strategyに従ってサーバを段階的にシャットダウンし、シャットダウンが完了した段階を返す
+func (s *Service) ShutdownWithStrategy(req *ShutdownWithStrategyRequest) (*ShutdownResult, error) {
+	return s.ShutdownWithStrategyWithContext(context.Background(), req)
+}
+
+func (s *Service) ShutdownWithStrategyWithContext(ctx context.Context, req *ShutdownWithStrategyRequest) (_ *ShutdownResult, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "ShutdownWithStrategy", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "ShutdownWithStrategy", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	stage, err := req.Strategy.Shutdown(ctx, iaas.NewServerOp(s.caller), req.Zone, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &ShutdownResult{Stage: stage}, nil
+}
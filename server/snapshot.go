@@ -0,0 +1,330 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// SnapshotTagPrefix スナップショットセットに属するアーカイブに付与されるタグの接頭辞
+//
+// タグは"@snapshot=<SetID>"の形式となる
+const SnapshotTagPrefix = "@snapshot="
+
+// snapshotMaxDescriptionLen アーカイブの説明の最大長
+const snapshotMaxDescriptionLen = 512
+
+// Snapshot サーバの全ディスクから作成したアーカイブの組(スナップショットセット)
+type Snapshot struct {
+	SetID     string
+	Zone      string
+	CreatedAt time.Time
+	Server    *SnapshotServer
+	Disks     []*SnapshotDisk // サーバへの接続順
+}
+
+// SnapshotServer スナップショット取得時のサーバの情報
+type SnapshotServer struct {
+	ID              types.ID
+	Name            string // 先頭のディスクのアーカイブ名から接尾辞("-0")を除いたもの
+	CPU             int
+	MemoryGB        int
+	GPU             int
+	Commitment      types.ECommitment
+	Generation      types.EPlanGeneration
+	InterfaceDriver types.EInterfaceDriver
+	CDROMID         types.ID
+
+	NetworkInterfaces []*NetworkInterface
+}
+
+// SnapshotDisk スナップショットセットに含まれるディスクごとの情報
+type SnapshotDisk struct {
+	Index        int
+	ArchiveID    types.ID
+	SourceDiskID types.ID
+	Name         string // アーカイブ名
+	DiskPlanID   types.ID
+	Connection   types.EDiskConnection
+	SizeGB       int
+}
+
+// Tag スナップショットセットに属するアーカイブに付与されるタグを返す
+func (s *Snapshot) Tag() string {
+	return snapshotTag(s.SetID)
+}
+
+// ArchiveIDs ディスクの接続順にアーカイブのIDを返す
+func (s *Snapshot) ArchiveIDs() []types.ID {
+	var ids []types.ID
+	for _, d := range s.Disks {
+		ids = append(ids, d.ArchiveID)
+	}
+	return ids
+}
+
+func snapshotTag(setID string) string {
+	return SnapshotTagPrefix + setID
+}
+
+func newSnapshotSetID(serverID types.ID, now time.Time) string {
+	return fmt.Sprintf("%s-%s", serverID, now.UTC().Format("20060102150405"))
+}
+
+func newSnapshotServer(server *iaas.Server) *SnapshotServer {
+	s := &SnapshotServer{
+		ID:              server.ID,
+		Name:            server.Name,
+		CPU:             server.CPU,
+		MemoryGB:        server.GetMemoryGB(),
+		GPU:             server.GPU,
+		Commitment:      server.ServerPlanCommitment,
+		Generation:      server.ServerPlanGeneration,
+		InterfaceDriver: server.InterfaceDriver,
+		CDROMID:         server.CDROMID,
+	}
+	for _, iface := range server.Interfaces {
+		nic := &NetworkInterface{PacketFilterID: iface.PacketFilterID}
+		switch {
+		case iface.SwitchID.IsEmpty():
+			nic.Upstream = "disconnected"
+		case iface.SwitchScope == types.Scopes.Shared:
+			nic.Upstream = "shared"
+		default:
+			nic.Upstream = iface.SwitchID.String()
+			nic.UserIPAddress = iface.UserIPAddress
+		}
+		s.NetworkInterfaces = append(s.NetworkInterfaces, nic)
+	}
+	return s
+}
+
+// snapshotMetadata アーカイブの説明に保存されるメタデータ
+//
+// 説明の最大長に収まるようキーは短縮し、名前はアーカイブ名から復元するため保存しない。
+// サーバの情報は先頭のディスクのアーカイブにのみ保存し、NICの情報は先頭のディスクのアーカイブから順に収まるだけ保存する
+type snapshotMetadata struct {
+	SetID     string                  `json:"set"`
+	CreatedAt int64                   `json:"at"`
+	Server    *snapshotServerMetadata `json:"sv,omitempty"`
+	Disk      *snapshotDiskMetadata   `json:"d"`
+
+	NICIndex          int                    `json:"ni,omitempty"` // NetworkInterfacesの先頭のNICの番号
+	NetworkInterfaces []*snapshotNICMetadata `json:"nic,omitempty"`
+}
+
+type snapshotServerMetadata struct {
+	ID              types.ID               `json:"id"`
+	CPU             int                    `json:"c"`
+	MemoryGB        int                    `json:"m"`
+	GPU             int                    `json:"g,omitempty"`
+	Commitment      types.ECommitment      `json:"cm,omitempty"`
+	Generation      types.EPlanGeneration  `json:"gen,omitempty"`
+	InterfaceDriver types.EInterfaceDriver `json:"drv,omitempty"`
+	CDROMID         types.ID               `json:"cd,omitempty"`
+	NICCount        int                    `json:"nics,omitempty"`
+}
+
+type snapshotNICMetadata struct {
+	Upstream       string   `json:"u"`
+	PacketFilterID types.ID `json:"pf,omitempty"`
+	UserIPAddress  string   `json:"ip,omitempty"`
+}
+
+type snapshotDiskMetadata struct {
+	Index        int                   `json:"i"`
+	SourceDiskID types.ID              `json:"src"`
+	DiskPlanID   types.ID              `json:"p"`
+	Connection   types.EDiskConnection `json:"c"`
+	SizeGB       int                   `json:"s"`
+}
+
+func newSnapshotMetadata(snapshot *Snapshot, disk *SnapshotDisk) *snapshotMetadata {
+	m := &snapshotMetadata{
+		SetID:     snapshot.SetID,
+		CreatedAt: snapshot.CreatedAt.Unix(),
+		Disk: &snapshotDiskMetadata{
+			Index:        disk.Index,
+			SourceDiskID: disk.SourceDiskID,
+			DiskPlanID:   disk.DiskPlanID,
+			Connection:   disk.Connection,
+			SizeGB:       disk.SizeGB,
+		},
+	}
+	if disk.Index == 0 {
+		server := snapshot.Server
+		m.Server = &snapshotServerMetadata{
+			ID:              server.ID,
+			CPU:             server.CPU,
+			MemoryGB:        server.MemoryGB,
+			GPU:             server.GPU,
+			Commitment:      server.Commitment,
+			Generation:      server.Generation,
+			InterfaceDriver: server.InterfaceDriver,
+			CDROMID:         server.CDROMID,
+			NICCount:        len(server.NetworkInterfaces),
+		}
+	}
+	return m
+}
+
+// snapshotDescriptions ディスクの接続順に各アーカイブの説明を返す
+func snapshotDescriptions(snapshot *Snapshot) ([]string, error) {
+	nics := snapshot.Server.NetworkInterfaces
+	next := 0
+	var descriptions []string
+	for _, d := range snapshot.Disks {
+		m := newSnapshotMetadata(snapshot, d)
+		m.NICIndex = next
+		for next < len(nics) {
+			nic := nics[next]
+			m.NetworkInterfaces = append(m.NetworkInterfaces, &snapshotNICMetadata{
+				Upstream:       nic.Upstream,
+				PacketFilterID: nic.PacketFilterID,
+				UserIPAddress:  nic.UserIPAddress,
+			})
+			if _, err := m.description(); err != nil {
+				m.NetworkInterfaces = m.NetworkInterfaces[:len(m.NetworkInterfaces)-1]
+				break
+			}
+			next++
+		}
+		if len(m.NetworkInterfaces) == 0 {
+			m.NICIndex = 0
+		}
+
+		description, err := m.description()
+		if err != nil {
+			return nil, err
+		}
+		descriptions = append(descriptions, description)
+	}
+	if next < len(nics) {
+		return nil, fmt.Errorf("snapshot metadata is too long: %d of %d network interface(s) do not fit in the archive descriptions", len(nics)-next, len(nics))
+	}
+	return descriptions, nil
+}
+
+func (m *snapshotMetadata) description() (string, error) {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	if len(data) > snapshotMaxDescriptionLen {
+		return "", fmt.Errorf("snapshot metadata is too long: %d bytes (max: %d)", len(data), snapshotMaxDescriptionLen)
+	}
+	return string(data), nil
+}
+
+func (m *snapshotMetadata) snapshotServer(archive *iaas.Archive) *SnapshotServer {
+	if m.Server == nil {
+		return nil
+	}
+	return &SnapshotServer{
+		ID:              m.Server.ID,
+		Name:            strings.TrimSuffix(archive.Name, snapshotArchiveNameSuffix(m.Disk.Index)),
+		CPU:             m.Server.CPU,
+		MemoryGB:        m.Server.MemoryGB,
+		GPU:             m.Server.GPU,
+		Commitment:      m.Server.Commitment,
+		Generation:      m.Server.Generation,
+		InterfaceDriver: m.Server.InterfaceDriver,
+		CDROMID:         m.Server.CDROMID,
+	}
+}
+
+func (m *snapshotMetadata) snapshotDisk(archive *iaas.Archive) *SnapshotDisk {
+	return &SnapshotDisk{
+		Index:        m.Disk.Index,
+		ArchiveID:    archive.ID,
+		SourceDiskID: m.Disk.SourceDiskID,
+		Name:         archive.Name,
+		DiskPlanID:   m.Disk.DiskPlanID,
+		Connection:   m.Disk.Connection,
+		SizeGB:       m.Disk.SizeGB,
+	}
+}
+
+// snapshotArchiveName スナップショットセットに属するアーカイブの名前を返す
+func snapshotArchiveName(prefix string, index int) string {
+	return prefix + snapshotArchiveNameSuffix(index)
+}
+
+func snapshotArchiveNameSuffix(index int) string {
+	return fmt.Sprintf("-%d", index)
+}
+
+// parseSnapshotMetadata アーカイブの説明からスナップショットのメタデータを読み取る
+func parseSnapshotMetadata(setID string, archive *iaas.Archive) (*snapshotMetadata, error) {
+	metadata := &snapshotMetadata{}
+	if err := json.NewDecoder(strings.NewReader(archive.Description)).Decode(metadata); err != nil {
+		return nil, fmt.Errorf("archive[%s] has invalid snapshot metadata: %w", archive.ID, err)
+	}
+	if metadata.SetID != setID || metadata.Disk == nil {
+		return nil, fmt.Errorf("archive[%s] has invalid snapshot metadata", archive.ID)
+	}
+	return metadata, nil
+}
+
+// snapshotFromArchives スナップショットセットに属するアーカイブからSnapshotを組み立てる
+func snapshotFromArchives(zone, setID string, archives []*iaas.Archive) (*Snapshot, error) {
+	snapshot := &Snapshot{SetID: setID, Zone: zone}
+	nicCount := 0
+	nics := make(map[int]*NetworkInterface)
+	for _, archive := range archives {
+		metadata, err := parseSnapshotMetadata(setID, archive)
+		if err != nil {
+			return nil, err
+		}
+		if metadata.Server != nil {
+			snapshot.Server = metadata.snapshotServer(archive)
+			snapshot.CreatedAt = time.Unix(metadata.CreatedAt, 0)
+			nicCount = metadata.Server.NICCount
+		}
+		snapshot.Disks = append(snapshot.Disks, metadata.snapshotDisk(archive))
+		for i, nic := range metadata.NetworkInterfaces {
+			nics[metadata.NICIndex+i] = &NetworkInterface{
+				Upstream:       nic.Upstream,
+				PacketFilterID: nic.PacketFilterID,
+				UserIPAddress:  nic.UserIPAddress,
+			}
+		}
+	}
+
+	if snapshot.Server == nil {
+		return nil, fmt.Errorf("snapshot set %q has no server metadata", setID)
+	}
+	sort.Slice(snapshot.Disks, func(i, j int) bool { return snapshot.Disks[i].Index < snapshot.Disks[j].Index })
+	for i, d := range snapshot.Disks {
+		if d.Index != i {
+			return nil, fmt.Errorf("snapshot set %q is incomplete: disk #%d not found", setID, i)
+		}
+	}
+	for i := 0; i < nicCount; i++ {
+		nic, ok := nics[i]
+		if !ok {
+			return nil, fmt.Errorf("snapshot set %q is incomplete: network interface #%d not found", setID, i)
+		}
+		snapshot.Server.NetworkInterfaces = append(snapshot.Server.NetworkInterfaces, nic)
+	}
+	return snapshot, nil
+}
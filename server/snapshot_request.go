@@ -0,0 +1,47 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
+	"github.com/sacloud/packages-go/validate"
+)
+
+// SnapshotRequest サーバのスナップショット作成リクエスト
+type SnapshotRequest struct {
+	Zone string   `service:"-" validate:"required"`
+	ID   types.ID `service:"-" validate:"required"`
+
+	// Name 作成するアーカイブの名前の接頭辞、省略時はサーバ名
+	//
+	// アーカイブ名は"<Name>-<ディスクの番号>"となり、リストア時のサーバ名/ディスク名にはこの名前が利用される
+	Name string `validate:"omitempty,min=1"`
+	// Tags 作成するアーカイブに付与するタグ、スナップショットセットを示すタグは自動で付与される
+	Tags   types.Tags
+	IconID types.ID
+
+	// Shutdown trueの場合、一貫性のあるスナップショットを作成するためにサーバをシャットダウンし、アーカイブ作成後に起動する
+	Shutdown         bool
+	ForceShutdown    bool
+	ShutdownStrategy *shutdown.Strategy // シャットダウンが必要な場合のシャットダウン方法、ForceShutdownとは同時に指定できない
+}
+
+func (req *SnapshotRequest) Validate() error {
+	if err := validate.New().Struct(req); err != nil {
+		return err
+	}
+	return validateShutdownStrategy(req.ShutdownStrategy, req.ForceShutdown)
+}
@@ -0,0 +1,165 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/helper/power"
+	service "github.com/sacloud/iaas-service-go"
+	archiveBuilder "github.com/sacloud/iaas-service-go/archive/builder"
+)
+
+// Snapshot サーバに接続された全ディスクからアーカイブを作成する
+//
+// 作成したアーカイブにはスナップショットセットを示すタグと、リストア用にサーバのプラン/NIC/ディスクの情報が設定される。
+// いずれかのアーカイブの作成に失敗した場合は作成済みのアーカイブを削除する
+func (s *Service) Snapshot(req *SnapshotRequest) (*Snapshot, error) {
+	return s.SnapshotWithContext(context.Background(), req)
+}
+
+func (s *Service) SnapshotWithContext(ctx context.Context, req *SnapshotRequest) (_ *Snapshot, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Snapshot", Resource: "Server", Zone: req.Zone, ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Snapshot", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	serverOp := iaas.NewServerOp(s.caller)
+	server, err := serverOp.Read(ctx, req.Zone, req.ID)
+	if err != nil {
+		return nil, &service.Error{Op: "Snapshot", Resource: "Server", Zone: req.Zone, ID: req.ID, Err: err}
+	}
+	if len(server.Disks) == 0 {
+		return nil, &service.Error{
+			Op: "Snapshot", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation,
+			Err: errors.New("server has no disks"),
+		}
+	}
+
+	now := time.Now().Truncate(time.Second)
+	snapshot := &Snapshot{
+		SetID:     newSnapshotSetID(server.ID, now),
+		Zone:      req.Zone,
+		CreatedAt: now,
+		Server:    newSnapshotServer(server),
+	}
+	diskOp := iaas.NewDiskOp(s.caller)
+	for i, d := range server.Disks {
+		disk, err := diskOp.Read(ctx, req.Zone, d.ID)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Disks = append(snapshot.Disks, &SnapshotDisk{
+			Index:        i,
+			SourceDiskID: disk.ID,
+			Name:         disk.Name,
+			DiskPlanID:   disk.DiskPlanID,
+			Connection:   disk.Connection,
+			SizeGB:       disk.GetSizeGB(),
+		})
+	}
+
+	builders, err := snapshotArchiveBuilders(s.caller, req, snapshot)
+	if err != nil {
+		return nil, &service.Error{Op: "Snapshot", Resource: "Server", Zone: req.Zone, ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	if req.Shutdown && server.InstanceStatus.IsUp() {
+		if err := shutdownServer(ctx, serverOp, req.Zone, req.ID, req.ForceShutdown, req.ShutdownStrategy); err != nil {
+			return nil, err
+		}
+		defer func() {
+			// アーカイブ作成の失敗原因がキャンセルの場合でもサーバは起動する
+			bootCtx := ctx
+			if bootCtx.Err() != nil {
+				bootCtx = context.Background()
+			}
+			if bootErr := power.BootServer(bootCtx, serverOp, req.Zone, req.ID); bootErr != nil {
+				err = errors.Join(err, bootErr)
+			}
+		}()
+	}
+
+	if err := buildSnapshotArchives(ctx, s.caller, req.Zone, builders, snapshot); err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+func snapshotArchiveBuilders(caller iaas.APICaller, req *SnapshotRequest, snapshot *Snapshot) ([]*archiveBuilder.StandardArchiveBuilder, error) {
+	name := req.Name
+	if name == "" {
+		name = snapshot.Server.Name
+	}
+	tags := append(append([]string{}, req.Tags...), snapshot.Tag())
+
+	descriptions, err := snapshotDescriptions(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	var builders []*archiveBuilder.StandardArchiveBuilder
+	for i, d := range snapshot.Disks {
+		builders = append(builders, &archiveBuilder.StandardArchiveBuilder{
+			Name:         snapshotArchiveName(name, d.Index),
+			Description:  descriptions[i],
+			Tags:         tags,
+			IconID:       req.IconID,
+			SourceDiskID: d.SourceDiskID,
+			Client:       archiveBuilder.NewAPIClient(caller),
+		})
+	}
+	return builders, nil
+}
+
+// buildSnapshotArchives 各ディスクのアーカイブを並行して作成する
+func buildSnapshotArchives(ctx context.Context, caller iaas.APICaller, zone string, builders []*archiveBuilder.StandardArchiveBuilder, snapshot *Snapshot) error {
+	errs := make([]error, len(builders))
+	var wg sync.WaitGroup
+	for i, b := range builders {
+		wg.Add(1)
+		go func(i int, b *archiveBuilder.StandardArchiveBuilder) {
+			defer wg.Done()
+			archive, err := b.Build(ctx, zone)
+			if archive != nil {
+				snapshot.Disks[i].ArchiveID = archive.ID
+			}
+			errs[i] = err
+		}(i, b)
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	if err == nil {
+		return nil
+	}
+
+	archiveOp := iaas.NewArchiveOp(caller)
+	for _, d := range snapshot.Disks {
+		if d.ArchiveID.IsEmpty() {
+			continue
+		}
+		if deleteErr := archiveOp.Delete(ctx, zone, d.ArchiveID); deleteErr != nil {
+			err = errors.Join(err, fmt.Errorf("deleting archive[%s] failed: %w", d.ArchiveID, deleteErr))
+		}
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	diskService "github.com/sacloud/iaas-service-go/disk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_SnapshotAndRestore(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	zone := testutil.TestZone()
+	name := testutil.ResourceName("service-server-snapshot")
+
+	source, err := svc.Create(&CreateRequest{
+		Zone:     zone,
+		Name:     name,
+		CPU:      2,
+		MemoryGB: 4,
+		NetworkInterfaces: []*NetworkInterface{
+			{Upstream: "shared"},
+		},
+		Disks: []*diskService.ApplyRequest{
+			{Zone: zone, Name: name + "-1", DiskPlanID: types.DiskPlans.SSD, SizeGB: 20, Connection: types.DiskConnections.VirtIO},
+			{Zone: zone, Name: name + "-2", DiskPlanID: types.DiskPlans.HDD, SizeGB: 40, Connection: types.DiskConnections.IDE},
+		},
+		BootAfterCreate: true,
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: source.ID, Force: true, WithDisks: true}) // nolint
+
+	snapshot, err := svc.Snapshot(&SnapshotRequest{Zone: zone, ID: source.ID, Shutdown: true})
+	require.NoError(t, err)
+	require.Len(t, snapshot.Disks, 2)
+
+	// source server is booted again
+	read, err := svc.Read(&ReadRequest{Zone: zone, ID: source.ID})
+	require.NoError(t, err)
+	require.True(t, read.InstanceStatus.IsUp())
+
+	found, err := svc.ReadSnapshot(&ReadSnapshotRequest{Zone: zone, SetID: snapshot.SetID})
+	require.NoError(t, err)
+	require.Equal(t, snapshot.ArchiveIDs(), found.ArchiveIDs())
+	require.Equal(t, 2, found.Server.CPU)
+	require.Equal(t, 4, found.Server.MemoryGB)
+	require.Equal(t, []*NetworkInterface{{Upstream: "shared"}}, found.Server.NetworkInterfaces)
+
+	t.Run("restore as new server", func(t *testing.T) {
+		restored, err := svc.Restore(&RestoreRequest{Zone: zone, SetID: snapshot.SetID, Name: name + "-restored"})
+		require.NoError(t, err)
+		defer svc.Delete(&DeleteRequest{Zone: zone, ID: restored.ID, Force: true, WithDisks: true}) // nolint
+
+		require.NotEqual(t, source.ID, restored.ID)
+		require.Equal(t, name+"-restored", restored.Name)
+		require.Equal(t, 2, restored.CPU)
+		require.Equal(t, 4, restored.GetMemoryGB())
+		require.Len(t, restored.Interfaces, 1)
+		requireRestoredDisks(t, caller, zone, restored, snapshot)
+	})
+
+	t.Run("restore in place", func(t *testing.T) {
+		restored, err := svc.Restore(&RestoreRequest{Zone: zone, SetID: snapshot.SetID, ID: source.ID, DeleteReplacedDisks: true})
+		require.NoError(t, err)
+
+		require.Equal(t, source.ID, restored.ID)
+		require.True(t, restored.InstanceStatus.IsUp())
+		requireRestoredDisks(t, caller, zone, restored, snapshot)
+
+		_, err = iaas.NewDiskOp(caller).Read(context.Background(), zone, source.Disks[0].ID)
+		require.True(t, iaas.IsNotFoundError(err))
+	})
+
+	err = svc.DeleteSnapshot(&DeleteSnapshotRequest{Zone: zone, SetID: snapshot.SetID})
+	require.NoError(t, err)
+
+	_, err = svc.ReadSnapshot(&ReadSnapshotRequest{Zone: zone, SetID: snapshot.SetID})
+	require.True(t, service.IsNotFoundError(err))
+
+	_, err = svc.Restore(&RestoreRequest{Zone: zone, SetID: snapshot.SetID})
+	require.True(t, service.IsNotFoundError(err))
+	require.EqualError(t, err, fmt.Sprintf("Server[%s] Restore failed: snapshot set %q not found", zone, snapshot.SetID))
+}
+
+func TestService_Snapshot_manyNICs(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	svc := New(caller)
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	name := strings.Repeat("試", 64)
+	diskName := strings.Repeat("験", 64)
+
+	sw, err := iaas.NewSwitchOp(caller).Create(ctx, zone, &iaas.SwitchCreateRequest{Name: "snapshot-many-nics"})
+	require.NoError(t, err)
+	defer iaas.NewSwitchOp(caller).Delete(ctx, zone, sw.ID) // nolint
+
+	nics := []*NetworkInterface{{Upstream: "shared"}}
+	for i := 1; i < 10; i++ {
+		nics = append(nics, &NetworkInterface{Upstream: sw.ID.String(), UserIPAddress: fmt.Sprintf("192.168.100.%d", 100+i)})
+	}
+	source, err := svc.Create(&CreateRequest{
+		Zone:              zone,
+		Name:              name,
+		CPU:               1,
+		MemoryGB:          1,
+		NetworkInterfaces: nics,
+		Disks: []*diskService.ApplyRequest{
+			{Zone: zone, Name: diskName, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+			{Zone: zone, Name: diskName, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+		},
+	})
+	require.NoError(t, err)
+	defer svc.Delete(&DeleteRequest{Zone: zone, ID: source.ID, Force: true, WithDisks: true}) // nolint
+
+	snapshot, err := svc.Snapshot(&SnapshotRequest{Zone: zone, ID: source.ID})
+	require.NoError(t, err)
+	defer svc.DeleteSnapshot(&DeleteSnapshotRequest{Zone: zone, SetID: snapshot.SetID}) // nolint
+
+	for _, id := range snapshot.ArchiveIDs() {
+		archive, err := iaas.NewArchiveOp(caller).Read(ctx, zone, id)
+		require.NoError(t, err)
+		require.LessOrEqual(t, len(archive.Description), snapshotMaxDescriptionLen)
+	}
+
+	found, err := svc.ReadSnapshot(&ReadSnapshotRequest{Zone: zone, SetID: snapshot.SetID})
+	require.NoError(t, err)
+	require.Equal(t, name, found.Server.Name)
+	require.Equal(t, nics, found.Server.NetworkInterfaces)
+	require.Len(t, found.Disks, 2)
+}
+
+func TestSnapshotDescriptions(t *testing.T) {
+	snapshot := &Snapshot{
+		SetID:     "123456789012-20230101000000",
+		CreatedAt: time.Unix(1672531200, 0),
+		Server:    &SnapshotServer{ID: 123456789012, Name: strings.Repeat("試", 64), CPU: 1, MemoryGB: 1},
+		Disks: []*SnapshotDisk{
+			{Index: 0, SourceDiskID: 123456789013, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+			{Index: 1, SourceDiskID: 123456789014, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+		},
+	}
+	for i := 0; i < 10; i++ {
+		snapshot.Server.NetworkInterfaces = append(snapshot.Server.NetworkInterfaces, &NetworkInterface{
+			Upstream:       "123456789015",
+			PacketFilterID: 123456789016,
+			UserIPAddress:  fmt.Sprintf("192.168.100.%d", 100+i),
+		})
+	}
+
+	descriptions, err := snapshotDescriptions(snapshot)
+	require.NoError(t, err)
+
+	var archives []*iaas.Archive
+	for i, description := range descriptions {
+		require.LessOrEqual(t, len(description), snapshotMaxDescriptionLen)
+		archives = append(archives, &iaas.Archive{
+			ID:          types.ID(200 + i),
+			Name:        snapshotArchiveName(snapshot.Server.Name, i),
+			Description: description,
+		})
+	}
+	restored, err := snapshotFromArchives("is1a", snapshot.SetID, archives)
+	require.NoError(t, err)
+	require.Equal(t, snapshot.Server, restored.Server)
+
+	// NICの情報が一部のアーカイブにしかない場合は不完全なスナップショットとする
+	_, err = snapshotFromArchives("is1a", snapshot.SetID, archives[:1])
+	require.Error(t, err)
+
+	// 全てのアーカイブに収まらない場合
+	snapshot.Disks = snapshot.Disks[:1]
+	_, err = snapshotDescriptions(snapshot)
+	require.Error(t, err)
+}
+
+func requireRestoredDisks(t *testing.T, caller iaas.APICaller, zone string, server *iaas.Server, snapshot *Snapshot) {
+	require.Len(t, server.Disks, len(snapshot.Disks))
+	for i, d := range snapshot.Disks {
+		disk, err := iaas.NewDiskOp(caller).Read(context.Background(), zone, server.Disks[i].ID)
+		require.NoError(t, err)
+		require.Equal(t, d.ArchiveID, disk.SourceArchiveID)
+		require.Equal(t, d.Connection, disk.Connection)
+		require.Equal(t, d.SizeGB, disk.GetSizeGB())
+	}
+}
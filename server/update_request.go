@@ -21,6 +21,7 @@ import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/types"
 	diskService "github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/server/shutdown"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/validate"
 )
@@ -47,10 +48,14 @@ type UpdateRequest struct {
 	Disks             *[]*diskService.ApplyRequest `service:",omitempty"`
 	NoWait            bool
 	ForceShutdown     bool
+	ShutdownStrategy  *shutdown.Strategy `service:"-"` // シャットダウンが必要な場合のシャットダウン方法、ForceShutdownとは同時に指定できない
 }
 
 func (req *UpdateRequest) Validate() error {
-	return validate.New().Struct(req)
+	if err := validate.New().Struct(req); err != nil {
+		return err
+	}
+	return validateShutdownStrategy(req.ShutdownStrategy, req.ForceShutdown)
 }
 
 func (req *UpdateRequest) ApplyRequest(ctx context.Context, caller iaas.APICaller) (*ApplyRequest, error) {
@@ -135,5 +140,6 @@ func (req *UpdateRequest) applyRequestFromResource(ctx context.Context, caller i
 	if err := serviceutil.RequestConvertTo(req, applyRequest); err != nil {
 		return nil, err
 	}
+	applyRequest.ShutdownStrategy = req.ShutdownStrategy
 	return applyRequest, nil
 }
@@ -17,6 +17,7 @@ package serviceclass
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/validate"
 )
@@ -24,6 +25,8 @@ import (
 type FindRequest struct {
 	Zone string `service:"-" validate:"required"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -41,5 +44,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 		return nil, err
 	}
 
+	filter.SetCondition[*iaas.ServiceClass](&req.Filter, condition)
 	return condition, nil
 }
@@ -0,0 +1,104 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceutil
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// APIURL さくらのクラウドAPIのURLを分解したもの
+type APIURL struct {
+	// Zone ゾーン名、ゾーンを含まないURLの場合は空
+	Zone string
+	// Segments api/cloud/1.1/以降のパスの要素
+	Segments []string
+}
+
+// ParseAPIURL APIのURLを分解する
+//
+// 例: https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/server/123456789012/power
+// の場合はZoneがis1a、Segmentsがserver、123456789012、powerとなる。
+// api/cloud/1.1/を含まないURLの場合はfalseを返す。
+func ParseAPIURL(uri string) (*APIURL, bool) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, false
+	}
+
+	var zone string
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, s := range segments {
+		if s == "zone" && i+1 < len(segments) && zone == "" {
+			zone = segments[i+1]
+		}
+		if s == "1.1" && i >= 2 && segments[i-2] == "api" && segments[i-1] == "cloud" {
+			var rest []string
+			for _, s := range segments[i+1:] {
+				if s != "" {
+					rest = append(rest, s)
+				}
+			}
+			return &APIURL{Zone: zone, Segments: rest}, true
+		}
+	}
+	return nil, false
+}
+
+// Path api/cloud/1.1/以降のパスを返す
+func (u *APIURL) Path() string {
+	return strings.Join(u.Segments, "/")
+}
+
+// Kind パスの先頭の要素(リソース種別)を返す
+func (u *APIURL) Kind() string {
+	if len(u.Segments) == 0 {
+		return ""
+	}
+	return u.Segments[0]
+}
+
+// ID パスに含まれる最初のリソースIDを返す、含まれない場合は空のIDを返す
+func (u *APIURL) ID() types.ID {
+	for i, s := range u.Segments {
+		if i > 0 && isDigits(s) {
+			return types.StringID(s)
+		}
+	}
+	return types.ID(0)
+}
+
+// Template パスに含まれるリソースIDを{id}に置き換えたパスを返す
+func (u *APIURL) Template() string {
+	segments := make([]string, len(u.Segments))
+	for i, s := range u.Segments {
+		if i > 0 && isDigits(s) {
+			s = "{id}"
+		}
+		segments[i] = s
+	}
+	return strings.Join(segments, "/")
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,46 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceutil
+
+import (
+	"testing"
+
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAPIURL(t *testing.T) {
+	u, ok := ParseAPIURL("https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/server/123456789012/power")
+	require.True(t, ok)
+	require.Equal(t, "is1a", u.Zone)
+	require.Equal(t, "server/123456789012/power", u.Path())
+	require.Equal(t, "server/{id}/power", u.Template())
+	require.Equal(t, "server", u.Kind())
+	require.Equal(t, types.ID(123456789012), u.ID())
+
+	u, ok = ParseAPIURL("https://secure.sakura.ad.jp/cloud/zone/is1a/api/cloud/1.1/product/server")
+	require.True(t, ok)
+	require.Equal(t, "product/server", u.Template())
+	require.Equal(t, "product", u.Kind())
+	require.True(t, u.ID().IsEmpty())
+
+	u, ok = ParseAPIURL("https://secure.sakura.ad.jp/cloud/api/cloud/1.1/region/")
+	require.True(t, ok)
+	require.Empty(t, u.Zone)
+	require.Equal(t, "region", u.Path())
+
+	_, ok = ParseAPIURL("https://example.com/server/1")
+	require.False(t, ok)
+}
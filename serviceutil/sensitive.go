@@ -0,0 +1,54 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceutil
+
+import "strings"
+
+// DefaultSensitiveKeys 値をマスクするフィールド名のデフォルト値(小文字、部分一致)
+var DefaultSensitiveKeys = []string{"password", "secret", "token", "privatekey", "passphrase", "passcode"}
+
+// MaskSensitiveValues JSONをデコードした値のうち、名前にkeysのいずれかを含む(大文字小文字は区別しない)フィールドの値をmaskへ置き換える
+//
+// vは直接変更される。値が空のフィールドは置き換えない。
+func MaskSensitiveValues(v interface{}, keys []string, mask string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if IsSensitiveKey(key, keys) {
+				if value != nil && value != "" {
+					v[key] = mask
+				}
+				continue
+			}
+			v[key] = MaskSensitiveValues(value, keys, mask)
+		}
+	case []interface{}:
+		for i := range v {
+			v[i] = MaskSensitiveValues(v[i], keys, mask)
+		}
+	}
+	return v
+}
+
+// IsSensitiveKey フィールド名がkeysのいずれかを含むか(大文字小文字は区別しない)
+func IsSensitiveKey(key string, keys []string) bool {
+	key = strings.ToLower(key)
+	for _, s := range keys {
+		if strings.Contains(key, strings.ToLower(s)) {
+			return true
+		}
+	}
+	return false
+}
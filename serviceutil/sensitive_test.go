@@ -0,0 +1,43 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package serviceutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskSensitiveValues(t *testing.T) {
+	v := map[string]interface{}{
+		"Name":     "example",
+		"PassCode": "1234",
+		"Password": "",
+		"Disk": []interface{}{
+			map[string]interface{}{"SSHKey": "ssh-ed25519 AAAA", "PrivateKey": "-----BEGIN"},
+		},
+	}
+	require.Equal(t, map[string]interface{}{
+		"Name":     "example",
+		"PassCode": "***",
+		"Password": "",
+		"Disk": []interface{}{
+			map[string]interface{}{"SSHKey": "ssh-ed25519 AAAA", "PrivateKey": "***"},
+		},
+	}, MaskSensitiveValues(v, DefaultSensitiveKeys, "***"))
+
+	require.True(t, IsSensitiveKey("APIToken", []string{"TOKEN"}))
+	require.False(t, IsSensitiveKey("Name", DefaultSensitiveKeys))
+}
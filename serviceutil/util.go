@@ -20,11 +20,12 @@ import (
 
 	iaas "github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/mapconv"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/packages-go/size"
 )
 
 func HandleNotFoundError(err error, ignoreNotFoundError bool) error {
-	if ignoreNotFoundError && iaas.IsNotFoundError(err) {
+	if ignoreNotFoundError && service.IsNotFoundError(err) {
 		return nil // ignore: 404 not found
 	}
 	return err
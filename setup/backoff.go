@@ -0,0 +1,112 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package setup
+
+import (
+	"context"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// Backoff リトライ時の待ち時間の算出方法
+type Backoff interface {
+	// Next attempt回目(1始まり)の試行が失敗した後の待ち時間を返す
+	Next(attempt int) time.Duration
+}
+
+// ConstantBackoff 固定間隔で待つBackoff
+type ConstantBackoff struct {
+	Interval time.Duration
+}
+
+// Next attempt回目の試行が失敗した後の待ち時間を返す
+func (b *ConstantBackoff) Next(_ int) time.Duration {
+	return b.Interval
+}
+
+// ExponentialBackoff 試行ごとに待ち時間を指数関数的に増やすBackoff
+type ExponentialBackoff struct {
+	// Initial 初回の待ち時間
+	Initial time.Duration
+	// Max 待ち時間の上限、0の場合は上限なし
+	Max time.Duration
+	// Multiplier 試行ごとに待ち時間に掛ける値、1未満の場合は2
+	Multiplier float64
+}
+
+// Next attempt回目の試行が失敗した後の待ち時間を返す
+func (b *ExponentialBackoff) Next(attempt int) time.Duration {
+	multiplier := b.Multiplier
+	if multiplier < 1 {
+		multiplier = 2
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+
+	d := float64(b.Initial) * math.Pow(multiplier, float64(attempt-1))
+	if b.Max > 0 && d > float64(b.Max) {
+		return b.Max
+	}
+	if d > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(d)
+}
+
+// JitterBackoff 他のBackoffが算出した待ち時間をランダムに揺らがせるBackoff
+//
+// 複数のクライアントからのリトライが同じタイミングに集中することを避けるために利用する
+type JitterBackoff struct {
+	// Backoff 元となる待ち時間を算出するBackoff
+	Backoff Backoff
+	// Factor 揺らぎの幅(0〜1)、待ち時間は元の値の(1-Factor)倍から(1+Factor)倍の範囲となる。0の場合は0.5
+	Factor float64
+}
+
+// Next attempt回目の試行が失敗した後の待ち時間を返す
+func (b *JitterBackoff) Next(attempt int) time.Duration {
+	if b.Backoff == nil {
+		return 0
+	}
+	d := b.Backoff.Next(attempt)
+
+	factor := b.Factor
+	if factor <= 0 || factor > 1 {
+		factor = 0.5
+	}
+	delta := factor * float64(d)
+	lower := float64(d) - delta
+	return time.Duration(lower + rand.Float64()*2*delta) //nolint:gosec
+}
+
+// Wait 指定の時間待つ
+//
+// 待機中にctxがキャンセルされた場合はctx.Err()を返す
+func Wait(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
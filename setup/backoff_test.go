@@ -0,0 +1,51 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package setup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExponentialBackoff_Next(t *testing.T) {
+	backoff := &ExponentialBackoff{Initial: time.Second, Max: 5 * time.Second}
+
+	require.Equal(t, time.Second, backoff.Next(1))
+	require.Equal(t, 2*time.Second, backoff.Next(2))
+	require.Equal(t, 4*time.Second, backoff.Next(3))
+	require.Equal(t, 5*time.Second, backoff.Next(4))
+}
+
+func TestJitterBackoff_Next(t *testing.T) {
+	backoff := &JitterBackoff{Backoff: &ConstantBackoff{Interval: 10 * time.Second}, Factor: 0.2}
+
+	for i := 1; i <= 100; i++ {
+		d := backoff.Next(i)
+		require.True(t, 8*time.Second <= d && d <= 12*time.Second, "unexpected duration: %s", d)
+	}
+}
+
+func TestWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	err := Wait(ctx, time.Minute)
+	require.ErrorIs(t, err, context.Canceled)
+	require.True(t, time.Since(start) < time.Second)
+}
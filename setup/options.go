@@ -57,28 +57,63 @@ type Options struct {
 	DeleteRetryInterval time.Duration
 	// sacloud.StateWaiterによるステート待ちの間隔
 	PollingInterval time.Duration
+	// ProvisioningBackoff プロビジョニングAPI呼び出しのリトライ間隔の算出方法、省略時はProvisioningRetryIntervalの固定間隔
+	ProvisioningBackoff Backoff
+	// DeleteBackoff 削除API呼び出しのリトライ間隔の算出方法、省略時はDeleteRetryIntervalの固定間隔
+	DeleteBackoff Backoff
+	// OnRetry リトライを行う都度呼ばれるコールバック
+	OnRetry func(attempt *RetryAttempt)
 }
 
+// Init 未設定の項目にデフォルト値を設定する
+//
+// Deprecated: レシーバ自体を変更するため1つのOptionsを複数箇所で共有できません。代わりにWithDefaultsを利用してください
 func (o *Options) Init() {
-	if o.NICUpdateWaitDuration == time.Duration(0) {
-		o.NICUpdateWaitDuration = DefaultNICUpdateWaitDuration
+	*o = *o.WithDefaults()
+}
+
+// WithDefaults 未設定の項目にデフォルト値を設定したコピーを返す
+//
+// レシーバは変更されないため、1つのOptionsを複数のビルダー間で共有できる。
+// レシーバがnilの場合はデフォルト値のみを持つOptionsを返す
+func (o *Options) WithDefaults() *Options {
+	opts := &Options{}
+	if o != nil {
+		*opts = *o
+	}
+
+	if opts.NICUpdateWaitDuration == time.Duration(0) {
+		opts.NICUpdateWaitDuration = DefaultNICUpdateWaitDuration
 	}
-	if o.RetryCount <= 0 {
-		o.RetryCount = DefaultMaxRetryCount
+	if opts.RetryCount <= 0 {
+		opts.RetryCount = DefaultMaxRetryCount
 	}
-	if o.DeleteRetryCount <= 0 {
-		o.DeleteRetryCount = DefaultDeleteRetryCount
+	if opts.DeleteRetryCount <= 0 {
+		opts.DeleteRetryCount = DefaultDeleteRetryCount
 	}
-	if o.DeleteRetryInterval <= 0 {
-		o.DeleteRetryInterval = DefaultDeleteWaitInterval
+	if opts.DeleteRetryInterval <= 0 {
+		opts.DeleteRetryInterval = DefaultDeleteWaitInterval
 	}
-	if o.ProvisioningRetryCount <= 0 {
-		o.ProvisioningRetryCount = DefaultProvisioningRetryCount
+	if opts.ProvisioningRetryCount <= 0 {
+		opts.ProvisioningRetryCount = DefaultProvisioningRetryCount
 	}
-	if o.ProvisioningRetryInterval <= 0 {
-		o.ProvisioningRetryInterval = DefaultProvisioningWaitInterval
+	if opts.ProvisioningRetryInterval <= 0 {
+		opts.ProvisioningRetryInterval = DefaultProvisioningWaitInterval
 	}
-	if o.PollingInterval <= 0 {
-		o.PollingInterval = DefaultPollingInterval
+	if opts.PollingInterval <= 0 {
+		opts.PollingInterval = DefaultPollingInterval
+	}
+	if opts.ProvisioningBackoff == nil {
+		opts.ProvisioningBackoff = &ConstantBackoff{Interval: opts.ProvisioningRetryInterval}
+	}
+	if opts.DeleteBackoff == nil {
+		opts.DeleteBackoff = &ConstantBackoff{Interval: opts.DeleteRetryInterval}
+	}
+	return opts
+}
+
+func (o *Options) onRetry(attempt *RetryAttempt) {
+	if o.OnRetry != nil {
+		o.OnRetry(attempt)
 	}
 }
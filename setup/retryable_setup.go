@@ -23,11 +23,31 @@ import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/accessor"
 	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
 )
 
 // MaxRetryCountExceededError リトライ最大数超過エラー
+//
+// 互換性のためにerrorの別名として残している。
+// Setupが返す値の実体は*RetryCountExceededErrorのため、最大試行回数などを参照する場合はerrors.Asを利用する
 type MaxRetryCountExceededError error
 
+// RetryCountExceededError リトライ最大数超過エラーの実体
+type RetryCountExceededError struct {
+	MaxAttempts int // 最大試行回数
+}
+
+// Error errorインターフェースの実装
+func (e *RetryCountExceededError) Error() string {
+	return fmt.Sprintf("max retry count exceeded: attempted %d times", e.MaxAttempts)
+}
+
+// ErrorKind エラー種別
+func (e *RetryCountExceededError) ErrorKind() service.ErrorKind {
+	return service.ErrorKindMaxRetryCountExceeded
+}
+
 // CreateFunc リソース作成関数
 type CreateFunc func(ctx context.Context, zone string) (accessor.ID, error)
 
@@ -45,6 +65,41 @@ type DeleteFunc func(ctx context.Context, zone string, id types.ID) error
 // ReadFunc リソース起動待ちなどで利用するリソースのRead用Func
 type ReadFunc func(ctx context.Context, zone string, id types.ID) (interface{}, error)
 
+// RetryPhase リトライ対象の処理
+type RetryPhase string
+
+const (
+	// RetryPhaseSetup リソースの削除&再作成
+	RetryPhaseSetup = RetryPhase("setup")
+	// RetryPhaseProvisioning リソース起動前のプロビジョニング
+	RetryPhaseProvisioning = RetryPhase("provisioning")
+	// RetryPhaseDelete コピー失敗時のリソース削除
+	RetryPhaseDelete = RetryPhase("delete")
+)
+
+// progressPhase 進捗イベントで通知する際のPhase
+func (p RetryPhase) progressPhase() progress.Phase {
+	switch p {
+	case RetryPhaseSetup:
+		return progress.PhaseSetup
+	case RetryPhaseProvisioning:
+		return progress.PhaseProvisioning
+	case RetryPhaseDelete:
+		return progress.PhaseDelete
+	default:
+		return progress.Phase(p)
+	}
+}
+
+// RetryAttempt Options.OnRetryに渡されるリトライ情報
+type RetryAttempt struct {
+	Phase       RetryPhase
+	Attempt     int           // 失敗した試行の回数(1始まり)
+	MaxAttempts int           // 最大試行回数
+	Wait        time.Duration // 次の試行までの待ち時間
+	Err         error         // 直前の試行でのエラー
+}
+
 // RetryableSetup リソース作成時にコピー待ちや起動待ちが必要なリソースのビルダー。
 //
 // リソースのビルドの際、必要に応じてリトライ(リソースの削除&再作成)を行う。
@@ -62,6 +117,9 @@ type RetryableSetup struct {
 	// Read リソース起動待ち関数
 	Read ReadFunc
 
+	// Kind 進捗イベントで通知するリソース種別
+	Kind string
+
 	// Options .
 	Options *Options
 }
@@ -72,11 +130,18 @@ func (r *RetryableSetup) Setup(ctx context.Context, zone string) (interface{}, e
 		return nil, errors.New("failed: Read is required when IsWaitForCopy or IsWaitForUp is true")
 	}
 
-	r.init()
+	// r.Optionsは複数のRetryableSetupで共有される可能性があるため変更しない
+	options := r.Options.WithDefaults()
+	maxAttempts := options.RetryCount + 1
 
 	var created interface{}
-	for r.Options.RetryCount+1 > 0 {
-		r.Options.RetryCount--
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if attempt > 1 {
+			r.retry(ctx, r.resource(zone, types.ID(0)), options, &RetryAttempt{Phase: RetryPhaseSetup, Attempt: attempt - 1, MaxAttempts: maxAttempts})
+		}
 
 		// リソース作成
 		target, err := r.createResource(ctx, zone)
@@ -84,11 +149,12 @@ func (r *RetryableSetup) Setup(ctx context.Context, zone string) (interface{}, e
 			return nil, err
 		}
 		id := target.GetID()
+		progress.Emit(ctx, &progress.ResourceCreated{Resource: r.resource(zone, id)})
 
 		// コピー待ち
 		if r.IsWaitForCopy {
 			// コピー待ち、Failedになった場合はリソース削除
-			state, err := r.waitForCopyWithCleanup(ctx, zone, id)
+			state, err := r.waitForCopyWithCleanup(ctx, zone, id, options)
 			if err != nil {
 				return state, err
 			}
@@ -100,12 +166,12 @@ func (r *RetryableSetup) Setup(ctx context.Context, zone string) (interface{}, e
 		}
 
 		// 起動前の設定など
-		if err := r.provisionBeforeUp(ctx, zone, id, created); err != nil {
+		if err := r.provisionBeforeUp(ctx, zone, id, created, options); err != nil {
 			return created, err
 		}
 
 		// 起動待ち
-		if err := r.waitForUp(ctx, zone, id, created); err != nil {
+		if err := r.waitForUp(ctx, zone, id, created, options); err != nil {
 			return created, err
 		}
 
@@ -115,18 +181,11 @@ func (r *RetryableSetup) Setup(ctx context.Context, zone string) (interface{}, e
 	}
 
 	if created == nil {
-		return nil, MaxRetryCountExceededError(fmt.Errorf("max retry count exceeded"))
+		return nil, MaxRetryCountExceededError(&RetryCountExceededError{MaxAttempts: maxAttempts})
 	}
 	return created, nil
 }
 
-func (r *RetryableSetup) init() {
-	if r.Options == nil {
-		r.Options = &Options{}
-	}
-	r.Options.Init()
-}
-
 func (r *RetryableSetup) createResource(ctx context.Context, zone string) (accessor.ID, error) {
 	if r.Create == nil {
 		return nil, fmt.Errorf("create func is required")
@@ -134,11 +193,12 @@ func (r *RetryableSetup) createResource(ctx context.Context, zone string) (acces
 	return r.Create(ctx, zone)
 }
 
-func (r *RetryableSetup) waitForCopyWithCleanup(ctx context.Context, zone string, id types.ID) (interface{}, error) {
+func (r *RetryableSetup) waitForCopyWithCleanup(ctx context.Context, zone string, id types.ID, options *Options) (interface{}, error) {
+	resource := r.resource(zone, id)
 	waiter := &iaas.StatePollingWaiter{
-		ReadFunc: func() (interface{}, error) {
+		ReadFunc: progress.ObserveState(ctx, resource, func() (interface{}, error) {
 			return r.Read(ctx, zone, id)
-		},
+		}),
 		TargetAvailability: []types.EAvailability{
 			types.Availabilities.Available,
 			types.Availabilities.Failed,
@@ -150,10 +210,11 @@ func (r *RetryableSetup) waitForCopyWithCleanup(ctx context.Context, zone string
 			types.Availabilities.Transferring,
 			types.Availabilities.Discontinued,
 		},
-		Interval: r.Options.PollingInterval,
+		Interval: options.PollingInterval,
 	}
 
 	// wait
+	done := progress.StartPhase(ctx, resource, progress.PhaseWaitForCopy)
 	compChan, progressChan, errChan := waiter.WaitForStateAsync(ctx)
 	var state interface{}
 	var err error
@@ -171,6 +232,7 @@ loop:
 			break loop
 		}
 	}
+	done(err)
 
 	if state != nil {
 		// Availabilityを持ち、Failedになっていた場合はリソースを削除してリトライ
@@ -178,9 +240,16 @@ loop:
 			if f.GetAvailability().IsFailed() {
 				// FailedになったばかりだとDelete APIが失敗する(コピー進行中など)場合があるため、
 				// 任意の回数リトライ&待機を行う
-				for i := 0; i < r.Options.DeleteRetryCount; i++ {
-					time.Sleep(r.Options.DeleteRetryInterval)
+				for i := 1; i <= options.DeleteRetryCount; i++ {
+					wait := options.DeleteBackoff.Next(i)
+					if i > 1 {
+						r.retry(ctx, resource, options, &RetryAttempt{Phase: RetryPhaseDelete, Attempt: i - 1, MaxAttempts: options.DeleteRetryCount, Wait: wait, Err: err})
+					}
+					if err := Wait(ctx, wait); err != nil {
+						return nil, err
+					}
 					if err = r.Delete(ctx, zone, id); err == nil {
+						progress.Emit(ctx, &progress.ResourceDeleted{Resource: resource})
 						break
 					}
 				}
@@ -198,26 +267,38 @@ loop:
 	return nil, nil
 }
 
-func (r *RetryableSetup) provisionBeforeUp(ctx context.Context, zone string, id types.ID, created interface{}) error {
+func (r *RetryableSetup) provisionBeforeUp(ctx context.Context, zone string, id types.ID, created interface{}, options *Options) error {
 	if r.ProvisionBeforeUp != nil && created != nil {
 		var err error
-		for i := 0; i < r.Options.ProvisioningRetryCount; i++ {
+		done := progress.StartPhase(ctx, r.resource(zone, id), progress.PhaseProvisioning)
+		defer func() { done(err) }()
+
+		for i := 1; i <= options.ProvisioningRetryCount; i++ {
 			if err = r.ProvisionBeforeUp(ctx, zone, id, created); err == nil {
 				break
 			}
-			time.Sleep(r.Options.ProvisioningRetryInterval)
+			if i == options.ProvisioningRetryCount {
+				break
+			}
+
+			wait := options.ProvisioningBackoff.Next(i)
+			r.retry(ctx, r.resource(zone, id), options, &RetryAttempt{Phase: RetryPhaseProvisioning, Attempt: i, MaxAttempts: options.ProvisioningRetryCount, Wait: wait, Err: err})
+			if err = Wait(ctx, wait); err != nil {
+				return err
+			}
 		}
 		return err
 	}
 	return nil
 }
 
-func (r *RetryableSetup) waitForUp(ctx context.Context, zone string, id types.ID, created interface{}) error {
+func (r *RetryableSetup) waitForUp(ctx context.Context, zone string, id types.ID, created interface{}, options *Options) error {
 	if r.IsWaitForUp && created != nil {
+		resource := r.resource(zone, id)
 		waiter := &iaas.StatePollingWaiter{
-			ReadFunc: func() (interface{}, error) {
+			ReadFunc: progress.ObserveState(ctx, resource, func() (interface{}, error) {
 				return r.Read(ctx, zone, id)
-			},
+			}),
 			TargetAvailability: []types.EAvailability{
 				types.Availabilities.Available,
 			},
@@ -236,10 +317,28 @@ func (r *RetryableSetup) waitForUp(ctx context.Context, zone string, id types.ID
 				types.ServerInstanceStatuses.Cleaning,
 				types.ServerInstanceStatuses.Down,
 			},
-			Interval: r.Options.PollingInterval,
+			Interval: options.PollingInterval,
 		}
+		done := progress.StartPhase(ctx, resource, progress.PhaseWaitForBoot)
 		_, err := waiter.WaitForState(ctx)
+		done(err)
 		return err
 	}
 	return nil
 }
+
+func (r *RetryableSetup) resource(zone string, id types.ID) progress.Resource {
+	return progress.Resource{Kind: r.Kind, Zone: zone, ID: id}
+}
+
+func (r *RetryableSetup) retry(ctx context.Context, resource progress.Resource, options *Options, attempt *RetryAttempt) {
+	options.onRetry(attempt)
+	progress.Emit(ctx, &progress.RetryAttempt{
+		Resource:    resource,
+		Phase:       attempt.Phase.progressPhase(),
+		Attempt:     attempt.Attempt,
+		MaxAttempts: attempt.MaxAttempts,
+		Wait:        attempt.Wait,
+		Err:         attempt.Err,
+	})
+}
@@ -22,6 +22,8 @@ import (
 
 	"github.com/sacloud/iaas-api-go/accessor"
 	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
 	"github.com/stretchr/testify/require"
 )
 
@@ -151,10 +153,139 @@ func TestRetryableSetup_Setup(t *testing.T) {
 			require.Error(t, err)
 			_, ok := err.(MaxRetryCountExceededError)
 			require.True(t, ok)
+			require.True(t, service.IsMaxRetryCountExceededError(err))
+			var exceeded *RetryCountExceededError
+			require.ErrorAs(t, err, &exceeded)
+			require.Equal(t, 4, exceeded.MaxAttempts)
 		})
 	})
 }
 
+func TestRetryableSetup_Options(t *testing.T) {
+	ctx := context.Background()
+	zone := "tk1v"
+
+	t.Run("options are not modified", func(t *testing.T) {
+		options := &Options{
+			RetryCount:                3,
+			ProvisioningRetryInterval: time.Millisecond,
+			DeleteRetryInterval:       time.Millisecond,
+			PollingInterval:           time.Millisecond,
+		}
+		for i := 0; i < 2; i++ {
+			retryable := &RetryableSetup{
+				Create: func(context.Context, string) (id accessor.ID, e error) {
+					return &dummyIDAccessor{id: 1}, nil
+				},
+				IsWaitForCopy: true,
+				Delete: func(context.Context, string, types.ID) error {
+					return nil
+				},
+				Read:    withErrorReadFunc(nil, 3),
+				Options: options,
+			}
+			_, err := retryable.Setup(ctx, zone)
+			require.NoError(t, err)
+		}
+		require.Equal(t, 3, options.RetryCount)
+		require.Zero(t, options.DeleteRetryCount)
+	})
+
+	t.Run("retry callback and backoff", func(t *testing.T) {
+		var attempts []*RetryAttempt
+		provisioningErr := fmt.Errorf("error")
+		retryable := &RetryableSetup{
+			Create: func(context.Context, string) (id accessor.ID, e error) {
+				return &dummyIDAccessor{id: 1}, nil
+			},
+			ProvisionBeforeUp: func(context.Context, string, types.ID, interface{}) error {
+				return provisioningErr
+			},
+			Options: &Options{
+				ProvisioningRetryCount: 3,
+				ProvisioningBackoff:    &ExponentialBackoff{Initial: time.Millisecond},
+				OnRetry: func(attempt *RetryAttempt) {
+					attempts = append(attempts, attempt)
+				},
+			},
+		}
+		_, err := retryable.Setup(ctx, zone)
+		require.Equal(t, provisioningErr, err)
+		require.Equal(t, []*RetryAttempt{
+			{Phase: RetryPhaseProvisioning, Attempt: 1, MaxAttempts: 3, Wait: time.Millisecond, Err: provisioningErr},
+			{Phase: RetryPhaseProvisioning, Attempt: 2, MaxAttempts: 3, Wait: 2 * time.Millisecond, Err: provisioningErr},
+		}, attempts)
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		retryable := &RetryableSetup{
+			Create: func(context.Context, string) (id accessor.ID, e error) {
+				return &dummyIDAccessor{id: 1}, nil
+			},
+			ProvisionBeforeUp: func(context.Context, string, types.ID, interface{}) error {
+				cancel()
+				return fmt.Errorf("error")
+			},
+			Options: &Options{
+				ProvisioningRetryInterval: time.Hour,
+			},
+		}
+		_, err := retryable.Setup(ctx, zone)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestRetryableSetup_ProgressEvents(t *testing.T) {
+	var events []progress.Event
+	ctx := progress.WithObserver(context.Background(), progress.ObserverFunc(func(_ context.Context, event progress.Event) {
+		events = append(events, event)
+	}))
+
+	retryable := &RetryableSetup{
+		Create: func(context.Context, string) (id accessor.ID, e error) {
+			return &dummyIDAccessor{id: 1}, nil
+		},
+		IsWaitForCopy: true,
+		Delete: func(context.Context, string, types.ID) error {
+			return nil
+		},
+		Read: withErrorReadFunc(nil, 2),
+		Kind: "Dummy",
+		Options: &Options{
+			RetryCount:          1,
+			DeleteRetryInterval: time.Millisecond,
+			PollingInterval:     time.Millisecond,
+		},
+	}
+	_, err := retryable.Setup(ctx, "tk1v")
+	require.NoError(t, err)
+
+	var eventTypes []progress.EventType
+	for _, e := range events {
+		eventTypes = append(eventTypes, e.EventType())
+		require.Equal(t, "Dummy", e.Target().Kind)
+	}
+	require.Equal(t, []progress.EventType{
+		progress.EventTypeResourceCreated,
+		progress.EventTypePhaseStarted,
+		progress.EventTypeAvailabilityChanged, // failed
+		progress.EventTypePhaseFinished,
+		progress.EventTypeResourceDeleted,
+		progress.EventTypeRetryAttempt,
+		progress.EventTypeResourceCreated,
+		progress.EventTypePhaseStarted,
+		progress.EventTypeAvailabilityChanged, // available
+		progress.EventTypePhaseFinished,
+	}, eventTypes)
+
+	for _, e := range events {
+		if attempt, ok := e.(*progress.RetryAttempt); ok {
+			require.Equal(t, progress.PhaseSetup, attempt.Phase)
+		}
+	}
+}
+
 func withErrorReadFunc(readFunc ReadFunc, errCount int) ReadFunc {
 	maxErr := errCount
 	return func(ctx context.Context, zone string, id types.ID) (interface{}, error) {
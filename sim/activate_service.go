@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Activate(req *ActivateRequest) error {
 	return s.ActivateWithContext(context.Background(), req)
 }
 
-func (s *Service) ActivateWithContext(ctx context.Context, req *ActivateRequest) error {
+func (s *Service) ActivateWithContext(ctx context.Context, req *ActivateRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Activate", Resource: "SIM", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "Activate", Resource: "SIM", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewSIMOp(s.caller)
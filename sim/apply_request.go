@@ -17,6 +17,7 @@ package sim
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/sim/builder"
 	"github.com/sacloud/packages-go/validate"
 )
 
@@ -36,3 +37,19 @@ type ApplyRequest struct {
 func (req *ApplyRequest) Validate() error {
 	return validate.New().Struct(req)
 }
+
+// Builder リクエストの内容からSIMのビルダーを組み立てて返す
+func (req *ApplyRequest) Builder(caller iaas.APICaller) *builder.Builder {
+	return &builder.Builder{
+		Name:        req.Name,
+		Description: req.Description,
+		Tags:        req.Tags,
+		IconID:      req.IconID,
+		ICCID:       req.ICCID,
+		PassCode:    req.PassCode,
+		Activate:    req.Activate,
+		IMEI:        req.IMEI,
+		Carrier:     req.Carriers,
+		Client:      builder.NewAPIClient(caller),
+	}
+}
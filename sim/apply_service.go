@@ -18,30 +18,22 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
-	builder2 "github.com/sacloud/iaas-service-go/sim/builder"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Apply(req *ApplyRequest) (*iaas.SIM, error) {
 	return s.ApplyWithContext(context.Background(), req)
 }
 
-func (s *Service) ApplyWithContext(ctx context.Context, req *ApplyRequest) (*iaas.SIM, error) {
+func (s *Service) ApplyWithContext(ctx context.Context, req *ApplyRequest) (_ *iaas.SIM, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Apply", Resource: "SIM", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Apply", Resource: "SIM", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
-	builder := &builder2.Builder{
-		Name:        req.Name,
-		Description: req.Description,
-		Tags:        req.Tags,
-		IconID:      req.IconID,
-		ICCID:       req.ICCID,
-		PassCode:    req.PassCode,
-		Activate:    req.Activate,
-		IMEI:        req.IMEI,
-		Carrier:     req.Carriers,
-		Client:      builder2.NewAPIClient(s.caller),
-	}
+	builder := req.Builder(s.caller)
 	if err := builder.Validate(ctx); err != nil {
 		return nil, err
 	}
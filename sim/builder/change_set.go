@@ -0,0 +1,71 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+
+	"github.com/sacloud/iaas-api-go/helper/query"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSet Build/Updateを行った場合の変更内容を返す
+//
+// idが空の場合はBuild、それ以外の場合はUpdateを行った場合の変更内容を返します。
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context, id types.ID) (*service.ChangeSet, error) {
+	if err := b.Validate(ctx); err != nil {
+		return nil, err
+	}
+
+	if id.IsEmpty() {
+		cs := service.NewCreateChangeSet()
+		cs.Create("Name", b.Name)
+		cs.Create("Description", b.Description)
+		cs.Create("Tags", b.Tags)
+		cs.Create("IconID", b.IconID)
+		cs.Create("ICCID", b.ICCID)
+		cs.SetSensitive("PassCode", nil, b.PassCode)
+		cs.Create("Carrier", b.Carrier)
+		cs.Create("Activate", b.Activate)
+		cs.Create("IMEI", b.IMEI)
+		return cs, nil
+	}
+
+	sim, err := query.FindSIMByID(ctx, b.Client.SIM, id)
+	if err != nil {
+		return nil, err
+	}
+	carriers, err := b.Client.SIM.GetNetworkOperator(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := service.NewUpdateChangeSet(id)
+	cs.Update("Name", sim.Name, b.Name)
+	cs.Update("Description", sim.Description, b.Description)
+	cs.Update("Tags", sim.Tags, b.Tags)
+	cs.Update("IconID", sim.IconID, b.IconID)
+	cs.Update("Carrier", carriers, b.Carrier)
+	cs.Update("Activate", sim.Info.Activated, b.Activate)
+
+	currentIMEI := ""
+	if sim.Info.IMEILock {
+		currentIMEI = sim.Info.IMEI
+	}
+	cs.Update("IMEI", currentIMEI, b.IMEI)
+	return cs, nil
+}
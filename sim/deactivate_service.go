@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Deactivate(req *DeactivateRequest) error {
 	return s.DeactivateWithContext(context.Background(), req)
 }
 
-func (s *Service) DeactivateWithContext(ctx context.Context, req *DeactivateRequest) error {
+func (s *Service) DeactivateWithContext(ctx context.Context, req *DeactivateRequest) (err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Deactivate", Resource: "SIM", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return err
+		return &service.Error{Op: "Deactivate", Resource: "SIM", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewSIMOp(s.caller)
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Logs(req *LogsRequest) ([]*iaas.SIMLog, error) {
 	return s.LogsWithContext(context.Background(), req)
 }
 
-func (s *Service) LogsWithContext(ctx context.Context, req *LogsRequest) ([]*iaas.SIMLog, error) {
+func (s *Service) LogsWithContext(ctx context.Context, req *LogsRequest) (_ []*iaas.SIMLog, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Logs", Resource: "SIM", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Logs", Resource: "SIM", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewSIMOp(s.caller)
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorSIM(req *MonitorSIMRequest) ([]*iaas.MonitorLinkValue,
 	return s.MonitorSIMWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorSIMWithContext(ctx context.Context, req *MonitorSIMRequest) ([]*iaas.MonitorLinkValue, error) {
+func (s *Service) MonitorSIMWithContext(ctx context.Context, req *MonitorSIMRequest) (_ []*iaas.MonitorLinkValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorSIM", Resource: "SIM", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorSIM", Resource: "SIM", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewSIMOp(s.caller)
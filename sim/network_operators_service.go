@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) NetworkOperators(req *NetworkOperatorsRequest) ([]*iaas.SIMNetworkOperatorConfig, error) {
 	return s.NetworkOperatorsWithContext(context.Background(), req)
 }
 
-func (s *Service) NetworkOperatorsWithContext(ctx context.Context, req *NetworkOperatorsRequest) ([]*iaas.SIMNetworkOperatorConfig, error) {
+func (s *Service) NetworkOperatorsWithContext(ctx context.Context, req *NetworkOperatorsRequest) (_ []*iaas.SIMNetworkOperatorConfig, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "NetworkOperators", Resource: "SIM", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "NetworkOperators", Resource: "SIM", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewSIMOp(s.caller)
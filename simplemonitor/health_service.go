@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Health(req *HealthRequest) (*iaas.SimpleMonitorHealthStatus, error) {
 	return s.HealthWithContext(context.Background(), req)
 }
 
-func (s *Service) HealthWithContext(ctx context.Context, req *HealthRequest) (*iaas.SimpleMonitorHealthStatus, error) {
+func (s *Service) HealthWithContext(ctx context.Context, req *HealthRequest) (_ *iaas.SimpleMonitorHealthStatus, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Health", Resource: "SimpleMonitor", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Health", Resource: "SimpleMonitor", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewSimpleMonitorOp(s.caller)
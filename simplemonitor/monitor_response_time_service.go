@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 )
 
@@ -25,9 +26,12 @@ func (s *Service) MonitorResponseTime(req *MonitorResponseTimeRequest) ([]*iaas.
 	return s.MonitorResponseTimeWithContext(context.Background(), req)
 }
 
-func (s *Service) MonitorResponseTimeWithContext(ctx context.Context, req *MonitorResponseTimeRequest) ([]*iaas.MonitorResponseTimeSecValue, error) {
+func (s *Service) MonitorResponseTimeWithContext(ctx context.Context, req *MonitorResponseTimeRequest) (_ []*iaas.MonitorResponseTimeSecValue, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "MonitorResponseTime", Resource: "SimpleMonitor", ID: req.ID, Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "MonitorResponseTime", Resource: "SimpleMonitor", ID: req.ID, Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewSimpleMonitorOp(s.caller)
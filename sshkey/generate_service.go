@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) Generate(req *GenerateRequest) (*iaas.SSHKeyGenerated, error) {
 	return s.GenerateWithContext(context.Background(), req)
 }
 
-func (s *Service) GenerateWithContext(ctx context.Context, req *GenerateRequest) (*iaas.SSHKeyGenerated, error) {
+func (s *Service) GenerateWithContext(ctx context.Context, req *GenerateRequest) (_ *iaas.SSHKeyGenerated, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Generate", Resource: "SSHKey", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "Generate", Resource: "SSHKey", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	client := iaas.NewSSHKeyOp(s.caller)
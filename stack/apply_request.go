@@ -0,0 +1,59 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+
+	"github.com/sacloud/packages-go/validate"
+)
+
+type ApplyRequest struct {
+	Resources []*Resource `validate:"required,dive"`
+	State     *State      // 前回のApply結果のState、省略時は全てのリソースが新規作成される
+
+	Prune         bool // trueの場合、Stateに存在しResourcesに存在しないリソースを削除する
+	ForceShutdown bool // Pruneでの削除時に電源OFF(強制終了)してから削除するか
+	Parallelism   int  `validate:"min=0"` // 同時に処理するリソース数、省略時はDefaultParallelism
+}
+
+func (req *ApplyRequest) Validate() error {
+	if err := validate.New().Struct(req); err != nil {
+		return err
+	}
+	_, _, err := req.graph()
+	return err
+}
+
+// graph Resourcesから依存グラフと各リソースのhandlerを組み立てる
+func (req *ApplyRequest) graph() (*graph, map[string]*handler, error) {
+	g := newGraph()
+	handlers := map[string]*handler{}
+	for _, r := range req.Resources {
+		if _, ok := handlers[r.Name]; ok {
+			return nil, nil, fmt.Errorf("duplicate resource name: %q", r.Name)
+		}
+		h, err := handlerOf(r.Spec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("resource %q: %w", r.Name, err)
+		}
+		handlers[r.Name] = h
+		g.add(r.Name, r.dependencies())
+	}
+	if err := g.validate(); err != nil {
+		return nil, nil, err
+	}
+	return g, handlers, nil
+}
@@ -0,0 +1,184 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Apply Resourcesを依存関係に従って作成/更新する
+//
+// 依存関係のないリソース同士は並列に処理される。
+// 一部のリソースが失敗した場合でも、処理結果と更新後のStateは*Errorと共に返される。
+func (s *Service) Apply(req *ApplyRequest) (*Result, error) {
+	return s.ApplyWithContext(context.Background(), req)
+}
+
+func (s *Service) ApplyWithContext(ctx context.Context, req *ApplyRequest) (_ *Result, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Apply", Resource: "Stack", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Apply", Resource: "Stack", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	g, handlers, err := req.graph()
+	if err != nil {
+		return nil, &service.Error{Op: "Apply", Resource: "Stack", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	resources := map[string]*Resource{}
+	results := map[string]*ResourceResult{}
+	currents := map[string]*applyResult{}
+	for _, r := range req.Resources {
+		h := handlers[r.Name]
+		resources[r.Name] = r
+		result := &ResourceResult{Name: r.Name, Kind: h.kind, Zone: h.zone, ID: h.id, Action: service.ChangeActionCreate}
+		current := &applyResult{}
+		if st := req.State.lookup(r.Name); st != nil && st.Kind == h.kind && (result.ID.IsEmpty() || result.ID == st.ID) {
+			result.ID = st.ID
+			current.DiskIDs = st.DiskIDs
+		}
+		if !result.ID.IsEmpty() {
+			result.Action = service.ChangeActionUpdate
+		}
+		results[r.Name] = result
+		currents[r.Name] = current
+	}
+
+	appliedResults := map[string]*applyResult{}
+	var mu sync.Mutex
+	errs := g.run(ctx, req.Parallelism, func(ctx context.Context, name string) error {
+		r := resources[name]
+		mu.Lock()
+		result := results[name]
+		for _, ref := range r.Refs {
+			if err := setField(r.Spec, ref.Field, results[ref.Resource].ID); err != nil {
+				mu.Unlock()
+				return err
+			}
+		}
+		current := currents[name]
+		current.ID = result.ID
+		mu.Unlock()
+
+		v, err := handlers[name].apply(ctx, s.caller, current)
+		if v != nil {
+			mu.Lock()
+			result.ID = v.ID
+			appliedResults[name] = v
+			mu.Unlock()
+		}
+		return err
+	})
+
+	state := &State{Resources: map[string]*StateResource{}}
+	var applied []*ResourceResult
+	failed := false
+	for _, r := range req.Resources {
+		result := results[r.Name]
+		result.Err = errs[r.Name]
+		applied = append(applied, result)
+
+		v := appliedResults[r.Name]
+		if result.Err != nil {
+			failed = true
+			// 作成後にエラーとなったリソースも次回のApply/Deleteの対象とするため記録する
+			if v == nil || v.ID.IsEmpty() {
+				if st := req.State.lookup(r.Name); st != nil {
+					state.Resources[r.Name] = st
+				}
+				continue
+			}
+		}
+		state.Resources[r.Name] = &StateResource{
+			Kind:      result.Kind,
+			Zone:      result.Zone,
+			ID:        result.ID,
+			DiskIDs:   v.DiskIDs,
+			DependsOn: r.dependencies(),
+		}
+	}
+
+	// Resourcesから除かれたリソース
+	var orphans []string
+	if req.State != nil {
+		for name, st := range req.State.Resources {
+			if _, ok := resources[name]; !ok {
+				orphans = append(orphans, name)
+				state.Resources[name] = st
+			}
+		}
+	}
+	sort.Strings(orphans)
+
+	// Applyに失敗した場合は依存関係が不完全な可能性があるため削除は行わない
+	if req.Prune && !failed && len(orphans) > 0 {
+		deleted := s.deleteResources(ctx, state, orphans, req.ForceShutdown, req.Parallelism)
+		applied = append(applied, deleted...)
+	}
+
+	return &Result{Resources: applied, State: state}, errorFromResults(applied)
+}
+
+// deleteResources stateに記録されたリソースのうちnamesで指定されたものを依存関係の逆順に削除する
+//
+// 削除に成功したリソースはstateから除かれる。
+func (s *Service) deleteResources(ctx context.Context, state *State, names []string, force bool, parallelism int) []*ResourceResult {
+	targets := map[string]bool{}
+	for _, name := range names {
+		targets[name] = true
+	}
+
+	g := newGraph()
+	for _, name := range names {
+		var deps []string
+		for _, dep := range state.Resources[name].DependsOn {
+			if targets[dep] {
+				deps = append(deps, dep)
+			}
+		}
+		g.add(name, deps)
+	}
+
+	errs := g.reverse().run(ctx, parallelism, func(ctx context.Context, name string) error {
+		st := state.Resources[name]
+		if st.ID.IsEmpty() {
+			return nil
+		}
+		return deleteResource(ctx, s.caller, st, force)
+	})
+
+	var results []*ResourceResult
+	for _, name := range names {
+		st := state.Resources[name]
+		results = append(results, &ResourceResult{
+			Name:   name,
+			Kind:   st.Kind,
+			Zone:   st.Zone,
+			ID:     st.ID,
+			Action: service.ChangeActionDelete,
+			Err:    errs[name],
+		})
+		if errs[name] == nil {
+			delete(state.Resources, name)
+		}
+	}
+	return results
+}
@@ -0,0 +1,298 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/dns"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/sacloud/iaas-service-go/swytch"
+	"github.com/stretchr/testify/require"
+)
+
+func testResources(name string) []*Resource {
+	zone := testutil.TestZone()
+	return []*Resource{
+		{
+			Name: "web",
+			Refs: []*Ref{{Field: "NetworkInterfaces[1].Upstream", Resource: "internal"}},
+			Spec: &server.ApplyRequest{
+				Zone:     zone,
+				Name:     name,
+				CPU:      1,
+				MemoryGB: 1,
+				NetworkInterfaces: []*server.NetworkInterface{
+					{Upstream: "shared"},
+					{},
+				},
+			},
+		},
+		{
+			Name: "internal",
+			Spec: &swytch.CreateRequest{Zone: zone, Name: name},
+		},
+		{
+			Name:      "dns",
+			DependsOn: []string{"web"},
+			Spec:      &dns.CreateRequest{Name: name + ".example.com"},
+		},
+	}
+}
+
+func TestService_Apply(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("stack-apply")
+	zone := testutil.TestZone()
+	svc := New(caller)
+
+	// create
+	result, err := svc.Apply(&ApplyRequest{Resources: testResources(name)})
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 3)
+	for _, r := range result.Resources {
+		require.Equal(t, service.ChangeActionCreate, r.Action)
+		require.False(t, r.ID.IsEmpty())
+	}
+	state := result.State
+	require.Len(t, state.Resources, 3)
+	require.Equal(t, "Server", state.Resources["web"].Kind)
+	require.Equal(t, []string{"internal"}, state.Resources["web"].DependsOn)
+
+	sv, err := iaas.NewServerOp(caller).Read(ctx, zone, state.Resources["web"].ID)
+	require.NoError(t, err)
+	require.Len(t, sv.Interfaces, 2)
+	require.Equal(t, state.Resources["internal"].ID, sv.Interfaces[1].SwitchID)
+
+	// update
+	result, err = svc.Apply(&ApplyRequest{Resources: testResources(name), State: state})
+	require.NoError(t, err)
+	for _, r := range result.Resources {
+		require.Equal(t, service.ChangeActionUpdate, r.Action)
+		require.Equal(t, state.Resources[r.Name].ID, r.ID)
+	}
+	state = result.State
+
+	// prune
+	resources := testResources(name)[:2]
+	result, err = svc.Apply(&ApplyRequest{Resources: resources, State: state, Prune: true})
+	require.NoError(t, err)
+	require.Len(t, result.Resources, 3)
+	require.Equal(t, service.ChangeActionDelete, result.Resources[2].Action)
+	require.Equal(t, "dns", result.Resources[2].Name)
+	require.NotContains(t, result.State.Resources, "dns")
+	_, err = iaas.NewDNSOp(caller).Read(ctx, state.Resources["dns"].ID)
+	require.True(t, iaas.IsNotFoundError(err))
+	state = result.State
+
+	// delete
+	result, err = svc.Delete(&DeleteRequest{State: state, ForceShutdown: true})
+	require.NoError(t, err)
+	require.Empty(t, result.State.Resources)
+	_, err = iaas.NewServerOp(caller).Read(ctx, zone, state.Resources["web"].ID)
+	require.True(t, iaas.IsNotFoundError(err))
+	_, err = iaas.NewSwitchOp(caller).Read(ctx, zone, state.Resources["internal"].ID)
+	require.True(t, iaas.IsNotFoundError(err))
+}
+
+func TestService_ApplyWithFailure(t *testing.T) {
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("stack-apply-failure")
+	svc := New(caller)
+
+	zone := testutil.TestZone()
+	resources := []*Resource{
+		{Name: "internal", Spec: &swytch.CreateRequest{Zone: zone, Name: name}},
+		// 存在しないIDを指定して失敗させる
+		{Name: "broken", Spec: &dns.UpdateRequest{ID: types.ID(1)}},
+		{Name: "child", DependsOn: []string{"broken"}, Spec: &swytch.CreateRequest{Zone: zone, Name: name}},
+	}
+
+	result, err := svc.Apply(&ApplyRequest{Resources: resources})
+	require.Error(t, err)
+
+	var stackErr *Error
+	require.True(t, errors.As(err, &stackErr))
+	require.Len(t, stackErr.Errors, 2)
+
+	require.NoError(t, result.Resources[0].Err)
+	require.True(t, service.IsNotFoundError(result.Resources[1].Err))
+	require.ErrorIs(t, result.Resources[2].Err, ErrDependencyFailed)
+	require.Equal(t, []string{"internal"}, keys(result.State))
+
+	_, err = svc.Delete(&DeleteRequest{State: result.State})
+	require.NoError(t, err)
+}
+
+func TestService_Apply_serverDisks(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("stack-apply-disks")
+	zone := testutil.TestZone()
+	svc := New(caller)
+
+	resources := func() []*Resource {
+		return []*Resource{
+			{
+				Name: "web",
+				Spec: &server.ApplyRequest{
+					Zone:     zone,
+					Name:     name,
+					CPU:      1,
+					MemoryGB: 1,
+					Disks: []*disk.ApplyRequest{
+						{Zone: zone, Name: name, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+						{Zone: zone, Name: name, DiskPlanID: types.DiskPlans.SSD, SizeGB: 20},
+					},
+				},
+			},
+		}
+	}
+	countDisks := func() int {
+		found, err := iaas.NewDiskOp(caller).Find(ctx, zone, &iaas.FindCondition{
+			Filter: search.Filter{search.Key("Name"): search.ExactMatch(name)},
+		})
+		require.NoError(t, err)
+		return len(found.Disks)
+	}
+
+	result, err := svc.Apply(&ApplyRequest{Resources: resources()})
+	require.NoError(t, err)
+	state := result.State
+	require.Len(t, state.Resources["web"].DiskIDs, 2)
+	require.Equal(t, 2, countDisks())
+
+	result, err = svc.Apply(&ApplyRequest{Resources: resources(), State: state})
+	require.NoError(t, err)
+	require.Equal(t, state.Resources["web"].DiskIDs, result.State.Resources["web"].DiskIDs)
+	require.Equal(t, 2, countDisks())
+
+	_, err = svc.Delete(&DeleteRequest{State: result.State, ForceShutdown: true})
+	require.NoError(t, err)
+	require.Equal(t, 0, countDisks())
+}
+
+func TestService_Apply_clearDescription(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("stack-apply-description")
+	zone := testutil.TestZone()
+	svc := New(caller)
+
+	result, err := svc.Apply(&ApplyRequest{Resources: []*Resource{
+		{Name: "sw", Spec: &swytch.CreateRequest{Zone: zone, Name: name, Description: "desc"}},
+		{Name: "dns", Spec: &dns.CreateRequest{Name: name + ".example.com", Description: "desc"}},
+	}})
+	require.NoError(t, err)
+
+	result, err = svc.Apply(&ApplyRequest{State: result.State, Resources: []*Resource{
+		{Name: "sw", Spec: &swytch.CreateRequest{Zone: zone, Name: name}},
+		{Name: "dns", Spec: &dns.CreateRequest{Name: name + ".example.com"}},
+	}})
+	require.NoError(t, err)
+
+	sw, err := iaas.NewSwitchOp(caller).Read(ctx, zone, result.State.Resources["sw"].ID)
+	require.NoError(t, err)
+	require.Empty(t, sw.Description)
+
+	d, err := iaas.NewDNSOp(caller).Read(ctx, result.State.Resources["dns"].ID)
+	require.NoError(t, err)
+	require.Empty(t, d.Description)
+
+	_, err = svc.Delete(&DeleteRequest{State: result.State})
+	require.NoError(t, err)
+}
+
+func TestService_Apply_serverFailedAfterCreate(t *testing.T) {
+	ctx := context.Background()
+	caller := testutil.SingletonAPICaller()
+	name := testutil.ResourceName("stack-apply-server-failure")
+	zone := testutil.TestZone()
+	svc := New(caller)
+
+	// 存在しないCD-ROMを指定し、サーバの作成後に失敗させる
+	result, err := svc.Apply(&ApplyRequest{Resources: []*Resource{
+		{Name: "web", Spec: &server.ApplyRequest{Zone: zone, Name: name, CPU: 1, MemoryGB: 1, CDROMID: types.ID(1)}},
+	}})
+	require.Error(t, err)
+	require.Error(t, result.Resources[0].Err)
+
+	st := result.State.Resources["web"]
+	require.NotNil(t, st)
+	require.False(t, st.ID.IsEmpty())
+	require.Equal(t, st.ID, result.Resources[0].ID)
+
+	_, err = svc.Delete(&DeleteRequest{State: result.State, ForceShutdown: true})
+	require.NoError(t, err)
+	_, err = iaas.NewServerOp(caller).Read(ctx, zone, st.ID)
+	require.True(t, iaas.IsNotFoundError(err))
+}
+
+func TestApplyRequest_Validate(t *testing.T) {
+	zone := testutil.TestZone()
+	cases := []struct {
+		msg       string
+		resources []*Resource
+	}{
+		{
+			msg: "duplicate name",
+			resources: []*Resource{
+				{Name: "a", Spec: &swytch.CreateRequest{Zone: zone, Name: "a"}},
+				{Name: "a", Spec: &swytch.CreateRequest{Zone: zone, Name: "a"}},
+			},
+		},
+		{
+			msg: "undefined reference",
+			resources: []*Resource{
+				{Name: "a", DependsOn: []string{"b"}, Spec: &swytch.CreateRequest{Zone: zone, Name: "a"}},
+			},
+		},
+		{
+			msg: "circular dependency",
+			resources: []*Resource{
+				{Name: "a", DependsOn: []string{"b"}, Spec: &swytch.CreateRequest{Zone: zone, Name: "a"}},
+				{Name: "b", DependsOn: []string{"a"}, Spec: &swytch.CreateRequest{Zone: zone, Name: "b"}},
+			},
+		},
+		{
+			msg: "unsupported spec",
+			resources: []*Resource{
+				{Name: "a", Spec: &swytch.ReadRequest{Zone: zone, ID: types.ID(1)}},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		err := (&ApplyRequest{Resources: tc.resources}).Validate()
+		require.Error(t, err, tc.msg)
+	}
+}
+
+func keys(state *State) []string {
+	var names []string
+	for name := range state.Resources {
+		names = append(names, name)
+	}
+	return names
+}
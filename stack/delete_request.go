@@ -0,0 +1,28 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import "github.com/sacloud/packages-go/validate"
+
+type DeleteRequest struct {
+	State *State `validate:"required"` // Applyの結果として返されたState
+
+	ForceShutdown bool // trueの場合は電源OFF(強制終了)してから削除
+	Parallelism   int  `validate:"min=0"` // 同時に処理するリソース数、省略時はDefaultParallelism
+}
+
+func (req *DeleteRequest) Validate() error {
+	return validate.New().Struct(req)
+}
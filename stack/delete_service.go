@@ -0,0 +1,49 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"sort"
+
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Delete Stateに記録された全てのリソースを依存関係の逆順に削除する
+//
+// 削除に失敗したリソースは返されるStateに残る。
+func (s *Service) Delete(req *DeleteRequest) (*Result, error) {
+	return s.DeleteWithContext(context.Background(), req)
+}
+
+func (s *Service) DeleteWithContext(ctx context.Context, req *DeleteRequest) (_ *Result, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "Delete", Resource: "Stack", Request: req})
+	defer func() { err = finish(err) }()
+
+	if err := req.Validate(); err != nil {
+		return nil, &service.Error{Op: "Delete", Resource: "Stack", Kind: service.ErrorKindValidation, Err: err}
+	}
+
+	state := &State{Resources: map[string]*StateResource{}}
+	var names []string
+	for name, st := range req.State.Resources {
+		state.Resources[name] = st
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	results := s.deleteResources(ctx, state, names, req.ForceShutdown, req.Parallelism)
+	return &Result{Resources: results, State: state}, errorFromResults(results)
+}
@@ -0,0 +1,156 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrDependencyFailed 依存するリソースの処理が失敗したため処理されなかったことを示すエラー
+var ErrDependencyFailed = errors.New("dependency failed")
+
+// graph リソースの論理名をノードとする依存グラフ
+type graph struct {
+	nodes []string            // 定義順
+	deps  map[string][]string // ノードが依存するノード
+}
+
+func newGraph() *graph {
+	return &graph{deps: map[string][]string{}}
+}
+
+func (g *graph) add(name string, deps []string) {
+	g.nodes = append(g.nodes, name)
+	g.deps[name] = deps
+}
+
+// validate 未定義のノードへの依存や循環がないか検証する
+func (g *graph) validate() error {
+	for _, name := range g.nodes {
+		for _, dep := range g.deps[name] {
+			if _, ok := g.deps[dep]; !ok {
+				return fmt.Errorf("resource %q depends on undefined resource %q", name, dep)
+			}
+		}
+	}
+
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := map[string]int{}
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visiting:
+			return fmt.Errorf("circular dependency: %v", append(path, name))
+		case visited:
+			return nil
+		}
+		state[name] = visiting
+		for _, dep := range g.deps[name] {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+		return nil
+	}
+	for _, name := range g.nodes {
+		if err := visit(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reverse 依存の向きを反転したグラフを返す(削除時に利用する)
+func (g *graph) reverse() *graph {
+	r := newGraph()
+	for _, name := range g.nodes {
+		r.deps[name] = nil
+	}
+	r.nodes = append(r.nodes, g.nodes...)
+	for _, name := range g.nodes {
+		for _, dep := range g.deps[name] {
+			if _, ok := r.deps[dep]; ok {
+				r.deps[dep] = append(r.deps[dep], name)
+			}
+		}
+	}
+	return r
+}
+
+// run 依存するノードの処理が全て成功したノードから順にfnを実行する
+//
+// 依存のないノード同士はparallelismを上限に並列で処理される。
+// 依存するノードが失敗した場合、fnは呼ばれずErrDependencyFailedをラップしたエラーとなる。
+func (g *graph) run(ctx context.Context, parallelism int, fn func(ctx context.Context, name string) error) map[string]error {
+	if parallelism <= 0 {
+		parallelism = DefaultParallelism
+	}
+
+	done := map[string]chan struct{}{}
+	for _, name := range g.nodes {
+		done[name] = make(chan struct{})
+	}
+
+	var mu sync.Mutex
+	results := map[string]error{}
+	resultOf := func(name string) error {
+		mu.Lock()
+		defer mu.Unlock()
+		return results[name]
+	}
+
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+	for _, name := range g.nodes {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			defer close(done[name])
+
+			err := func() error {
+				for _, dep := range g.deps[name] {
+					select {
+					case <-done[dep]:
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+					if resultOf(dep) != nil {
+						return fmt.Errorf("%w: %q", ErrDependencyFailed, dep)
+					}
+				}
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+				defer func() { <-sem }()
+				return fn(ctx, name)
+			}()
+
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}(name)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,285 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/database"
+	"github.com/sacloud/iaas-service-go/disk"
+	"github.com/sacloud/iaas-service-go/dns"
+	"github.com/sacloud/iaas-service-go/loadbalancer"
+	"github.com/sacloud/iaas-service-go/nfs"
+	"github.com/sacloud/iaas-service-go/server"
+	serverBuilder "github.com/sacloud/iaas-service-go/server/builder"
+	"github.com/sacloud/iaas-service-go/swytch"
+	"github.com/sacloud/iaas-service-go/vpcrouter"
+)
+
+// applyResult 作成/更新したリソースの情報
+type applyResult struct {
+	ID      types.ID
+	DiskIDs []types.ID // Serverの場合のみ、接続されているディスクのID
+}
+
+// applyFunc リソースを作成/更新する
+//
+// currentにはStateに記録されている作成済みリソースの情報が渡される(未作成の場合はIDが空)。
+// 作成後の処理でエラーとなった場合も、作成済みのリソースがあればその情報をエラーと共に返す
+type applyFunc func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error)
+
+type handler struct {
+	kind   string
+	zone   string
+	id     types.ID // Specで指定されたID
+	update bool     // Specが*UpdateRequestの場合true
+	apply  applyFunc
+}
+
+func handlerOf(spec interface{}) (*handler, error) {
+	switch req := spec.(type) {
+	case *swytch.CreateRequest:
+		return &handler{kind: "Switch", zone: req.Zone, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			svc := swytch.New(caller)
+			if current.ID.IsEmpty() {
+				sw, err := svc.CreateWithContext(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				return &applyResult{ID: sw.ID}, nil
+			}
+			// swytch.UpdateRequestでは空の説明を指定できないため、APIを直接呼び出して全ての項目を置き換える
+			sw, err := iaas.NewSwitchOp(caller).Update(ctx, req.Zone, current.ID, &iaas.SwitchUpdateRequest{
+				Name:           req.Name,
+				Description:    req.Description,
+				Tags:           req.Tags,
+				IconID:         req.IconID,
+				NetworkMaskLen: req.NetworkMaskLen,
+				DefaultRoute:   req.DefaultRoute,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: sw.ID}, nil
+		}}, nil
+	case *swytch.UpdateRequest:
+		return &handler{kind: "Switch", zone: req.Zone, id: req.ID, update: true, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			if req.ID.IsEmpty() {
+				req.ID = current.ID
+			}
+			sw, err := swytch.New(caller).UpdateWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: sw.ID}, nil
+		}}, nil
+	case *dns.CreateRequest:
+		return &handler{kind: "DNS", apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			svc := dns.New(caller)
+			if current.ID.IsEmpty() {
+				d, err := svc.CreateWithContext(ctx, req)
+				if err != nil {
+					return nil, err
+				}
+				return &applyResult{ID: d.ID}, nil
+			}
+			// dns.UpdateRequestでは空の説明を指定できないため、APIを直接呼び出して全ての項目を置き換える
+			d, err := iaas.NewDNSOp(caller).Update(ctx, current.ID, &iaas.DNSUpdateRequest{
+				Description: req.Description,
+				Tags:        req.Tags,
+				IconID:      req.IconID,
+				Records:     req.Records,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: d.ID}, nil
+		}}, nil
+	case *dns.UpdateRequest:
+		return &handler{kind: "DNS", id: req.ID, update: true, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			if req.ID.IsEmpty() {
+				req.ID = current.ID
+			}
+			d, err := dns.New(caller).UpdateWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: d.ID}, nil
+		}}, nil
+	case *server.ApplyRequest:
+		return &handler{kind: "Server", zone: req.Zone, id: req.ID, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			if req.ID.IsEmpty() {
+				req.ID = current.ID
+			}
+			// 作成済みのディスクを再作成しないよう、Stateに記録されたディスクのIDを設定する
+			if !req.ID.IsEmpty() {
+				for i, id := range current.DiskIDs {
+					if i < len(req.Disks) && req.Disks[i].ID.IsEmpty() {
+						req.Disks[i].ID = id
+					}
+				}
+			}
+			return applyServer(ctx, caller, req)
+		}}, nil
+	case *disk.ApplyRequest:
+		return &handler{kind: "Disk", zone: req.Zone, id: req.ID, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			if req.ID.IsEmpty() {
+				req.ID = current.ID
+			}
+			v, err := disk.New(caller).ApplyWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: v.ID}, nil
+		}}, nil
+	case *vpcrouter.ApplyRequest:
+		return &handler{kind: "VPCRouter", zone: req.Zone, id: req.ID, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			if req.ID.IsEmpty() {
+				req.ID = current.ID
+			}
+			v, err := vpcrouter.New(caller).ApplyWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: v.ID}, nil
+		}}, nil
+	case *loadbalancer.ApplyRequest:
+		return &handler{kind: "LoadBalancer", zone: req.Zone, id: req.ID, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			if req.ID.IsEmpty() {
+				req.ID = current.ID
+			}
+			v, err := loadbalancer.New(caller).ApplyWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: v.ID}, nil
+		}}, nil
+	case *database.ApplyRequest:
+		return &handler{kind: "Database", zone: req.Zone, id: req.ID, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			if req.ID.IsEmpty() {
+				req.ID = current.ID
+			}
+			v, err := database.New(caller).ApplyWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: v.ID}, nil
+		}}, nil
+	case *nfs.ApplyRequest:
+		return &handler{kind: "NFS", zone: req.Zone, id: req.ID, apply: func(ctx context.Context, caller iaas.APICaller, current *applyResult) (*applyResult, error) {
+			if req.ID.IsEmpty() {
+				req.ID = current.ID
+			}
+			v, err := nfs.New(caller).ApplyWithContext(ctx, req)
+			if err != nil {
+				return nil, err
+			}
+			return &applyResult{ID: v.ID}, nil
+		}}, nil
+	}
+	return nil, fmt.Errorf("unsupported spec type: %T", spec)
+}
+
+// applyServer ビルダーを直接利用してサーバを作成/更新する
+//
+// server.Service.Applyと異なり、作成後の処理(ディスクの作成や起動など)に失敗した場合も
+// 作成済みのサーバとディスクのIDをエラーと共に返す
+func applyServer(ctx context.Context, caller iaas.APICaller, req *server.ApplyRequest) (*applyResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	builder, err := req.Builder(caller)
+	if err != nil {
+		return nil, err
+	}
+
+	var built *serverBuilder.BuildResult
+	if req.ID.IsEmpty() {
+		built, err = builder.Build(ctx, req.Zone)
+	} else {
+		built, err = builder.Update(ctx, req.Zone)
+	}
+	if built == nil || built.ServerID.IsEmpty() {
+		return nil, err
+	}
+
+	v, readErr := iaas.NewServerOp(caller).Read(ctx, req.Zone, built.ServerID)
+	if readErr != nil {
+		return &applyResult{ID: built.ServerID, DiskIDs: built.DiskIDs}, errors.Join(err, readErr)
+	}
+	result := &applyResult{ID: v.ID}
+	for _, d := range v.Disks {
+		result.DiskIDs = append(result.DiskIDs, d.ID)
+	}
+	return result, err
+}
+
+// newSpec JSON/YAMLからの復元時にKindに対応するSpecの値を返す
+//
+// updateがtrueの場合、Switch/DNSはUpdateRequestを返す
+func newSpec(kind string, update bool) (interface{}, bool) {
+	switch kind {
+	case "Switch":
+		if update {
+			return &swytch.UpdateRequest{}, true
+		}
+		return &swytch.CreateRequest{}, true
+	case "DNS":
+		if update {
+			return &dns.UpdateRequest{}, true
+		}
+		return &dns.CreateRequest{}, true
+	case "Server":
+		return &server.ApplyRequest{}, true
+	case "Disk":
+		return &disk.ApplyRequest{}, true
+	case "VPCRouter":
+		return &vpcrouter.ApplyRequest{}, true
+	case "LoadBalancer":
+		return &loadbalancer.ApplyRequest{}, true
+	case "Database":
+		return &database.ApplyRequest{}, true
+	case "NFS":
+		return &nfs.ApplyRequest{}, true
+	}
+	return nil, false
+}
+
+// deleteResource Stateに記録されたリソースを削除する
+func deleteResource(ctx context.Context, caller iaas.APICaller, r *StateResource, force bool) error {
+	switch r.Kind {
+	case "Switch":
+		return swytch.New(caller).DeleteWithContext(ctx, &swytch.DeleteRequest{Zone: r.Zone, ID: r.ID})
+	case "DNS":
+		return dns.New(caller).DeleteWithContext(ctx, &dns.DeleteRequest{ID: r.ID})
+	case "Server":
+		return server.New(caller).DeleteWithContext(ctx, &server.DeleteRequest{Zone: r.Zone, ID: r.ID, WithDisks: true, Force: force})
+	case "Disk":
+		return disk.New(caller).DeleteWithContext(ctx, &disk.DeleteRequest{Zone: r.Zone, ID: r.ID})
+	case "VPCRouter":
+		return vpcrouter.New(caller).DeleteWithContext(ctx, &vpcrouter.DeleteRequest{Zone: r.Zone, ID: r.ID, Force: force})
+	case "LoadBalancer":
+		return loadbalancer.New(caller).DeleteWithContext(ctx, &loadbalancer.DeleteRequest{Zone: r.Zone, ID: r.ID, Force: force})
+	case "Database":
+		return database.New(caller).DeleteWithContext(ctx, &database.DeleteRequest{Zone: r.Zone, ID: r.ID, Force: force})
+	case "NFS":
+		return nfs.New(caller).DeleteWithContext(ctx, &nfs.DeleteRequest{Zone: r.Zone, ID: r.ID, Force: force})
+	}
+	return fmt.Errorf("unsupported kind: %q", r.Kind)
+}
@@ -0,0 +1,144 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+var idType = reflect.TypeOf(types.ID(0))
+
+// setField specのpathで示されるフィールドへidを設定する
+func setField(spec interface{}, path string, id types.ID) error {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(spec)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return fmt.Errorf("spec must be a non-nil pointer: %T", spec)
+	}
+	if err := setValue(v, tokens, id); err != nil {
+		return fmt.Errorf("field %q: %w", path, err)
+	}
+	return nil
+}
+
+type pathToken struct {
+	field string
+	index int // fieldが空の場合のみ有効
+}
+
+func parsePath(path string) ([]pathToken, error) {
+	var tokens []pathToken
+	for _, part := range strings.Split(path, ".") {
+		name := part
+		var indexes []int
+		if i := strings.Index(part, "["); i >= 0 {
+			name = part[:i]
+			rest := part[i:]
+			for rest != "" {
+				end := strings.Index(rest, "]")
+				if rest[0] != '[' || end < 0 {
+					return nil, fmt.Errorf("invalid field path: %q", path)
+				}
+				n, err := strconv.Atoi(rest[1:end])
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("invalid index in field path: %q", path)
+				}
+				indexes = append(indexes, n)
+				rest = rest[end+1:]
+			}
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid field path: %q", path)
+		}
+		tokens = append(tokens, pathToken{field: name})
+		for _, n := range indexes {
+			tokens = append(tokens, pathToken{index: n})
+		}
+	}
+	return tokens, nil
+}
+
+func setValue(v reflect.Value, tokens []pathToken, id types.ID) error {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			if !v.CanSet() {
+				return fmt.Errorf("nil pointer: %s", v.Type())
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if len(tokens) == 0 && v.Type().Elem() == idType {
+			v.Elem().Set(reflect.ValueOf(id))
+			return nil
+		}
+		return setValue(v.Elem(), tokens, id)
+	case reflect.Interface:
+		if v.IsNil() {
+			return fmt.Errorf("nil interface: %s", v.Type())
+		}
+		elem := v.Elem()
+		if elem.Kind() == reflect.Ptr {
+			return setValue(elem, tokens, id)
+		}
+		// インターフェースが値を保持している場合はコピーを更新して戻す
+		copied := reflect.New(elem.Type()).Elem()
+		copied.Set(elem)
+		if err := setValue(copied, tokens, id); err != nil {
+			return err
+		}
+		v.Set(copied)
+		return nil
+	}
+
+	if len(tokens) == 0 {
+		switch {
+		case v.Type() == idType:
+			v.Set(reflect.ValueOf(id))
+		case v.Kind() == reflect.String:
+			v.SetString(id.String())
+		default:
+			return fmt.Errorf("unsupported field type: %s", v.Type())
+		}
+		return nil
+	}
+
+	token := tokens[0]
+	if token.field != "" {
+		if v.Kind() != reflect.Struct {
+			return fmt.Errorf("%s is not a struct", v.Type())
+		}
+		f := v.FieldByName(token.field)
+		if !f.IsValid() || !f.CanSet() {
+			return fmt.Errorf("%s has no exported field %q", v.Type(), token.field)
+		}
+		return setValue(f, tokens[1:], id)
+	}
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("%s is not a slice", v.Type())
+	}
+	if token.index >= v.Len() {
+		return fmt.Errorf("index out of range: %d", token.index)
+	}
+	return setValue(v.Index(token.index), tokens[1:], id)
+}
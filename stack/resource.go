@@ -0,0 +1,173 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go/types"
+)
+
+// DefaultParallelism 同時に処理するリソース数のデフォルト値
+const DefaultParallelism = 4
+
+// Resource スタックを構成するリソースの定義
+type Resource struct {
+	// Name スタック内で一意な論理名
+	Name string `validate:"required"`
+	// DependsOn Refs以外で依存するリソースの論理名
+	DependsOn []string
+	// Refs 他のリソースのIDを参照するフィールド
+	Refs []*Ref `validate:"dive"`
+	// Spec リソースの定義
+	//
+	// 以下のいずれかを指定する。参照の解決やIDの設定のため、処理中に値が更新される。
+	//   - *swytch.CreateRequest / *swytch.UpdateRequest
+	//   - *dns.CreateRequest / *dns.UpdateRequest
+	//   - *server.ApplyRequest
+	//   - *disk.ApplyRequest
+	//   - *vpcrouter.ApplyRequest
+	//   - *loadbalancer.ApplyRequest
+	//   - *database.ApplyRequest
+	//   - *nfs.ApplyRequest
+	Spec interface{} `validate:"required"`
+}
+
+// Ref 他のリソースのIDをSpecのフィールドへ設定するための参照
+type Ref struct {
+	// Field 値を設定するSpec内のフィールドのパス
+	//
+	// フィールド名を"."で区切り、スライスの要素は"[n]"で指定する。
+	// 例: "NetworkInterfaces[0].Upstream", "SwitchID"
+	// 対象のフィールドはtypes.ID、*types.ID、stringのいずれかである必要がある。
+	Field string `validate:"required"`
+	// Resource 参照先リソースの論理名
+	Resource string `validate:"required"`
+}
+
+// Kind Specから判定したリソース種別を返す
+func (r *Resource) Kind() (string, error) {
+	h, err := handlerOf(r.Spec)
+	if err != nil {
+		return "", err
+	}
+	return h.kind, nil
+}
+
+// dependencies Refs/DependsOnから依存するリソースの論理名を重複なく返す
+func (r *Resource) dependencies() []string {
+	var deps []string
+	seen := map[string]bool{}
+	for _, name := range r.DependsOn {
+		if !seen[name] {
+			seen[name] = true
+			deps = append(deps, name)
+		}
+	}
+	for _, ref := range r.Refs {
+		if !seen[ref.Resource] {
+			seen[ref.Resource] = true
+			deps = append(deps, ref.Resource)
+		}
+	}
+	return deps
+}
+
+type resourceDocument struct {
+	Name      string
+	Kind      string
+	Update    bool     `json:",omitempty"` // SpecがUpdateRequestか(Switch/DNSのみ)
+	DependsOn []string `json:",omitempty"`
+	Refs      []*Ref   `json:",omitempty"`
+	Spec      json.RawMessage
+}
+
+// MarshalJSON Kindを付与してJSONへ変換する
+func (r *Resource) MarshalJSON() ([]byte, error) {
+	h, err := handlerOf(r.Spec)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := json.Marshal(r.Spec)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(&resourceDocument{
+		Name:      r.Name,
+		Kind:      h.kind,
+		Update:    h.update,
+		DependsOn: r.DependsOn,
+		Refs:      r.Refs,
+		Spec:      spec,
+	})
+}
+
+// UnmarshalJSON Kindに応じた型でSpecを復元する
+//
+// Kindが"Switch"、"DNS"の場合、Updateがtrueか、SpecにIDが含まれていればそれぞれのUpdateRequest、
+// それ以外はCreateRequestとして扱われる。
+func (r *Resource) UnmarshalJSON(data []byte) error {
+	var doc resourceDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	update := doc.Update
+	if !update && len(doc.Spec) > 0 {
+		var id struct{ ID types.ID }
+		if err := json.Unmarshal(doc.Spec, &id); err == nil && !id.ID.IsEmpty() {
+			update = true
+		}
+	}
+	spec, ok := newSpec(doc.Kind, update)
+	if !ok {
+		return fmt.Errorf("resource %q: unsupported kind: %q", doc.Name, doc.Kind)
+	}
+	if len(doc.Spec) > 0 {
+		if err := json.Unmarshal(doc.Spec, spec); err != nil {
+			return fmt.Errorf("resource %q: %w", doc.Name, err)
+		}
+	}
+	*r = Resource{
+		Name:      doc.Name,
+		DependsOn: doc.DependsOn,
+		Refs:      doc.Refs,
+		Spec:      spec,
+	}
+	return nil
+}
+
+// State スタックにより作成されたリソースの情報
+//
+// ApplyResultとして返されたものを保存しておき、次回のApply/Delete時に指定する。
+type State struct {
+	Resources map[string]*StateResource // 論理名をキーとするリソースの情報
+}
+
+// StateResource 作成済みリソースの情報
+type StateResource struct {
+	Kind      string
+	Zone      string
+	ID        types.ID
+	DiskIDs   []types.ID `json:",omitempty"` // Serverに接続されているディスクのID、再Apply時にSpec.Disksへ設定される
+	DependsOn []string   `json:",omitempty"`
+}
+
+func (s *State) lookup(name string) *StateResource {
+	if s == nil || s.Resources == nil {
+		return nil
+	}
+	return s.Resources[name]
+}
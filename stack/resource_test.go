@@ -0,0 +1,83 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go/types"
+	"github.com/sacloud/iaas-service-go/dns"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/sacloud/iaas-service-go/swytch"
+	"github.com/sacloud/iaas-service-go/vpcrouter"
+	"github.com/sacloud/iaas-service-go/vpcrouter/builder"
+	"github.com/sacloud/packages-go/pointer"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetField(t *testing.T) {
+	t.Run("string in slice", func(t *testing.T) {
+		spec := &server.ApplyRequest{NetworkInterfaces: []*server.NetworkInterface{{Upstream: "shared"}, {}}}
+		require.NoError(t, setField(spec, "NetworkInterfaces[1].Upstream", types.ID(101)))
+		require.Equal(t, "101", spec.NetworkInterfaces[1].Upstream)
+	})
+
+	t.Run("ID in interface", func(t *testing.T) {
+		spec := &vpcrouter.ApplyRequest{
+			AdditionalNICSettings: []builder.AdditionalNICSettingHolder{&builder.AdditionalStandardNICSetting{}},
+		}
+		require.NoError(t, setField(spec, "AdditionalNICSettings[0].SwitchID", types.ID(102)))
+		require.Equal(t, types.ID(102), spec.AdditionalNICSettings[0].(*builder.AdditionalStandardNICSetting).SwitchID)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		spec := &server.ApplyRequest{}
+		require.Error(t, setField(spec, "NetworkInterfaces[0].Upstream", types.ID(1)))
+		require.Error(t, setField(spec, "NotExists", types.ID(1)))
+		require.Error(t, setField(spec, "CPU", types.ID(1)))
+		require.Error(t, setField(spec, "NetworkInterfaces[x]", types.ID(1)))
+	})
+}
+
+func TestResource_JSON(t *testing.T) {
+	resources := []*Resource{
+		{Name: "sw", Spec: &swytch.CreateRequest{Zone: "is1a", Name: "sw", Tags: types.Tags{}}},
+		{Name: "existing-sw", Spec: &swytch.UpdateRequest{Zone: "is1a", ID: types.ID(101), Name: pointer.NewString("existing")}},
+		{Name: "existing-dns", Spec: &dns.UpdateRequest{ID: types.ID(102)}},
+		{Name: "state-sw", Spec: &swytch.UpdateRequest{Zone: "is1a", Description: pointer.NewString("")}},
+		{
+			Name: "web",
+			Refs: []*Ref{{Field: "NetworkInterfaces[0].Upstream", Resource: "sw"}},
+			Spec: &server.ApplyRequest{
+				Zone:              "is1a",
+				Name:              "web",
+				Tags:              types.Tags{},
+				NetworkInterfaces: []*server.NetworkInterface{{}},
+			},
+		},
+	}
+
+	data, err := json.Marshal(resources)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"Kind":"Switch"`)
+	require.Contains(t, string(data), `"Kind":"Server"`)
+
+	var loaded []*Resource
+	require.NoError(t, json.Unmarshal(data, &loaded))
+	require.Equal(t, resources, loaded)
+
+	require.Error(t, json.Unmarshal([]byte(`[{"Name":"x","Kind":"Unknown"}]`), &loaded))
+}
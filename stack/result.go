@@ -0,0 +1,93 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package stack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// Result Apply/Deleteの処理結果
+type Result struct {
+	Resources []*ResourceResult // リソースごとの処理結果
+	State     *State            // 処理後のState、次回のApply/Delete時に指定する
+}
+
+// ResourceResult リソースごとの処理結果
+type ResourceResult struct {
+	Name   string
+	Kind   string
+	Zone   string
+	ID     types.ID
+	Action service.ChangeAction
+	Err    error
+}
+
+// ResourceError 特定のリソースの処理中に発生したエラー
+type ResourceError struct {
+	Name string
+	Err  error
+}
+
+// Error errorインターフェースの実装
+func (e *ResourceError) Error() string {
+	return fmt.Sprintf("resource[%s]: %s", e.Name, e.Err)
+}
+
+// Unwrap 元のエラーを返す
+func (e *ResourceError) Unwrap() error {
+	return e.Err
+}
+
+// Error 1つ以上のリソースで処理が失敗したことを示すエラー
+//
+// 成功したリソースの結果やStateはこのエラーと共に返される。
+type Error struct {
+	Errors []*ResourceError
+}
+
+// Error errorインターフェースの実装
+func (e *Error) Error() string {
+	var messages []string
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("failed in %d resource(s): %s", len(e.Errors), strings.Join(messages, ", "))
+}
+
+// Unwrap リソースごとのエラーを返す
+func (e *Error) Unwrap() []error {
+	var errs []error
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+func errorFromResults(results []*ResourceResult) error {
+	var errs []*ResourceError
+	for _, r := range results {
+		if r.Err != nil {
+			errs = append(errs, &ResourceError{Name: r.Name, Err: r.Err})
+		}
+	}
+	if len(errs) > 0 {
+		return &Error{Errors: errs}
+	}
+	return nil
+}
@@ -17,6 +17,7 @@ package subnet
 import (
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/search"
+	"github.com/sacloud/iaas-service-go/filter"
 	"github.com/sacloud/iaas-service-go/serviceutil"
 	"github.com/sacloud/packages-go/validate"
 )
@@ -24,6 +25,8 @@ import (
 type FindRequest struct {
 	Zone string `service:"-" validate:"required"`
 
+	Filter filter.Filter `service:"-"`
+
 	Sort  search.SortKeys
 	Count int
 	From  int
@@ -40,5 +43,6 @@ func (req *FindRequest) ToRequestParameter() (*iaas.FindCondition, error) {
 	if err := serviceutil.RequestConvertTo(req, condition); err != nil {
 		return nil, err
 	}
+	filter.SetCondition[*iaas.Subnet](&req.Filter, condition)
 	return condition, nil
 }
@@ -0,0 +1,217 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
+	"github.com/sacloud/iaas-service-go/serviceutil"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName トレーサー名
+const InstrumentationName = "github.com/sacloud/iaas-service-go"
+
+// 各spanに付与される属性のキー
+const (
+	AttributeOperation = attribute.Key("sacloud.operation")
+	AttributeKind      = attribute.Key("sacloud.resource.kind")
+	AttributeZone      = attribute.Key("sacloud.zone")
+	AttributeID        = attribute.Key("sacloud.resource.id")
+	AttributePhase     = attribute.Key("sacloud.phase")
+	AttributeMethod    = attribute.Key("http.method")
+	AttributeURL       = attribute.Key("http.url")
+)
+
+// Options Callerの設定
+type Options struct {
+	// TracerProvider spanの作成に利用するTracerProvider、省略時は何も記録しないTracerProviderを利用する
+	TracerProvider trace.TracerProvider
+}
+
+// Caller OpenTelemetryのspanを記録するiaas.APICaller
+//
+// 各サービスのコンストラクタに渡すと、サービスの操作ごとのspanと、その子spanとしてAPI呼び出しごと/
+// ビルダーの処理の段階(progress.Phase)ごとのspanを記録する。
+// 操作ごとのspanはservice.OperationHookとして記録される。
+// 他のAPICallerのラッパーと組み合わせる場合、ラッパーがservice.CallerWrapperを実装していればCallerの位置は問わない
+type Caller struct {
+	caller iaas.APICaller
+	tracer trace.Tracer
+	phases *phaseObserver
+}
+
+var (
+	_ iaas.APICaller        = (*Caller)(nil)
+	_ service.OperationHook = (*Caller)(nil)
+	_ service.CallerWrapper = (*Caller)(nil)
+)
+
+// NewCaller callerをラップしたCallerを返す
+func NewCaller(caller iaas.APICaller, options *Options) *Caller {
+	var provider trace.TracerProvider
+	if options != nil {
+		provider = options.TracerProvider
+	}
+	if provider == nil {
+		provider = trace.NewNoopTracerProvider()
+	}
+	tracer := provider.Tracer(InstrumentationName, trace.WithInstrumentationVersion(service.Version))
+	return &Caller{
+		caller: caller,
+		tracer: tracer,
+		phases: &phaseObserver{tracer: tracer, spans: make(map[phaseKey][]trace.Span)},
+	}
+}
+
+// Do iaas.APICallerの実装
+func (c *Caller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	path, kind, zone, id := uri, "", "", types.ID(0)
+	if u, ok := serviceutil.ParseAPIURL(uri); ok {
+		path, kind, zone, id = u.Template(), u.Kind(), u.Zone, u.ID()
+	}
+	attrs := []attribute.KeyValue{
+		AttributeMethod.String(method),
+		AttributeURL.String(uri),
+		AttributeKind.String(kind),
+	}
+	if zone != "" {
+		attrs = append(attrs, AttributeZone.String(zone))
+	}
+	if !id.IsEmpty() {
+		attrs = append(attrs, AttributeID.String(id.String()))
+	}
+
+	ctx, span := c.tracer.Start(ctx, method+" "+path, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	defer span.End()
+
+	data, err := c.caller.Do(ctx, method, uri, body)
+	recordError(span, err)
+	return data, err
+}
+
+// Unwrap service.CallerWrapperの実装
+func (c *Caller) Unwrap() iaas.APICaller {
+	return c.caller
+}
+
+// StartOperation service.OperationHookの実装
+//
+// 操作ごとのspanを開始し、ビルダーの処理の段階をspanとして記録するためのprogress.Observerを設定したcontextを返す
+func (c *Caller) StartOperation(ctx context.Context, op *service.Operation) (context.Context, func(err error)) {
+	attrs := []attribute.KeyValue{
+		AttributeOperation.String(op.Name),
+		AttributeKind.String(op.Resource),
+	}
+	if op.Zone != "" {
+		attrs = append(attrs, AttributeZone.String(op.Zone))
+	}
+	if !op.ID.IsEmpty() {
+		attrs = append(attrs, AttributeID.String(op.ID.String()))
+	}
+
+	ctx, span := c.tracer.Start(ctx, op.Resource+"."+op.Name, trace.WithAttributes(attrs...))
+	if ctx.Value(phaseObserverKey{}) != c.phases {
+		ctx = progress.WithObserver(context.WithValue(ctx, phaseObserverKey{}, c.phases), c.phases)
+	}
+	return ctx, func(err error) {
+		recordError(span, err)
+		span.End()
+	}
+}
+
+// Observer ビルダーの処理の段階をspanとして記録するprogress.Observerを返す
+//
+// サービスを経由せずにビルダーを直接利用する場合にprogress.WithObserverで設定する
+func (c *Caller) Observer() progress.Observer {
+	return c.phases
+}
+
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+type phaseObserverKey struct{}
+
+type phaseKey struct {
+	resource progress.Resource
+	phase    progress.Phase
+}
+
+// phaseObserver PhaseStarted/PhaseFinishedをspanとして、その他のイベントをspanのイベントとして記録する
+type phaseObserver struct {
+	tracer trace.Tracer
+
+	mu    sync.Mutex
+	spans map[phaseKey][]trace.Span
+}
+
+func (o *phaseObserver) OnEvent(ctx context.Context, event progress.Event) {
+	resource := event.Target()
+	switch e := event.(type) {
+	case *progress.PhaseStarted:
+		attrs := []attribute.KeyValue{
+			AttributePhase.String(string(e.Phase)),
+			AttributeKind.String(resource.Kind),
+		}
+		if resource.Zone != "" {
+			attrs = append(attrs, AttributeZone.String(resource.Zone))
+		}
+		if !resource.ID.IsEmpty() {
+			attrs = append(attrs, AttributeID.String(resource.ID.String()))
+		}
+		_, span := o.tracer.Start(ctx, resource.Kind+" "+string(e.Phase), trace.WithAttributes(attrs...))
+
+		o.mu.Lock()
+		key := phaseKey{resource: resource, phase: e.Phase}
+		o.spans[key] = append(o.spans[key], span)
+		o.mu.Unlock()
+	case *progress.PhaseFinished:
+		o.mu.Lock()
+		key := phaseKey{resource: resource, phase: e.Phase}
+		spans := o.spans[key]
+		var span trace.Span
+		if len(spans) > 0 {
+			span = spans[len(spans)-1]
+			if len(spans) == 1 {
+				delete(o.spans, key)
+			} else {
+				o.spans[key] = spans[:len(spans)-1]
+			}
+		}
+		o.mu.Unlock()
+
+		if span != nil {
+			recordError(span, e.Err)
+			span.End()
+		}
+	default:
+		attrs := []attribute.KeyValue{AttributeKind.String(resource.Kind)}
+		if !resource.ID.IsEmpty() {
+			attrs = append(attrs, AttributeID.String(resource.ID.String()))
+		}
+		trace.SpanFromContext(ctx).AddEvent(string(event.EventType()), trace.WithAttributes(attrs...))
+	}
+}
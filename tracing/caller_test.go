@@ -0,0 +1,123 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/sacloud/iaas-service-go/progress"
+	"github.com/sacloud/iaas-service-go/ratelimit"
+	"github.com/sacloud/iaas-service-go/server"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type dummyCaller struct {
+	response string
+	err      error
+}
+
+func (c *dummyCaller) Do(ctx context.Context, method, uri string, body interface{}) ([]byte, error) {
+	return []byte(c.response), c.err
+}
+
+func newTestCaller(dummy *dummyCaller) (*Caller, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	return NewCaller(dummy, &Options{TracerProvider: provider}), recorder
+}
+
+func TestCaller_Service(t *testing.T) {
+	caller, recorder := newTestCaller(&dummyCaller{response: `{"Server":{"ID":"123456789012","Name":"example"}}`})
+
+	read, err := server.New(caller).Read(&server.ReadRequest{Zone: "is1a", ID: types.ID(123456789012)})
+	require.NoError(t, err)
+	require.Equal(t, "example", read.Name)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	api, op := spans[0], spans[1]
+	require.Equal(t, "Server.Read", op.Name())
+	require.Contains(t, op.Attributes(), AttributeZone.String("is1a"))
+	require.Contains(t, op.Attributes(), AttributeID.String("123456789012"))
+
+	require.Equal(t, "GET server/{id}", api.Name())
+	require.Equal(t, op.SpanContext().SpanID(), api.Parent().SpanID())
+	require.Contains(t, api.Attributes(), AttributeKind.String("server"))
+	require.Contains(t, api.Attributes(), AttributeZone.String("is1a"))
+	require.Contains(t, api.Attributes(), AttributeID.String("123456789012"))
+}
+
+func TestCaller_Error(t *testing.T) {
+	caller, recorder := newTestCaller(&dummyCaller{err: errors.New("dummy")})
+
+	_, err := server.New(caller).Read(&server.ReadRequest{Zone: "is1a", ID: types.ID(123456789012)})
+	require.Error(t, err)
+
+	for _, span := range recorder.Ended() {
+		require.Equal(t, codes.Error, span.Status().Code, span.Name())
+	}
+}
+
+func TestCaller_Phases(t *testing.T) {
+	caller, recorder := newTestCaller(&dummyCaller{})
+
+	ctx, finish := caller.StartOperation(context.Background(), &service.Operation{Name: "Apply", Resource: "Server", Zone: "is1a"})
+	resource := progress.Resource{Kind: "Server", Zone: "is1a", ID: types.ID(1)}
+	done := progress.StartPhase(ctx, resource, progress.PhaseBuildDisks)
+	progress.Emit(ctx, &progress.ResourceCreated{Resource: resource})
+	done(errors.New("dummy"))
+	finish(nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	phase, op := spans[0], spans[1]
+	require.Equal(t, "Server build-disks", phase.Name())
+	require.Equal(t, op.SpanContext().SpanID(), phase.Parent().SpanID())
+	require.Equal(t, codes.Error, phase.Status().Code)
+	require.Equal(t, codes.Unset, op.Status().Code)
+	require.Len(t, op.Events(), 1)
+	require.Equal(t, string(progress.EventTypeResourceCreated), op.Events()[0].Name)
+}
+
+func TestCaller_Wrapped(t *testing.T) {
+	tracingCaller, recorder := newTestCaller(&dummyCaller{response: `{"Server":{"ID":"123456789012","Name":"example"}}`})
+	caller, err := ratelimit.NewCaller(tracingCaller, nil)
+	require.NoError(t, err)
+
+	_, err = server.New(caller).Read(&server.ReadRequest{Zone: "is1a", ID: types.ID(123456789012)})
+	require.NoError(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	api, op := spans[0], spans[1]
+	require.Equal(t, "Server.Read", op.Name())
+	require.Equal(t, op.SpanContext().SpanID(), api.Parent().SpanID())
+}
+
+func TestNewCaller_Noop(t *testing.T) {
+	caller := NewCaller(&dummyCaller{response: `{"Server":{"ID":"1"}}`}, nil)
+	_, err := server.New(caller).Read(&server.ReadRequest{Zone: "is1a", ID: types.ID(1)})
+	require.NoError(t, err)
+}
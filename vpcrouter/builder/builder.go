@@ -18,7 +18,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"time"
 
 	"github.com/sacloud/iaas-api-go"
 	"github.com/sacloud/iaas-api-go/accessor"
@@ -68,11 +67,10 @@ type RouterSetting struct {
 }
 
 func (b *Builder) init() {
-	if b.SetupOptions == nil {
-		b.SetupOptions = &setup2.Options{}
-	}
-	b.SetupOptions.Init()
-	b.SetupOptions.ProvisioningRetryCount = 1
+	// 呼び出し元と共有している可能性があるためコピーに対して変更する
+	options := b.SetupOptions.WithDefaults()
+	options.ProvisioningRetryCount = 1
+	b.SetupOptions = options
 
 	if b.RouterSetting == nil {
 		b.RouterSetting = &RouterSetting{
@@ -225,7 +223,9 @@ func (b *Builder) create(ctx context.Context, zone string) (*iaas.VPCRouter, err
 			}
 
 			// [HACK] スイッチ接続直後だとエラーになることがあるため数秒待つ
-			time.Sleep(b.SetupOptions.NICUpdateWaitDuration)
+			if err := setup2.Wait(ctx, b.SetupOptions.NICUpdateWaitDuration); err != nil {
+				return err
+			}
 
 			// 残りの設定の投入
 			_, err := b.Client.UpdateSettings(ctx, zone, id, &iaas.VPCRouterUpdateSettingsRequest{
@@ -273,6 +273,7 @@ func (b *Builder) create(ctx context.Context, zone string) (*iaas.VPCRouter, err
 		},
 		IsWaitForCopy: !b.NoWait,
 		IsWaitForUp:   !b.NoWait && b.SetupOptions.BootAfterBuild,
+		Kind:          "VPCRouter",
 		Options:       b.SetupOptions,
 	}
 
@@ -355,7 +356,9 @@ func (b *Builder) update(ctx context.Context, zone string, id types.ID) (*iaas.V
 		}
 	}
 	// [HACK] スイッチ接続直後だとエラーになることがあるため数秒待つ
-	time.Sleep(b.SetupOptions.NICUpdateWaitDuration)
+	if err := setup2.Wait(ctx, b.SetupOptions.NICUpdateWaitDuration); err != nil {
+		return nil, err
+	}
 
 	_, err = b.Client.Update(ctx, zone, id, &iaas.VPCRouterUpdateRequest{
 		Name:        b.Name,
@@ -0,0 +1,128 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
+)
+
+// ChangeSet Buildを行った場合の変更内容を返す
+//
+// 現在の状態の参照のためにAPIリクエストが行われますが、更新系のAPIは呼び出されません。
+func (b *Builder) ChangeSet(ctx context.Context) (*service.ChangeSet, error) {
+	b.init()
+
+	if err := b.Validate(ctx, b.Zone); err != nil {
+		return nil, err
+	}
+
+	if b.ID.IsEmpty() {
+		return b.planForCreate(), nil
+	}
+	return b.planForUpdate(ctx, b.Zone)
+}
+
+func (b *Builder) planForCreate() *service.ChangeSet {
+	cs := service.NewCreateChangeSet()
+	cs.Create("Name", b.Name)
+	cs.Create("Description", b.Description)
+	cs.Create("Tags", b.Tags)
+	cs.Create("IconID", b.IconID)
+	cs.Create("PlanID", b.PlanID)
+	cs.Create("Version", b.Version)
+	cs.Create("Switch", b.NICSetting.getConnectedSwitch())
+	cs.Create("IPAddresses", b.NICSetting.getIPAddresses())
+	for _, nic := range b.AdditionalNICSettings {
+		switchID, index := nic.getSwitchInfo()
+		cs.Create(fmt.Sprintf("AdditionalNICSettings[%d]", index), switchID)
+	}
+	b.planRouterSetting(cs, &iaas.VPCRouterSetting{})
+	return cs
+}
+
+func (b *Builder) planForUpdate(ctx context.Context, zone string) (*service.ChangeSet, error) {
+	vpcRouter, err := b.Client.Read(ctx, zone, b.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	isNeedShutdown, err := b.collectUpdateInfo(vpcRouter)
+	if err != nil {
+		return nil, err
+	}
+
+	cs := service.NewUpdateChangeSet(vpcRouter.ID)
+	cs.Update("Name", vpcRouter.Name, b.Name)
+	cs.Update("Description", vpcRouter.Description, b.Description)
+	cs.Update("Tags", vpcRouter.Tags, b.Tags)
+	cs.Update("IconID", vpcRouter.IconID, b.IconID)
+
+	// NICの切断/変更
+	for _, iface := range vpcRouter.Interfaces {
+		if iface.Index == 0 {
+			continue
+		}
+		field := fmt.Sprintf("AdditionalNICSettings[%d]", iface.Index)
+		newSwitchID := b.findAdditionalSwitchSettingByIndex(iface.Index)
+		if newSwitchID.IsEmpty() {
+			cs.Delete(field, iface.SwitchID)
+			continue
+		}
+		cs.Update(field, iface.SwitchID, newSwitchID)
+	}
+	// 追加されたNIC
+	for _, nic := range b.AdditionalNICSettings {
+		switchID, index := nic.getSwitchInfo()
+		if b.findInterfaceByIndex(vpcRouter, index) == nil {
+			cs.Create(fmt.Sprintf("AdditionalNICSettings[%d]", index), switchID)
+		}
+	}
+
+	current := vpcRouter.Settings
+	if current == nil {
+		current = &iaas.VPCRouterSetting{}
+	}
+	b.planRouterSetting(cs, current)
+
+	if isNeedShutdown {
+		cs.NeedShutdown()
+	}
+	return cs, nil
+}
+
+func (b *Builder) planRouterSetting(cs *service.ChangeSet, current *iaas.VPCRouterSetting) {
+	desired := b.RouterSetting
+	cs.Set("RouterSetting.VRID", current.VRID, desired.VRID)
+	cs.Set("RouterSetting.InternetConnectionEnabled", current.InternetConnectionEnabled, desired.InternetConnectionEnabled)
+	cs.Set("RouterSetting.Interfaces", current.Interfaces, b.getInterfaceSettings())
+	cs.Set("RouterSetting.StaticNAT", current.StaticNAT, desired.StaticNAT)
+	cs.Set("RouterSetting.PortForwarding", current.PortForwarding, desired.PortForwarding)
+	cs.Set("RouterSetting.Firewall", current.Firewall, desired.Firewall)
+	cs.Set("RouterSetting.DHCPServer", current.DHCPServer, desired.DHCPServer)
+	cs.Set("RouterSetting.DHCPStaticMapping", current.DHCPStaticMapping, desired.DHCPStaticMapping)
+	cs.Set("RouterSetting.DNSForwarding", current.DNSForwarding, desired.DNSForwarding)
+	cs.Set("RouterSetting.PPTPServer", current.PPTPServer, desired.PPTPServer)
+	cs.Set("RouterSetting.L2TPIPsecServer", current.L2TPIPsecServer, desired.L2TPIPsecServer)
+	cs.Set("RouterSetting.WireGuard", current.WireGuard, desired.WireGuard)
+	cs.Set("RouterSetting.RemoteAccessUsers", current.RemoteAccessUsers, desired.RemoteAccessUsers)
+	cs.Set("RouterSetting.SiteToSiteIPsecVPN", current.SiteToSiteIPsecVPN, desired.SiteToSiteIPsecVPN)
+	cs.Set("RouterSetting.StaticRoute", current.StaticRoute, desired.StaticRoute)
+	cs.Set("RouterSetting.SyslogHost", current.SyslogHost, desired.SyslogHost)
+	cs.Set("RouterSetting.ScheduledMaintenance", current.ScheduledMaintenance, desired.ScheduledMaintenance)
+}
@@ -0,0 +1,117 @@
+// Copyright 2022-2023 The sacloud/iaas-service-go Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package builder
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sacloud/iaas-api-go"
+	"github.com/sacloud/iaas-api-go/testutil"
+	"github.com/sacloud/iaas-api-go/types"
+	service "github.com/sacloud/iaas-service-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder_ChangeSet(t *testing.T) {
+	ctx := context.Background()
+	zone := testutil.TestZone()
+	caller := testutil.SingletonAPICaller()
+
+	swOp := iaas.NewSwitchOp(caller)
+	sw, err := swOp.Create(ctx, zone, &iaas.SwitchCreateRequest{Name: testutil.ResourceName("vpc-router-builder")})
+	require.NoError(t, err)
+	defer swOp.Delete(ctx, zone, sw.ID) //nolint:errcheck
+
+	builder := &Builder{
+		Zone:        zone,
+		Name:        testutil.ResourceName("vpc-router-builder"),
+		Description: "description",
+		Tags:        types.Tags{"tag1", "tag2"},
+		PlanID:      types.VPCRouterPlans.Standard,
+		Version:     1,
+		NICSetting:  &StandardNICSetting{},
+		RouterSetting: &RouterSetting{
+			InternetConnectionEnabled: types.StringTrue,
+		},
+		SetupOptions: getSetupOption(),
+		Client:       iaas.NewVPCRouterOp(caller),
+	}
+
+	t.Run("create", func(t *testing.T) {
+		cs, err := builder.ChangeSet(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionCreate, cs.Action)
+		require.True(t, cs.HasChanges())
+	})
+
+	created, err := builder.Build(ctx)
+	require.NoError(t, err)
+
+	vpcRouterOp := iaas.NewVPCRouterOp(caller)
+	defer vpcRouterOp.Delete(ctx, zone, created.ID) //nolint:errcheck
+
+	builder.ID = created.ID
+
+	t.Run("no changes", func(t *testing.T) {
+		cs, err := builder.ChangeSet(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionNone, cs.Action, "changes: %v", cs.Changes)
+		require.Equal(t, service.UpdateLevelNone, cs.UpdateLevel)
+	})
+
+	t.Run("simple update", func(t *testing.T) {
+		name := builder.Name
+		builder.Name = name + "-upd"
+		defer func() { builder.Name = name }()
+
+		cs, err := builder.ChangeSet(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionUpdate, cs.Action)
+		require.Equal(t, service.UpdateLevelSimple, cs.UpdateLevel)
+		require.Len(t, cs.Changes, 1)
+		require.Equal(t, "Name", cs.Changes[0].Field)
+	})
+
+	t.Run("add NIC", func(t *testing.T) {
+		builder.AdditionalNICSettings = []AdditionalNICSettingHolder{
+			&AdditionalStandardNICSetting{
+				SwitchID:       sw.ID,
+				IPAddress:      "192.168.0.1",
+				NetworkMaskLen: 24,
+				Index:          2,
+			},
+		}
+		defer func() { builder.AdditionalNICSettings = nil }()
+
+		cs, err := builder.ChangeSet(ctx)
+		require.NoError(t, err)
+		require.Equal(t, service.ChangeActionUpdate, cs.Action)
+		require.Equal(t, service.UpdateLevelNeedShutdown, cs.UpdateLevel)
+
+		// 変更内容の算出のみで更新されていないはず
+		vpcRouter, err := vpcRouterOp.Read(ctx, zone, created.ID)
+		require.NoError(t, err)
+		require.Len(t, vpcRouter.Interfaces, 1)
+	})
+
+	t.Run("plan change", func(t *testing.T) {
+		builder.PlanID = types.VPCRouterPlans.Premium
+		defer func() { builder.PlanID = types.VPCRouterPlans.Standard }()
+
+		_, err := builder.ChangeSet(ctx)
+		require.Error(t, err)
+	})
+}
@@ -18,15 +18,19 @@ import (
 	"context"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) CreateStandard(req *CreateStandardRequest) (*iaas.VPCRouter, error) {
 	return s.CreateStandardWithContext(context.Background(), req)
 }
 
-func (s *Service) CreateStandardWithContext(ctx context.Context, req *CreateStandardRequest) (*iaas.VPCRouter, error) {
+func (s *Service) CreateStandardWithContext(ctx context.Context, req *CreateStandardRequest) (_ *iaas.VPCRouter, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "CreateStandard", Resource: "VPCRouter", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "CreateStandard", Resource: "VPCRouter", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	return s.ApplyWithContext(ctx, req.ApplyRequest())
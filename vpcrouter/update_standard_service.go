@@ -16,23 +16,26 @@ package vpcrouter
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/sacloud/iaas-api-go"
+	service "github.com/sacloud/iaas-service-go"
 )
 
 func (s *Service) UpdateStandard(req *UpdateStandardRequest) (*iaas.VPCRouter, error) {
 	return s.UpdateStandardWithContext(context.Background(), req)
 }
 
-func (s *Service) UpdateStandardWithContext(ctx context.Context, req *UpdateStandardRequest) (*iaas.VPCRouter, error) {
+func (s *Service) UpdateStandardWithContext(ctx context.Context, req *UpdateStandardRequest) (_ *iaas.VPCRouter, err error) {
+	ctx, finish := service.StartOperation(ctx, s.caller, &service.Operation{Name: "UpdateStandard", Resource: "VPCRouter", Request: req})
+	defer func() { err = finish(err) }()
+
 	if err := req.Validate(); err != nil {
-		return nil, err
+		return nil, &service.Error{Op: "UpdateStandard", Resource: "VPCRouter", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	applyRequest, err := req.ApplyRequest(ctx, s.caller)
 	if err != nil {
-		return nil, fmt.Errorf("processing request parameter failed: %s", err)
+		return nil, &service.Error{Op: "UpdateStandard", Resource: "VPCRouter", Kind: service.ErrorKindValidation, Err: err}
 	}
 
 	return s.ApplyWithContext(ctx, applyRequest)